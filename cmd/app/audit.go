@@ -0,0 +1,75 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"crypto"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sigstore/fulcio/pkg/audit"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/spf13/cobra"
+)
+
+func newAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect audit logs written with serve --audit-log-path",
+	}
+	cmd.AddCommand(newAuditVerifyCmd())
+	return cmd
+}
+
+func newAuditVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify <audit log>",
+		Short: "Verify the hash chain and checkpoint signatures of an audit log",
+		Long: `Verify that no entry of an audit log was altered, removed or reordered,
+and that every checkpoint was signed by the key given with --public-key.
+Entries after the last checkpoint are only protected by the hash chain,
+and are reported as unsigned.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runAuditVerifyCmd,
+	}
+	cmd.Flags().String("public-key", "", "Path to the PEM-encoded public key of serve --audit-signing-key; if unset, the log must have no checkpoints")
+	return cmd
+}
+
+func runAuditVerifyCmd(cmd *cobra.Command, args []string) error {
+	var verifier signature.Verifier
+	if keyPath, _ := cmd.Flags().GetString("public-key"); keyPath != "" {
+		var err error
+		verifier, err = signature.LoadVerifierFromPEMFile(filepath.Clean(keyPath), crypto.SHA256)
+		if err != nil {
+			return fmt.Errorf("loading --public-key: %w", err)
+		}
+	}
+
+	f, err := os.Open(filepath.Clean(args[0]))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	summary, err := audit.Verify(f, verifier)
+	if err != nil {
+		return fmt.Errorf("verifying %s: %w", args[0], err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Verified %d decisions and %d checkpoints, %d decisions after the last checkpoint are unsigned\n",
+		summary.Decisions, summary.Checkpoints, summary.Unsigned)
+	return nil
+}
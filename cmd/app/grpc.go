@@ -22,6 +22,7 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"sync"
 	"syscall"
@@ -29,20 +30,20 @@ import (
 	"github.com/sigstore/sigstore/pkg/signature"
 
 	"github.com/fsnotify/fsnotify"
-	ctclient "github.com/google/certificate-transparency-go/client"
 	grpcmw "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sigstore/fulcio/pkg/ca"
-	"github.com/sigstore/fulcio/pkg/config"
+	"github.com/sigstore/fulcio/pkg/challenges"
+	"github.com/sigstore/fulcio/pkg/ctl"
 	gw "github.com/sigstore/fulcio/pkg/generated/protobuf"
 	gw_legacy "github.com/sigstore/fulcio/pkg/generated/protobuf/legacy"
-	"github.com/sigstore/fulcio/pkg/identity"
 	"github.com/sigstore/fulcio/pkg/log"
 	"github.com/sigstore/fulcio/pkg/server"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"goa.design/goa/v3/grpc/middleware"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -61,21 +62,6 @@ type grpcServer struct {
 	tlsCertWatcher     *fsnotify.Watcher
 }
 
-func PassFulcioConfigThruContext(cfg *config.FulcioConfig) grpc.UnaryServerInterceptor {
-	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		// For each request, infuse context with our snapshot of the FulcioConfig.
-		// TODO(mattmoor): Consider periodically (every minute?) refreshing the ConfigMap
-		// from disk, so that we don't need to cycle pods to pick up config updates.
-		// Alternately we could take advantage of Knative's configmap watcher.
-		ctx = config.With(ctx, cfg)
-		ctx, cancel := context.WithCancel(ctx)
-		defer cancel()
-
-		// Calls the inner handler
-		return handler(ctx, req)
-	}
-}
-
 type cachedTLSCert struct {
 	sync.RWMutex
 	certPath string
@@ -157,7 +143,7 @@ func (c *cachedTLSCert) GRPCCreds() grpc.ServerOption {
 	}))
 }
 
-func createGRPCServer(cfg *config.FulcioConfig, ctClient *ctclient.LogClient, baseca ca.CertificateAuthority, algorithmRegistry *signature.AlgorithmRegistryConfig, ip identity.IssuerPool) (*grpcServer, error) {
+func createGRPCServer(cfgs *server.ConfigReloader, ctLogs *ctl.MultiLog, baseca ca.CertificateAuthority, algorithmRegistry *signature.AlgorithmRegistryConfig, extraOpts ...server.GRPCCAServerOption) (*grpcServer, error) {
 	logger, opts := log.SetupGRPCLogging()
 
 	serverOpts := []grpc.ServerOption{
@@ -166,13 +152,14 @@ func createGRPCServer(cfg *config.FulcioConfig, ctClient *ctclient.LogClient, ba
 				grpc_recovery.UnaryServerInterceptor(grpc_recovery.WithRecoveryHandlerContext(panicRecoveryHandler)), // recovers from per-transaction panics elegantly, so put it first
 				middleware.UnaryRequestID(middleware.UseXRequestIDMetadataOption(true), middleware.XRequestMetadataLimitOption(128)),
 				grpc_zap.UnaryServerInterceptor(logger, opts...),
-				PassFulcioConfigThruContext(cfg),
+				cfgs.UnaryInterceptor(),
 				grpc_prometheus.UnaryServerInterceptor,
 			)),
 		grpc.KeepaliveParams(keepalive.ServerParameters{
 			MaxConnectionIdle: viper.GetDuration("idle-connection-timeout"),
 		}),
 		grpc.MaxRecvMsgSize(int(maxMsgSize)),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 	}
 
 	var tlsCertWatcher *fsnotify.Watcher
@@ -188,7 +175,11 @@ func createGRPCServer(cfg *config.FulcioConfig, ctClient *ctclient.LogClient, ba
 
 	myServer := grpc.NewServer(serverOpts...)
 
-	grpcCAServer := server.NewGRPCCAServer(ctClient, baseca, algorithmRegistry, ip)
+	caServerOpts, err := caServerOptions(cfgs)
+	if err != nil {
+		return nil, err
+	}
+	grpcCAServer := server.NewGRPCCAServer(ctLogs, baseca, algorithmRegistry, cfgs.IssuerPool(), append(caServerOpts, extraOpts...)...)
 
 	health.RegisterHealthServer(myServer, grpcCAServer)
 	// Register your gRPC service implementations.
@@ -198,6 +189,31 @@ func createGRPCServer(cfg *config.FulcioConfig, ctClient *ctclient.LogClient, ba
 	return &grpcServer{myServer, grpcServerEndpoint, grpcCAServer, tlsCertWatcher}, nil
 }
 
+// caServerOptions returns the options for the CA service configured through
+// flags.
+func caServerOptions(cfgs *server.ConfigReloader) ([]server.GRPCCAServerOption, error) {
+	opts := []server.GRPCCAServerOption{server.WithConfigReloader(cfgs)}
+
+	ttl := viper.GetDuration("challenge-ttl")
+	var nonces *challenges.NonceIssuer
+	var err error
+	if keyFile := viper.GetString("challenge-key-file"); keyFile != "" {
+		key, rerr := os.ReadFile(filepath.Clean(keyFile))
+		if rerr != nil {
+			return nil, fmt.Errorf("reading challenge key file: %w", rerr)
+		}
+		nonces, err = challenges.NewNonceIssuer(key, ttl)
+	} else {
+		nonces, err = challenges.NewEphemeralNonceIssuer(ttl)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating challenge nonce issuer: %w", err)
+	}
+	opts = append(opts, server.WithNonceIssuer(nonces))
+
+	return opts, nil
+}
+
 func (g *grpcServer) setupPrometheus(reg *prometheus.Registry) {
 	grpcMetrics := grpc_prometheus.DefaultServerMetrics
 	grpcMetrics.EnableHandlingTimeHistogram()
@@ -271,7 +287,7 @@ func (g *grpcServer) ExposesGRPCTLS() bool {
 	return viper.IsSet("grpc-tls-certificate") && viper.IsSet("grpc-tls-key")
 }
 
-func createLegacyGRPCServer(cfg *config.FulcioConfig, unixDomainSocket string, v2Server gw.CAServer) (*grpcServer, error) {
+func createLegacyGRPCServer(cfgs *server.ConfigReloader, unixDomainSocket string, v2Server gw.CAServer) (*grpcServer, error) {
 	logger, opts := log.SetupGRPCLogging()
 
 	myServer := grpc.NewServer(grpc.UnaryInterceptor(
@@ -279,10 +295,11 @@ func createLegacyGRPCServer(cfg *config.FulcioConfig, unixDomainSocket string, v
 			grpc_recovery.UnaryServerInterceptor(grpc_recovery.WithRecoveryHandlerContext(panicRecoveryHandler)), // recovers from per-transaction panics elegantly, so put it first
 			middleware.UnaryRequestID(middleware.UseXRequestIDMetadataOption(true), middleware.XRequestMetadataLimitOption(128)),
 			grpc_zap.UnaryServerInterceptor(logger, opts...),
-			PassFulcioConfigThruContext(cfg),
+			cfgs.UnaryInterceptor(),
 			grpc_prometheus.UnaryServerInterceptor,
 		)),
-		grpc.MaxRecvMsgSize(int(maxMsgSize)))
+		grpc.MaxRecvMsgSize(int(maxMsgSize)),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()))
 
 	legacyGRPCCAServer := server.NewLegacyGRPCCAServer(v2Server)
 
@@ -18,32 +18,35 @@ package app
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"strconv"
-	"strings"
 	"sync"
 	"syscall"
 	"time"
 
+	ct "github.com/google/certificate-transparency-go"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/cors"
+	"github.com/sigstore/fulcio/pkg/ctl"
 	gw "github.com/sigstore/fulcio/pkg/generated/protobuf"
 	legacy_gw "github.com/sigstore/fulcio/pkg/generated/protobuf/legacy"
 	"github.com/sigstore/fulcio/pkg/log"
 	"github.com/sigstore/fulcio/pkg/server"
+	"github.com/sigstore/fulcio/pkg/tracing"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	health "google.golang.org/grpc/health/grpc_health_v1"
-	"google.golang.org/grpc/metadata"
-	"google.golang.org/protobuf/proto"
 )
 
 type httpServer struct {
@@ -51,13 +54,12 @@ type httpServer struct {
 	httpServerEndpoint string
 }
 
-func extractOIDCTokenFromAuthHeader(_ context.Context, req *http.Request) metadata.MD {
-	token := strings.Replace(req.Header.Get("Authorization"), "Bearer ", "", 1)
-	return metadata.Pairs(server.MetadataOIDCTokenKey, token)
-}
+// ctLogDebugPath serves the key and entries of the in-memory CT log, if one
+// is configured.
+const ctLogDebugPath = "/debug/ctlog"
 
-func createHTTPServer(ctx context.Context, serverEndpoint string, grpcServer, legacyGRPCServer *grpcServer) httpServer {
-	opts := []grpc.DialOption{}
+func createHTTPServer(ctx context.Context, serverEndpoint string, grpcServer, legacyGRPCServer *grpcServer, ctLog *ctl.MemoryLog) httpServer {
+	opts := []grpc.DialOption{grpc.WithStatsHandler(otelgrpc.NewClientHandler())}
 	if grpcServer.ExposesGRPCTLS() {
 		/* #nosec G402 */ // InsecureSkipVerify is only used for the HTTP server to call the TLS-enabled grpc endpoint.
 		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})))
@@ -76,9 +78,18 @@ func createHTTPServer(ctx context.Context, serverEndpoint string, grpcServer, le
 		log.Logger.Fatal(err)
 	}
 
-	mux := runtime.NewServeMux(runtime.WithMetadata(extractOIDCTokenFromAuthHeader),
-		runtime.WithForwardResponseOption(setResponseCodeModifier),
+	mux := runtime.NewServeMux(runtime.WithMetadata(server.ExtractOIDCTokenFromAuthHeader),
+		runtime.WithForwardResponseOption(server.SetResponseCodeModifier),
+		runtime.WithMiddlewares(traceHTTPRequest),
 		runtime.WithHealthzEndpoint(health.NewHealthClient(cc)))
+	if err := mux.HandlePath(http.MethodGet, "/readyz", readyz(health.NewHealthClient(cc))); err != nil {
+		log.Logger.Fatal(err)
+	}
+	if ctLog != nil {
+		if err := mux.HandlePath(http.MethodGet, ctLogDebugPath, ctLogDebug(ctLog)); err != nil {
+			log.Logger.Fatal(err)
+		}
+	}
 
 	if err := gw.RegisterCAHandlerFromEndpoint(ctx, mux, grpcServerEndpoint, opts); err != nil {
 		log.Logger.Fatal(err)
@@ -87,7 +98,7 @@ func createHTTPServer(ctx context.Context, serverEndpoint string, grpcServer, le
 	if legacyGRPCServer != nil {
 		endpoint := fmt.Sprintf("unix:%v", legacyGRPCServer.grpcServerEndpoint)
 		// we are connecting over a unix domain socket, therefore we won't ever need TLS
-		unixDomainSocketOpts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+		unixDomainSocketOpts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithStatsHandler(otelgrpc.NewClientHandler())}
 		if err := legacy_gw.RegisterCAHandlerFromEndpoint(ctx, mux, endpoint, unixDomainSocketOpts); err != nil {
 			log.Logger.Fatal(err)
 		}
@@ -115,6 +126,17 @@ func createHTTPServer(ctx context.Context, serverEndpoint string, grpcServer, le
 	return httpServer{&api, serverEndpoint}
 }
 
+// traceHTTPRequest traces requests to the gateway in a span named after
+// the route, which the gateway propagates to the gRPC server.
+func traceHTTPRequest(next runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		pattern, _ := runtime.HTTPPathPattern(r.Context())
+		r, span := tracing.StartHTTP(r, r.Method+" "+pattern)
+		defer span.End()
+		next(w, r, pathParams)
+	}
+}
+
 func (h httpServer) startListener(wg *sync.WaitGroup) {
 	log.Logger.Infof("listening on http at %s", h.httpServerEndpoint)
 
@@ -144,36 +166,82 @@ func (h httpServer) startListener(wg *sync.WaitGroup) {
 	}()
 }
 
-func setResponseCodeModifier(ctx context.Context, w http.ResponseWriter, _ proto.Message) error {
-	md, ok := runtime.ServerMetadataFromContext(ctx)
-	if !ok {
-		return nil
-	}
-
-	// set SCT if present ahead of modifying response code
-	if vals := md.HeaderMD.Get(server.SCTMetadataKey); len(vals) > 0 {
-		delete(md.HeaderMD, server.SCTMetadataKey)
-		delete(w.Header(), "Grpc-Metadata-sct")
-		w.Header().Set("SCT", vals[0])
-	}
-
-	// strip all GRPC response headers
-	for headerKey := range w.Header() {
-		if strings.HasPrefix(headerKey, "Grpc-") {
-			delete(w.Header(), headerKey)
+// readyz returns a handler that responds 200 if the server is ready to issue
+// certificates and 503 otherwise, with the status of each health check as
+// JSON. Unlike /healthz, it fails while a component the server depends on is
+// unhealthy.
+func readyz(client health.HealthClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		resp, err := client.List(r.Context(), &health.HealthListRequest{})
+		if err != nil {
+			log.ContextLogger(r.Context()).Errorf("listing health statuses: %v", err)
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+		statuses := map[string]string{}
+		for service, st := range resp.GetStatuses() {
+			if service != "" {
+				statuses[service] = st.GetStatus().String()
+			}
 		}
+		code := http.StatusOK
+		if resp.GetStatuses()[server.ReadinessService].GetStatus() != health.HealthCheckResponse_SERVING {
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(statuses)
 	}
+}
 
-	// set http status code
-	if vals := md.HeaderMD.Get(server.HTTPResponseCodeMetadataKey); len(vals) > 0 {
-		code, err := strconv.Atoi(vals[0])
+// ctLogDebugEntry is an entry of the in-memory CT log as served by ctLogDebug.
+type ctLogDebugEntry struct {
+	Index     uint64 `json:"index"`
+	Timestamp uint64 `json:"timestamp"`
+	EntryType string `json:"entryType"`
+	// Chain holds the PEM-encoded submitted chain, leaf first.
+	Chain []string `json:"chain"`
+	// SCT is the SCT of the entry, as returned by add-chain.
+	SCT *ct.AddChainResponse `json:"sct"`
+}
+
+// ctLogDebug returns a handler that responds with the ID, PEM-encoded public
+// key and entries of the in-memory CT log l as JSON, so that SCTs issued in
+// development can be verified without a log to query.
+func ctLogDebug(l *ctl.MemoryLog) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		pub, err := cryptoutils.MarshalPublicKeyToPEM(l.PublicKey())
 		if err != nil {
-			return err
+			log.ContextLogger(r.Context()).Errorf("marshaling CT log public key: %v", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
 		}
-		// delete the headers to not expose any grpc-metadata in http response
-		delete(md.HeaderMD, server.HTTPResponseCodeMetadataKey)
-		w.WriteHeader(code)
+		entries := []ctLogDebugEntry{}
+		for _, e := range l.Entries() {
+			sct, err := ctl.ToAddChainResponse(e.SCT)
+			if err != nil {
+				log.ContextLogger(r.Context()).Errorf("marshaling SCT of CT log entry %d: %v", e.Index, err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			chain := make([]string, 0, len(e.Chain))
+			for _, c := range e.Chain {
+				chain = append(chain, string(pem.EncodeToMemory(&pem.Block{Type: string(cryptoutils.CertificatePEMType), Bytes: c.Data})))
+			}
+			entries = append(entries, ctLogDebugEntry{
+				Index:     e.Index,
+				Timestamp: e.Leaf.TimestampedEntry.Timestamp,
+				EntryType: e.Leaf.TimestampedEntry.EntryType.String(),
+				Chain:     chain,
+				SCT:       sct,
+			})
+		}
+		logID := l.LogID()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"logId":     logID[:],
+			"publicKey": string(pub),
+			"entries":   entries,
+		})
 	}
-
-	return nil
 }
@@ -18,11 +18,16 @@ package app
 import (
 	"context"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -30,9 +35,15 @@ import (
 	"sync"
 	"testing"
 
+	ct "github.com/google/certificate-transparency-go"
+	cttls "github.com/google/certificate-transparency-go/tls"
 	"github.com/sigstore/fulcio/pkg/ca"
+	"github.com/sigstore/fulcio/pkg/ctl"
 	"github.com/sigstore/fulcio/pkg/identity"
+	"github.com/sigstore/fulcio/pkg/server"
+	"github.com/sigstore/fulcio/pkg/test"
 	v1 "github.com/sigstore/protobuf-specs/gen/pb-go/common/v1"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
 	"github.com/sigstore/sigstore/pkg/signature"
 	"github.com/spf13/viper"
 
@@ -53,7 +64,7 @@ func setupHTTPServer(t *testing.T) (httpServer, string) {
 	if err != nil {
 		t.Error(err)
 	}
-	grpcServer, err := createGRPCServer(nil, nil, &TrivialCertificateAuthority{}, algorithmRegistry, nil)
+	grpcServer, err := createGRPCServer(server.NewConfigReloader("", nil), nil, &TrivialCertificateAuthority{}, algorithmRegistry)
 	if err != nil {
 		t.Error(err)
 	}
@@ -70,7 +81,7 @@ func setupHTTPServer(t *testing.T) (httpServer, string) {
 	}
 
 	httpHost := httpListen.Addr().String()
-	httpServer := createHTTPServer(context.Background(), httpHost, grpcServer, nil)
+	httpServer := createHTTPServer(context.Background(), httpHost, grpcServer, nil, nil)
 	go func() {
 		_ = httpServer.Serve(httpListen)
 		grpcServer.GracefulStop()
@@ -103,7 +114,7 @@ func setupHTTPServerWithGRPCTLS(t *testing.T) (httpServer, string) {
 	if err != nil {
 		t.Error(err)
 	}
-	grpcServer, err := createGRPCServer(nil, nil, &TrivialCertificateAuthority{}, algorithmRegistry, nil)
+	grpcServer, err := createGRPCServer(server.NewConfigReloader("", nil), nil, &TrivialCertificateAuthority{}, algorithmRegistry)
 	if err != nil {
 		t.Error(err)
 	}
@@ -119,14 +130,14 @@ func setupHTTPServerWithGRPCTLS(t *testing.T) (httpServer, string) {
 	if err != nil {
 		t.Error(err)
 	}
-	legacyGRPCServer, err := createLegacyGRPCServer(nil, LegacyUnixDomainSocket, grpcServer.caService)
+	legacyGRPCServer, err := createLegacyGRPCServer(server.NewConfigReloader("", nil), LegacyUnixDomainSocket, grpcServer.caService)
 	if err != nil {
 		t.Fatal(err)
 	}
 	legacyGRPCServer.startUnixListener()
 
 	httpHost := httpListen.Addr().String()
-	httpServer := createHTTPServer(context.Background(), httpHost, grpcServer, legacyGRPCServer)
+	httpServer := createHTTPServer(context.Background(), httpHost, grpcServer, legacyGRPCServer, nil)
 	go func() {
 		_ = httpServer.Serve(httpListen)
 		grpcServer.GracefulStop()
@@ -206,3 +217,68 @@ func (tca *TrivialCertificateAuthority) TrustBundle(_ context.Context) ([][]*x50
 func (tca *TrivialCertificateAuthority) Close() error {
 	return nil
 }
+
+func TestCTLogDebug(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := ctl.NewMemoryLog(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, _, err := test.GenerateRootCA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.AddChain(context.Background(), []ct.ASN1Cert{{Data: root.Raw}}); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	ctLogDebug(l)(w, httptest.NewRequest(http.MethodGet, ctLogDebugPath, nil), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", w.Code)
+	}
+	var resp struct {
+		LogID     []byte            `json:"logId"`
+		PublicKey string            `json:"publicKey"`
+		Entries   []ctLogDebugEntry `json:"entries"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if logID := l.LogID(); string(resp.LogID) != string(logID[:]) {
+		t.Errorf("unexpected log ID %x", resp.LogID)
+	}
+	if len(resp.Entries) != 1 || resp.Entries[0].EntryType != ct.X509LogEntryType.String() || len(resp.Entries[0].Chain) != 1 {
+		t.Fatalf("unexpected entries %+v", resp.Entries)
+	}
+
+	// The served key and chain are enough to verify the served SCT
+	pub, err := cryptoutils.UnmarshalPEMToPublicKey([]byte(resp.PublicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := ct.NewSignatureVerifier(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := resp.Entries[0]
+	certs, err := cryptoutils.UnmarshalCertificatesFromPEM([]byte(e.Chain[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := ct.MerkleTreeLeafFromRawChain([]ct.ASN1Cert{{Data: certs[0].Raw}}, ct.X509LogEntryType, e.SCT.Timestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sig ct.DigitallySigned
+	if _, err := cttls.Unmarshal(e.SCT.Signature, &sig); err != nil {
+		t.Fatal(err)
+	}
+	sct := ct.SignedCertificateTimestamp{SCTVersion: ct.V1, LogID: ct.LogID{KeyID: l.LogID()}, Timestamp: e.SCT.Timestamp, Signature: sig}
+	if err := verifier.VerifySCTSignature(sct, ct.LogEntry{Leaf: *leaf}); err != nil {
+		t.Errorf("VerifySCTSignature() = %v", err)
+	}
+}
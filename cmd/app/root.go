@@ -43,4 +43,5 @@ func Execute(ctx context.Context) {
 func init() {
 	rootCmd.AddCommand(newCreateCACmd())
 	rootCmd.AddCommand(newServeCmd())
+	rootCmd.AddCommand(newAuditCmd())
 }
@@ -18,16 +18,22 @@ package app
 import (
 	"bytes"
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
 	"flag"
 	"fmt"
+	"maps"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"syscall"
@@ -47,24 +53,34 @@ import (
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sigstore/fulcio/pkg/audit"
 	certauth "github.com/sigstore/fulcio/pkg/ca"
+	"github.com/sigstore/fulcio/pkg/ca/awspca"
 	"github.com/sigstore/fulcio/pkg/ca/ephemeralca"
+	"github.com/sigstore/fulcio/pkg/ca/failoverca"
 	"github.com/sigstore/fulcio/pkg/ca/fileca"
 	googlecav1 "github.com/sigstore/fulcio/pkg/ca/googleca/v1"
 	"github.com/sigstore/fulcio/pkg/ca/kmsca"
 	"github.com/sigstore/fulcio/pkg/ca/pkcs11ca"
+	"github.com/sigstore/fulcio/pkg/ca/routingca"
 	"github.com/sigstore/fulcio/pkg/ca/tinkca"
+	"github.com/sigstore/fulcio/pkg/challenges"
 	"github.com/sigstore/fulcio/pkg/config"
+	"github.com/sigstore/fulcio/pkg/ctl"
 	"github.com/sigstore/fulcio/pkg/generated/protobuf"
 	"github.com/sigstore/fulcio/pkg/generated/protobuf/legacy"
-	"github.com/sigstore/fulcio/pkg/identity"
+	healthmon "github.com/sigstore/fulcio/pkg/health"
+	"github.com/sigstore/fulcio/pkg/ledger"
 	"github.com/sigstore/fulcio/pkg/log"
+	"github.com/sigstore/fulcio/pkg/notify"
 	"github.com/sigstore/fulcio/pkg/server"
+	"github.com/sigstore/fulcio/pkg/tracing"
 	"github.com/sigstore/sigstore/pkg/cryptoutils"
 	"github.com/sigstore/sigstore/pkg/signature/kms/gcp"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"goa.design/goa/v3/grpc/middleware"
 	"google.golang.org/api/option"
@@ -77,6 +93,9 @@ import (
 const (
 	serveCmdEnvPrefix        = "FULCIO_SERVE"
 	defaultConfigPath string = "/etc/fulcio-config/config.yaml"
+	// inMemoryCTLogURL is the --ct-log-url of a CT log embedded in the
+	// server, for development.
+	inMemoryCTLogURL = "inmemory://"
 )
 
 var serveCmdConfigFilePath string
@@ -91,13 +110,45 @@ func newServeCmd() *cobra.Command {
 
 	cmd.Flags().StringVarP(&serveCmdConfigFilePath, "config", "c", "", "config file containing all settings")
 	cmd.Flags().String("log_type", "dev", "logger type to use (dev/prod)")
-	cmd.Flags().String("ca", "", "googleca | tinkca | pkcs11ca | fileca | kmsca | ephemeralca (for testing)")
+	cmd.Flags().String("ca", "", "googleca | awspca | tinkca | pkcs11ca | fileca | kmsca | ephemeralca (for testing)")
 	cmd.Flags().String("aws-hsm-root-ca-path", "", "Path to root CA on disk (only used with AWS HSM)")
 	cmd.Flags().String("gcp_private_ca_parent", "", "private ca parent: projects/<project>/locations/<location>/caPools/<caPool> (only used with --ca googleca)"+
 		"Optionally specify /certificateAuthorities/<caID>, which will bypass CA pool load balancing.")
+	cmd.Flags().String("aws-pca-arn", "", "AWS Private CA certificate authority ARN: arn:aws:acm-pca:<region>:<account>:certificate-authority/<id> (only used with --ca awspca)")
 	cmd.Flags().String("hsm-caroot-id", "", "HSM ID for Root CA (only used with --ca pkcs11ca)")
-	cmd.Flags().String("ct-log-url", "http://localhost:6962/test", "host and path (with log prefix at the end) to the ct log")
-	cmd.Flags().String("ct-log-public-key-path", "", "Path to a PEM-encoded public key of the CT log, used to verify SCTs")
+	cmd.Flags().StringSlice("ct-log-url", []string{"http://localhost:6962/test"}, "host and path (with log prefix at the end) to the ct log; repeat to submit to several logs. "+inMemoryCTLogURL+" embeds a CT log in the server that keeps its entries in memory (for development)")
+	cmd.Flags().String("ct-log-inmemory-key-path", "", "Path to an unencrypted PEM-encoded ECDSA or RSA private key that the in-memory CT log signs SCTs with; if unset, a random key is generated on startup (only used with --ct-log-url="+inMemoryCTLogURL+")")
+	cmd.Flags().StringSlice("ct-log-public-key-path", nil, "Path to a PEM-encoded public key of the CT log, used to verify SCTs; if set, one per --ct-log-url, in the same order")
+	cmd.Flags().StringSlice("ct-log-type", nil, "API of the CT log, rfc6962 (default) or static-ct; if set, one per --ct-log-url, in the same order")
+	cmd.Flags().StringSlice("ct-log-monitoring-url", nil, "URL prefix that a static-ct CT log serves its checkpoint from, defaulting to its --ct-log-url; if set, one per --ct-log-url, in the same order")
+	cmd.Flags().StringSlice("ct-log-origin", nil, "Origin line that the checkpoints of a static-ct CT log must have; if unset, any origin is accepted; if set, one per --ct-log-url, in the same order")
+	cmd.Flags().Int("ct-log-quorum", 1, "Number of CT logs that must return an SCT before a certificate is issued")
+	cmd.Flags().Duration("ct-log-deadline", ctl.DefaultDeadline, "How long to wait for SCTs from CT logs beyond the quorum")
+	cmd.Flags().Int("ct-log-retries", ctl.DefaultRetries, "Number of times a failed CT log submission is retried")
+	cmd.Flags().Duration("ct-log-retry-backoff", ctl.DefaultInitialBackoff, "Delay before retrying a failed CT log submission, doubling with each retry")
+	cmd.Flags().Duration("ct-log-retry-max-backoff", ctl.DefaultMaxBackoff, "Maximum delay between retries of a failed CT log submission")
+	cmd.Flags().Duration("ct-log-attempt-timeout", ctl.DefaultAttemptTimeout, "How long each CT log submission attempt may take, or 0 for no timeout")
+	cmd.Flags().Int("ct-log-circuit-breaker-threshold", 0, "Number of consecutive failed submissions after which a CT log is skipped, or 0 to never skip a CT log")
+	cmd.Flags().Duration("ct-log-circuit-breaker-cooldown", ctl.DefaultCircuitBreakerCooldown, "How long a failing CT log is skipped before it is tried again (only used with --ct-log-circuit-breaker-threshold)")
+	cmd.Flags().String("ct-log-fallback-queue-dir", "", "Directory to queue certificates in while CT logs are unavailable; if set, such certificates are issued without an SCT and submitted to the CT logs once they recover")
+	cmd.Flags().Duration("ct-log-fallback-retry-interval", ctl.DefaultQueueInterval, "How often certificates queued in --ct-log-fallback-queue-dir are submitted to the CT logs")
+	cmd.Flags().String("ledger-path", "", "Path to a file in which to record issued certificates; if set, certificates are not issued unless they are recorded")
+	cmd.Flags().String("ledger-admin-issuer", "", "URL of the OIDC issuer of the identity tokens of issuance ledger administrators (only used with --ledger-path)")
+	cmd.Flags().StringSlice("ledger-admins", nil, "Identities, such as email addresses, allowed to query the issuance ledger (only used with --ledger-path)")
+	cmd.Flags().String("audit-log-path", "", "Path to a JSON Lines file in which to record the outcome of every certificate request; if set, certificates are not issued unless they are recorded")
+	cmd.Flags().String("audit-signing-key", "", "Path to an unencrypted PEM-encoded private key used to sign checkpoints of the audit log (only used with --audit-log-path)")
+	cmd.Flags().Int("audit-checkpoint-every", audit.DefaultCheckpointEvery, "Number of audit log entries after which a checkpoint is signed (only used with --audit-signing-key)")
+	cmd.Flags().Duration("audit-checkpoint-interval", audit.DefaultCheckpointInterval, "Time after the last checkpoint at which the next audit log entry is followed by a checkpoint (only used with --audit-signing-key)")
+	cmd.Flags().String("notify-source", notify.DefaultSource, "CloudEvents source of the issuance events posted to the notify-endpoints configured in --config")
+	cmd.Flags().Int("notify-queue-size", notify.DefaultQueueSize, "Number of issuance events held for each notification endpoint before further events are dropped")
+	cmd.Flags().Int("notify-retries", notify.DefaultRetries, "Number of times a failed delivery to a notification endpoint is retried")
+	cmd.Flags().Duration("notify-timeout", notify.DefaultAttemptTimeout, "How long each delivery attempt to a notification endpoint may take")
+	cmd.Flags().String("tracing-exporter", tracing.ExporterNone, "Where to export OpenTelemetry traces: none, otlp or stdout")
+	cmd.Flags().String("tracing-otlp-endpoint", "", "Host and port of the OTLP collector traces are exported to over gRPC; defaults to OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4317 (only used with --tracing-exporter=otlp)")
+	cmd.Flags().Bool("tracing-otlp-insecure", false, "Connect to the OTLP collector without TLS (only used with --tracing-exporter=otlp)")
+	cmd.Flags().Float64("tracing-sample-ratio", 1, "Fraction of requests traced, unless the caller propagates a sampling decision (only used with --tracing-exporter)")
+	cmd.Flags().Duration("health-check-interval", healthmon.DefaultInterval, "How often the CA, CT logs, config and OIDC issuer discovery are checked for the gRPC health service and /readyz")
+	cmd.Flags().Duration("health-check-timeout", healthmon.DefaultTimeout, "How long each health check may take before it fails")
 	cmd.Flags().String("config-path", defaultConfigPath, "path to fulcio config yaml")
 	cmd.Flags().String("pkcs11-config-path", "config/crypto11.conf", "path to fulcio pkcs11 config file")
 	// RHTAS FIPS - DO NOT REMOVE
@@ -107,6 +158,10 @@ func newServeCmd() *cobra.Command {
 	cmd.Flags().String("fileca-key-passwd", "", "Password to decrypt CA private key (optional; omit for unencrypted keys)")
 	// ========================================
 	cmd.Flags().Bool("fileca-watch", true, "Watch filesystem for updates")
+	cmd.Flags().String("fileca-dir", "", "Path to a directory of CA key pairs to rotate between, used instead of --fileca-cert and --fileca-key")
+	cmd.Flags().StringSlice("ca-failover", nil, "Names of backends configured under ca-backends to fall back to, in order, when the --ca backend fails")
+	cmd.Flags().Int("ca-failover-threshold", failoverca.DefaultFailureThreshold, "Number of consecutive failures after which a CA backend is skipped (only used with --ca-failover)")
+	cmd.Flags().Duration("ca-failover-cooldown", failoverca.DefaultCooldown, "How long a failing CA backend is skipped before it is tried again (only used with --ca-failover)")
 	cmd.Flags().String("kms-resource", "", "KMS key resource path. Must be prefixed with awskms://, azurekms://, gcpkms://, or hashivault://")
 	cmd.Flags().String("kms-cert-chain-path", "", "Path to PEM-encoded CA certificate chain for KMS-backed CA")
 	cmd.Flags().Uint("gcp-kms-retries", 0, "Number of retries for GCP KMS requests")
@@ -134,6 +189,8 @@ func newServeCmd() *cobra.Command {
 		v1.PublicKeyDetails_PKIX_RSA_PKCS1V15_4096_SHA256,
 		v1.PublicKeyDetails_PKIX_ED25519,
 	}), "the list of allowed client signing algorithms")
+	cmd.Flags().String("challenge-key-file", "", "Path to a file holding a secret of at least 32 bytes used to sign proof of possession challenges; must be shared by all replicas. If unset, a random per-process key is used")
+	cmd.Flags().Duration("challenge-ttl", challenges.DefaultNonceTTL, "How long a proof of possession challenge is accepted after it is issued")
 
 	// convert "http-host" flag to "host" and "http-port" flag to be "port"
 	cmd.Flags().SetNormalizeFunc(func(_ *pflag.FlagSet, name string) pflag.NormalizedName {
@@ -179,59 +236,30 @@ func runServeCmd(cmd *cobra.Command, args []string) { //nolint: revive
 	viper.SetEnvPrefix(serveCmdEnvPrefix)
 	viper.AutomaticEnv()
 
-	switch viper.GetString("ca") {
-	case "":
-		log.Logger.Fatal("required flag \"ca\" not set")
-
-	case "pkcs11ca":
-		if !viper.IsSet("hsm-caroot-id") {
-			log.Logger.Fatal("hsm-caroot-id must be set when using pkcs11ca")
-		}
-
-	case "googleca":
-		if !viper.IsSet("gcp_private_ca_parent") {
-			log.Logger.Fatal("gcp_private_ca_parent must be set when using googleca")
-		}
-		if viper.IsSet("gcp_private_ca_version") {
-			// There's a MarkDeprecated function in cobra/pflags, but it doesn't use log.Logger
-			log.Logger.Warn("gcp_private_ca_version is deprecated and will soon be removed; please remove it")
-		}
-	// RHTAS FIPS - DO NOT REMOVE
-	// ========================================
-	case "fileca":
-		if !viper.IsSet("fileca-cert") {
-			log.Logger.Fatal("fileca-cert must be set to certificate path when using fileca")
-		}
-		if !viper.IsSet("fileca-key") {
-			log.Logger.Fatal("fileca-key must be set to private key path when using fileca")
-		}
-	// ========================================
-	case "kmsca":
-		if !viper.IsSet("kms-resource") {
-			log.Logger.Fatal("kms-resource must be set when using kmsca")
-		}
-		if !viper.IsSet("kms-cert-chain-path") {
-			log.Logger.Fatal("kms-cert-chain-path must be set when using kmsca")
-		}
-	case "tinkca":
-		if !viper.IsSet("tink-kms-resource") {
-			log.Logger.Fatal("tink-kms-resource must be set when using tinkca")
-		}
-		if !viper.IsSet("tink-cert-chain-path") {
-			log.Logger.Fatal("tink-cert-chain-path must be set when using tinkca")
-		}
-		if !viper.IsSet("tink-keyset-path") {
-			log.Logger.Fatal("tink-keyset-path must be set when using tinkca")
-		}
-	case "ephemeralca":
-		// this is a no-op since this is a self-signed in-memory CA for testing
-	default:
-		log.Logger.Fatalf("--ca=%s is not a valid selection. Try: pkcs11ca, googleca, fileca, or ephemeralca", viper.GetString("ca"))
+	if err := checkCAConfig(viper.GetViper()); err != nil {
+		log.Logger.Fatal(err)
 	}
 
 	// Setup the logger to dev/prod
 	log.ConfigureLogger(viper.GetString("log_type"))
 
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:     viper.GetString("tracing-exporter"),
+		OTLPEndpoint: viper.GetString("tracing-otlp-endpoint"),
+		OTLPInsecure: viper.GetBool("tracing-otlp-insecure"),
+		SampleRatio:  viper.GetFloat64("tracing-sample-ratio"),
+	})
+	if err != nil {
+		log.Logger.Fatal(err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Logger.Warnf("error flushing traces: %v", err)
+		}
+	}()
+
 	algorithmStrings := viper.GetStringSlice("client-signing-algorithms")
 	var algorithmConfig []v1.PublicKeyDetails
 	for _, s := range algorithmStrings {
@@ -261,100 +289,77 @@ func runServeCmd(cmd *cobra.Command, args []string) { //nolint: revive
 	if err != nil {
 		log.Logger.Fatalf("error loading --config-path=%s: %v", cp, err)
 	}
-
-	var baseca certauth.CertificateAuthority
-	switch viper.GetString("ca") {
-	case "googleca":
-		baseca, err = googlecav1.NewCertAuthorityService(cmd.Context(), viper.GetString("gcp_private_ca_parent"))
-	case "pkcs11ca":
-		params := pkcs11ca.Params{
-			ConfigPath: viper.GetString("pkcs11-config-path"),
-			RootID:     viper.GetString("hsm-caroot-id"),
-		}
-		if viper.IsSet("aws-hsm-root-ca-path") {
-			path := viper.GetString("aws-hsm-root-ca-path")
-			params.CAPath = &path
+	checkCAs := checkIssuerCAs(viper.GetViper())
+	if err := checkCAs(cfg); err != nil {
+		log.Logger.Fatalf("error loading --config-path=%s: %v", cp, err)
+	}
+	cfgs := server.NewConfigReloader(cp, cfg, server.WithConfigValidator(checkCAs))
+	if _, err := os.Stat(cp); err == nil {
+		if err := cfgs.Watch(ctx); err != nil {
+			log.Logger.Fatalf("error watching --config-path=%s: %v", cp, err)
 		}
-		baseca, err = pkcs11ca.NewPKCS11CA(params)
-	case "fileca":
-		certFile := viper.GetString("fileca-cert")
-		keyFile := viper.GetString("fileca-key")
-		keyPass := viper.GetString("fileca-key-passwd")
-		watch := viper.GetBool("fileca-watch")
-		baseca, err = fileca.NewFileCA(certFile, keyFile, keyPass, watch)
-	case "ephemeralca":
-		baseca, err = ephemeralca.NewEphemeralCA()
-	case "kmsca":
-		var data []byte
-		data, err = os.ReadFile(filepath.Clean(viper.GetString("kms-cert-chain-path")))
+	}
+
+	baseca, err := newCA(ctx, viper.GetViper())
+	if err != nil {
+		log.Logger.Fatal(err)
+	}
+	if len(viper.GetStringMap("ca-backends")) > 0 || len(viper.GetStringSlice("ca-failover")) > 0 {
+		baseca, err = newCompositeCA(ctx, viper.GetViper(), baseca)
 		if err != nil {
-			log.Logger.Fatalf("error reading the kms certificate chain from '%s': %v", viper.GetString("kms-cert-chain-path"), err)
+			log.Logger.Fatal(err)
 		}
-		var certs []*x509.Certificate
-		certs, err = cryptoutils.LoadCertificatesFromPEM(bytes.NewReader(data))
+	}
+	defer baseca.Close()
+	go reloadOnSIGHUP(cfgs, baseca)
+	prometheus.MustRegister(server.NewSigningChainCollector(baseca))
+
+	ctLogs, memoryCTLog, err := newCTLogs(viper.GetViper())
+	if err != nil {
+		log.Logger.Fatal(err)
+	}
+	var caServerOpts []server.GRPCCAServerOption
+	ctQueueDir := viper.GetString("ct-log-fallback-queue-dir")
+	if ctQueueDir != "" {
+		ctQueue, err := ctl.NewQueue(ctQueueDir, ctLogs, ctl.WithQueueInterval(viper.GetDuration("ct-log-fallback-retry-interval")))
 		if err != nil {
-			log.Logger.Fatalf("error loading the PEM certificates from the kms certificate chain from '%s': %v", viper.GetString("kms-cert-chain-path"), err)
+			log.Logger.Fatal(err)
 		}
-		opts := make([]signature.RPCOption, 0)
-		callOpts := []grpc_retry.CallOption{grpc_retry.WithMax(viper.GetUint("gcp-kms-retries")), grpc_retry.WithPerRetryTimeout(time.Duration(viper.GetUint("gcp-kms-timeout")) * time.Second)}
-		opts = append(opts, gcp.WithGoogleAPIClientOption(option.WithGRPCDialOption(grpc.WithUnaryInterceptor(grpc_retry.UnaryClientInterceptor(callOpts...)))))
-		baseca, err = kmsca.NewKMSCA(cmd.Context(), viper.GetString("kms-resource"), certs, opts...)
-	case "tinkca":
-		baseca, err = tinkca.NewTinkCA(cmd.Context(),
-			viper.GetString("tink-kms-resource"), viper.GetString("tink-keyset-path"), viper.GetString("tink-cert-chain-path"))
-	default:
-		err = fmt.Errorf("invalid value for configured CA: %v", baseca)
+		go ctQueue.Run(ctx)
+		caServerOpts = append(caServerOpts, server.WithCTQueue(ctQueue))
 	}
+	monitor, err := newHealthMonitor(viper.GetViper(), cfgs, ctLogs, baseca, ctQueueDir != "")
 	if err != nil {
 		log.Logger.Fatal(err)
 	}
-	defer baseca.Close()
-
-	var ctClient *ctclient.LogClient
-	if logURL := viper.GetString("ct-log-url"); logURL != "" {
-		opts := jsonclient.Options{
-			Logger: logAdaptor{logger: log.Logger},
-		}
-		// optionally add CT log public key to verify SCTs
-		if pubKeyPath := viper.GetString("ct-log-public-key-path"); pubKeyPath != "" {
-			pemPubKey, err := os.ReadFile(filepath.Clean(pubKeyPath))
-			if err != nil {
-				log.Logger.Fatal(err)
-			}
-			opts.PublicKey = string(pemPubKey)
+	go monitor.Run(ctx)
+	caServerOpts = append(caServerOpts, server.WithHealthMonitor(monitor))
+	if path := viper.GetString("ledger-path"); path != "" {
+		store, err := ledger.NewBoltStore(path)
+		if err != nil {
+			log.Logger.Fatal(err)
 		}
-		var httpClient *http.Client
-		if tlsCaCertPath := viper.GetString("ct-log.tls-ca-cert"); tlsCaCertPath != "" {
-			tlsCaCert, err := os.ReadFile(filepath.Clean(tlsCaCertPath))
-			if err != nil {
-				log.Logger.Fatal(err)
-			}
-			caCertPool := x509.NewCertPool()
-			if ok := caCertPool.AppendCertsFromPEM(tlsCaCert); !ok {
-				log.Logger.Fatal("failed to append TLS CA certificate")
-			}
-			tlsConfig := &tls.Config{
-				RootCAs:    caCertPool,
-				MinVersion: tls.VersionTLS12,
-			}
-			transport := &http.Transport{
-				TLSClientConfig: tlsConfig,
-			}
-			httpClient = &http.Client{
-				Timeout:   30 * time.Second,
-				Transport: transport,
-			}
-		} else {
-			httpClient = &http.Client{
-				Timeout: 30 * time.Second,
-			}
+		defer store.Close()
+		caServerOpts = append(caServerOpts,
+			server.WithLedger(store),
+			server.WithLedgerAdmins(viper.GetString("ledger-admin-issuer"), viper.GetStringSlice("ledger-admins")))
+	}
+	if path := viper.GetString("audit-log-path"); path != "" {
+		sink, err := newAuditSink(viper.GetViper(), path)
+		if err != nil {
+			log.Logger.Fatal(err)
 		}
-		ctClient, err = ctclient.New(logURL, httpClient, opts)
+		defer sink.Close()
+		caServerOpts = append(caServerOpts, server.WithAuditSink(sink))
+	}
+	if viper.IsSet("notify-endpoints") {
+		notifier, err := newNotifier(viper.GetViper())
 		if err != nil {
 			log.Logger.Fatal(err)
 		}
+		go notifier.Run(ctx)
+		caServerOpts = append(caServerOpts, server.WithNotifier(notifier))
 	}
-	ip := server.NewIssuerPool(cfg)
 
 	portsMatch := viper.GetString("port") == viper.GetString("grpc-port")
 	hostsMatch := viper.GetString("host") == viper.GetString("grpc-host")
@@ -362,7 +367,7 @@ func runServeCmd(cmd *cobra.Command, args []string) { //nolint: revive
 		port := viper.GetInt("port")
 		metricsPort := viper.GetInt("metrics-port")
 		// StartDuplexServer will always return an error, log fatally if it's non-nil
-		if err := StartDuplexServer(ctx, cfg, ctClient, baseca, algorithmRegistry, viper.GetString("host"), port, metricsPort, ip); err != http.ErrServerClosed {
+		if err := StartDuplexServer(ctx, cfgs, ctLogs, memoryCTLog, baseca, algorithmRegistry, viper.GetString("host"), port, metricsPort, caServerOpts...); err != http.ErrServerClosed {
 			log.Logger.Fatal(err)
 		}
 		return
@@ -375,20 +380,20 @@ func runServeCmd(cmd *cobra.Command, args []string) { //nolint: revive
 
 	reg := prometheus.NewRegistry()
 
-	grpcServer, err := createGRPCServer(cfg, ctClient, baseca, algorithmRegistry, ip)
+	grpcServer, err := createGRPCServer(cfgs, ctLogs, baseca, algorithmRegistry, caServerOpts...)
 	if err != nil {
 		log.Logger.Fatal(err)
 	}
 	grpcServer.setupPrometheus(reg)
 	grpcServer.startTCPListener(&wg)
 
-	legacyGRPCServer, err := createLegacyGRPCServer(cfg, viper.GetString("legacy-unix-domain-socket"), grpcServer.caService)
+	legacyGRPCServer, err := createLegacyGRPCServer(cfgs, viper.GetString("legacy-unix-domain-socket"), grpcServer.caService)
 	if err != nil {
 		log.Logger.Fatal(err)
 	}
 	legacyGRPCServer.startUnixListener()
 
-	httpServer := createHTTPServer(ctx, httpServerEndpoint, grpcServer, legacyGRPCServer)
+	httpServer := createHTTPServer(ctx, httpServerEndpoint, grpcServer, legacyGRPCServer, memoryCTLog)
 	httpServer.startListener(&wg)
 
 	readHeaderTimeout := viper.GetDuration("read-header-timeout")
@@ -422,6 +427,518 @@ func runServeCmd(cmd *cobra.Command, args []string) { //nolint: revive
 	wg.Wait()
 }
 
+// reloadOnSIGHUP reloads the config file, and the keys and certificates of
+// CAs that support reloading, each time the process receives SIGHUP.
+func reloadOnSIGHUP(cfgs *server.ConfigReloader, baseca certauth.CertificateAuthority) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	for range sighup {
+		log.Logger.Info("received SIGHUP, reloading config")
+		if err := cfgs.Reload(); err != nil {
+			log.Logger.Errorf("error reloading config, continuing with previous config: %v", err)
+		}
+		if rl, ok := baseca.(certauth.Reloader); ok {
+			if err := rl.Reload(); err != nil {
+				log.Logger.Errorf("error reloading CA, continuing with previous keys and certificates: %v", err)
+			}
+		}
+	}
+}
+
+// checkCAConfig checks that the settings required by the CA selected in v
+// are present.
+func checkCAConfig(v *viper.Viper) error {
+	switch v.GetString("ca") {
+	case "":
+		return errors.New("required flag \"ca\" not set")
+
+	case "pkcs11ca":
+		if v.GetString("hsm-caroot-id") == "" {
+			return errors.New("hsm-caroot-id must be set when using pkcs11ca")
+		}
+
+	case "googleca":
+		if v.GetString("gcp_private_ca_parent") == "" {
+			return errors.New("gcp_private_ca_parent must be set when using googleca")
+		}
+		if v.IsSet("gcp_private_ca_version") {
+			// There's a MarkDeprecated function in cobra/pflags, but it doesn't use log.Logger
+			log.Logger.Warn("gcp_private_ca_version is deprecated and will soon be removed; please remove it")
+		}
+	case "awspca":
+		if v.GetString("aws-pca-arn") == "" {
+			return errors.New("aws-pca-arn must be set when using awspca")
+		}
+	// RHTAS FIPS - DO NOT REMOVE
+	// ========================================
+	case "fileca":
+		// A rotation directory replaces fileca-cert and fileca-key
+		if v.GetString("fileca-dir") != "" {
+			break
+		}
+		if v.GetString("fileca-cert") == "" {
+			return errors.New("fileca-cert must be set to certificate path when using fileca")
+		}
+		if v.GetString("fileca-key") == "" {
+			return errors.New("fileca-key must be set to private key path when using fileca")
+		}
+	// ========================================
+	case "kmsca":
+		if v.GetString("kms-resource") == "" {
+			return errors.New("kms-resource must be set when using kmsca")
+		}
+		if v.GetString("kms-cert-chain-path") == "" {
+			return errors.New("kms-cert-chain-path must be set when using kmsca")
+		}
+	case "tinkca":
+		if v.GetString("tink-kms-resource") == "" {
+			return errors.New("tink-kms-resource must be set when using tinkca")
+		}
+		if v.GetString("tink-cert-chain-path") == "" {
+			return errors.New("tink-cert-chain-path must be set when using tinkca")
+		}
+		if v.GetString("tink-keyset-path") == "" {
+			return errors.New("tink-keyset-path must be set when using tinkca")
+		}
+	case "ephemeralca":
+		// this is a no-op since this is a self-signed in-memory CA for testing
+	default:
+		return fmt.Errorf("--ca=%s is not a valid selection. Try: pkcs11ca, googleca, awspca, fileca, kmsca, tinkca, or ephemeralca", v.GetString("ca"))
+	}
+	return nil
+}
+
+// newCA creates the CA selected in v.
+func newCA(ctx context.Context, v *viper.Viper) (certauth.CertificateAuthority, error) {
+	switch v.GetString("ca") {
+	case "googleca":
+		return googlecav1.NewCertAuthorityService(ctx, v.GetString("gcp_private_ca_parent"))
+	case "awspca":
+		return awspca.NewAWSPCA(ctx, v.GetString("aws-pca-arn"))
+	case "pkcs11ca":
+		params := pkcs11ca.Params{
+			ConfigPath: v.GetString("pkcs11-config-path"),
+			RootID:     v.GetString("hsm-caroot-id"),
+		}
+		if path := v.GetString("aws-hsm-root-ca-path"); path != "" {
+			params.CAPath = &path
+		}
+		return pkcs11ca.NewPKCS11CA(params)
+	case "fileca":
+		certFile := v.GetString("fileca-cert")
+		keyFile := v.GetString("fileca-key")
+		keyPass := v.GetString("fileca-key-passwd")
+		watch := v.GetBool("fileca-watch")
+		if dir := v.GetString("fileca-dir"); dir != "" {
+			return fileca.NewFileCAFromDir(dir, keyPass, watch)
+		}
+		return fileca.NewFileCA(certFile, keyFile, keyPass, watch)
+	case "ephemeralca":
+		return ephemeralca.NewEphemeralCA()
+	case "kmsca":
+		data, err := os.ReadFile(filepath.Clean(v.GetString("kms-cert-chain-path")))
+		if err != nil {
+			return nil, fmt.Errorf("error reading the kms certificate chain from '%s': %w", v.GetString("kms-cert-chain-path"), err)
+		}
+		certs, err := cryptoutils.LoadCertificatesFromPEM(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("error loading the PEM certificates from the kms certificate chain from '%s': %w", v.GetString("kms-cert-chain-path"), err)
+		}
+		opts := make([]signature.RPCOption, 0)
+		callOpts := []grpc_retry.CallOption{grpc_retry.WithMax(v.GetUint("gcp-kms-retries")), grpc_retry.WithPerRetryTimeout(time.Duration(v.GetUint("gcp-kms-timeout")) * time.Second)}
+		opts = append(opts, gcp.WithGoogleAPIClientOption(option.WithGRPCDialOption(grpc.WithUnaryInterceptor(grpc_retry.UnaryClientInterceptor(callOpts...)))))
+		return kmsca.NewKMSCA(ctx, v.GetString("kms-resource"), certs, opts...)
+	case "tinkca":
+		return tinkca.NewTinkCA(ctx,
+			v.GetString("tink-kms-resource"), v.GetString("tink-keyset-path"), v.GetString("tink-cert-chain-path"))
+	default:
+		return nil, fmt.Errorf("invalid value for configured CA: %v", v.GetString("ca"))
+	}
+}
+
+// newCTLogs creates the CT logs configured in v, or returns nil if no CT log
+// is configured. If one of the logs is the in-memory CT log, it is also
+// returned on its own.
+func newCTLogs(v *viper.Viper) (*ctl.MultiLog, *ctl.MemoryLog, error) {
+	var logURLs []string
+	for _, u := range v.GetStringSlice("ct-log-url") {
+		if u != "" {
+			logURLs = append(logURLs, u)
+		}
+	}
+	if len(logURLs) == 0 {
+		return nil, nil, nil
+	}
+	// Per-log settings are either unset, or given once per log in order
+	perLog := func(key string) ([]string, error) {
+		values := v.GetStringSlice(key)
+		switch len(values) {
+		case 0:
+			return make([]string, len(logURLs)), nil
+		case len(logURLs):
+			return values, nil
+		default:
+			return nil, fmt.Errorf("--%s must be given once per --ct-log-url, got %d values for %d logs", key, len(values), len(logURLs))
+		}
+	}
+	pubKeyPaths, err := perLog("ct-log-public-key-path")
+	if err != nil {
+		return nil, nil, err
+	}
+	logTypes, err := perLog("ct-log-type")
+	if err != nil {
+		return nil, nil, err
+	}
+	monitoringURLs, err := perLog("ct-log-monitoring-url")
+	if err != nil {
+		return nil, nil, err
+	}
+	origins, err := perLog("ct-log-origin")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	httpClient := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+	if tlsCaCertPath := v.GetString("ct-log.tls-ca-cert"); tlsCaCertPath != "" {
+		tlsCaCert, err := os.ReadFile(filepath.Clean(tlsCaCertPath))
+		if err != nil {
+			return nil, nil, err
+		}
+		caCertPool := x509.NewCertPool()
+		if ok := caCertPool.AppendCertsFromPEM(tlsCaCert); !ok {
+			return nil, nil, errors.New("failed to append TLS CA certificate")
+		}
+		httpClient.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs:    caCertPool,
+				MinVersion: tls.VersionTLS12,
+			},
+		}
+	}
+
+	logs := make([]ctl.Log, 0, len(logURLs))
+	var memoryLog *ctl.MemoryLog
+	for i, logURL := range logURLs {
+		if logURL == inMemoryCTLogURL {
+			if memoryLog != nil {
+				return nil, nil, fmt.Errorf("--ct-log-url=%s can only be given once", inMemoryCTLogURL)
+			}
+			if memoryLog, err = newMemoryCTLog(v); err != nil {
+				return nil, nil, err
+			}
+			// Submissions to the in-memory log cannot fail, so they are
+			// not retried
+			logs = append(logs, memoryLog)
+			continue
+		}
+		var pemPubKey []byte
+		if pubKeyPaths[i] != "" {
+			pemPubKey, err = os.ReadFile(filepath.Clean(pubKeyPaths[i]))
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		var l ctl.Log
+		switch logTypes[i] {
+		case "", ctl.LogTypeRFC6962:
+			opts := jsonclient.Options{
+				Logger: logAdaptor{logger: log.Logger},
+				// optionally add CT log public key to verify SCTs
+				PublicKey: string(pemPubKey),
+			}
+			ctClient, err := ctclient.New(logURL, httpClient, opts)
+			if err != nil {
+				return nil, nil, fmt.Errorf("creating CT log client for %s: %w", logURL, err)
+			}
+			l = ctClient
+		case ctl.LogTypeStaticCT:
+			// SCTs and checkpoints of static-ct-api logs are always verified
+			if pemPubKey == nil {
+				return nil, nil, fmt.Errorf("--ct-log-public-key-path is required for %s log %s", ctl.LogTypeStaticCT, logURL)
+			}
+			pubKey, err := cryptoutils.UnmarshalPEMToPublicKey(pemPubKey)
+			if err != nil {
+				return nil, nil, fmt.Errorf("parsing public key of CT log %s: %w", logURL, err)
+			}
+			staticLog, err := ctl.NewStaticCTLog(logURL, pubKey,
+				ctl.WithMonitoringURL(monitoringURLs[i]),
+				ctl.WithOrigin(origins[i]),
+				ctl.WithHTTPClient(httpClient))
+			if err != nil {
+				return nil, nil, fmt.Errorf("creating CT log client for %s: %w", logURL, err)
+			}
+			l = staticLog
+		default:
+			return nil, nil, fmt.Errorf("invalid --ct-log-type %q for %s, must be %s or %s", logTypes[i], logURL, ctl.LogTypeRFC6962, ctl.LogTypeStaticCT)
+		}
+		resilientLog, err := ctl.NewResilientLog(l,
+			ctl.WithRetries(v.GetInt("ct-log-retries")),
+			ctl.WithBackoff(v.GetDuration("ct-log-retry-backoff"), v.GetDuration("ct-log-retry-max-backoff")),
+			ctl.WithAttemptTimeout(v.GetDuration("ct-log-attempt-timeout")),
+			ctl.WithCircuitBreaker(v.GetInt("ct-log-circuit-breaker-threshold"), v.GetDuration("ct-log-circuit-breaker-cooldown")))
+		if err != nil {
+			return nil, nil, fmt.Errorf("configuring CT log %s: %w", logURL, err)
+		}
+		logs = append(logs, resilientLog)
+	}
+	multiLog, err := ctl.NewMultiLog(logs,
+		ctl.WithQuorum(v.GetInt("ct-log-quorum")),
+		ctl.WithDeadline(v.GetDuration("ct-log-deadline")))
+	if err != nil {
+		return nil, nil, err
+	}
+	return multiLog, memoryLog, nil
+}
+
+// newMemoryCTLog returns an in-memory CT log signing with the key at
+// --ct-log-inmemory-key-path, or with a random key if it is unset.
+func newMemoryCTLog(v *viper.Viper) (*ctl.MemoryLog, error) {
+	log.Logger.Warnf("--ct-log-url=%s is for development only: its entries are lost on restart", inMemoryCTLogURL)
+	keyPath := v.GetString("ct-log-inmemory-key-path")
+	if keyPath == "" {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("generating in-memory CT log key: %w", err)
+		}
+		return ctl.NewMemoryLog(key)
+	}
+	pemKey, err := os.ReadFile(filepath.Clean(keyPath))
+	if err != nil {
+		return nil, fmt.Errorf("reading --ct-log-inmemory-key-path: %w", err)
+	}
+	key, err := cryptoutils.UnmarshalPEMToPrivateKey(pemKey, cryptoutils.SkipPassword)
+	if err != nil {
+		return nil, fmt.Errorf("parsing --ct-log-inmemory-key-path: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("--ct-log-inmemory-key-path holds an unsupported %T key", key)
+	}
+	return ctl.NewMemoryLog(signer)
+}
+
+// newAuditSink returns a sink appending to the audit log at path, signing
+// checkpoints with --audit-signing-key if set.
+func newAuditSink(v *viper.Viper, path string) (*audit.FileSink, error) {
+	opts := []audit.FileSinkOption{
+		audit.WithCheckpointEvery(v.GetInt("audit-checkpoint-every")),
+		audit.WithCheckpointInterval(v.GetDuration("audit-checkpoint-interval")),
+	}
+	if keyPath := v.GetString("audit-signing-key"); keyPath != "" {
+		signer, err := signature.LoadSignerFromPEMFile(filepath.Clean(keyPath), crypto.SHA256, cryptoutils.SkipPassword)
+		if err != nil {
+			return nil, fmt.Errorf("loading --audit-signing-key: %w", err)
+		}
+		opts = append(opts, audit.WithSigner(signer))
+	}
+	return audit.NewFileSink(path, opts...)
+}
+
+// notifyEndpointConfig is a webhook configured under notify-endpoints.
+type notifyEndpointConfig struct {
+	Name       string                `mapstructure:"name"`
+	URL        string                `mapstructure:"url"`
+	SecretFile string                `mapstructure:"secret-file"`
+	Filters    []map[string][]string `mapstructure:"filters"`
+}
+
+// newNotifier returns a notifier posting issuance events to the endpoints
+// configured under notify-endpoints in v.
+func newNotifier(v *viper.Viper) (*notify.Notifier, error) {
+	var configs []notifyEndpointConfig
+	if err := v.UnmarshalKey("notify-endpoints", &configs); err != nil {
+		return nil, fmt.Errorf("notify-endpoints: %w", err)
+	}
+	var endpoints []notify.Endpoint
+	for _, c := range configs {
+		e := notify.Endpoint{Name: c.Name, URL: c.URL}
+		if c.SecretFile != "" {
+			secret, err := os.ReadFile(filepath.Clean(c.SecretFile))
+			if err != nil {
+				return nil, fmt.Errorf("notify-endpoints: reading secret of %q: %w", c.Name, err)
+			}
+			e.Secret = bytes.TrimSpace(secret)
+		}
+		for _, f := range c.Filters {
+			e.Filters = append(e.Filters, notify.Filter(f))
+		}
+		endpoints = append(endpoints, e)
+	}
+	return notify.NewNotifier(endpoints,
+		notify.WithSource(v.GetString("notify-source")),
+		notify.WithQueueSize(v.GetInt("notify-queue-size")),
+		notify.WithRetries(v.GetInt("notify-retries")),
+		notify.WithAttemptTimeout(v.GetDuration("notify-timeout")))
+}
+
+// newHealthMonitor returns a monitor of the components the server depends
+// on. An unreachable CT log does not make the server unready if
+// certificates are queued for CT logs while they are unreachable.
+func newHealthMonitor(v *viper.Viper, cfgs *server.ConfigReloader, ctLogs *ctl.MultiLog, baseca certauth.CertificateAuthority, ctQueued bool) (*healthmon.Monitor, error) {
+	checks := []healthmon.Check{{
+		Name: server.HealthCheckCA,
+		Func: func(ctx context.Context) error { return certauth.Probe(ctx, baseca) },
+	}, {
+		Name: server.HealthCheckConfig,
+		Func: cfgs.CheckConfig,
+	}, {
+		// Issuers that cannot be discovered only fail the requests they sign
+		Name:     server.HealthCheckOIDCDiscovery,
+		Func:     cfgs.CheckOIDCDiscovery,
+		Optional: true,
+	}}
+	if ctLogs != nil {
+		checks = append(checks, healthmon.Check{
+			Name:     server.HealthCheckCTLog,
+			Func:     ctLogs.Ping,
+			Optional: ctQueued,
+		})
+	}
+	return healthmon.NewMonitor(checks,
+		healthmon.WithInterval(v.GetDuration("health-check-interval")),
+		healthmon.WithTimeout(v.GetDuration("health-check-timeout")))
+}
+
+// caBackendViper returns the settings of the CA backend configured under
+// ca-backends.<name> in v. Settings the backend does not set, such as
+// pkcs11-config-path or gcp-kms-retries, are inherited from v.
+func caBackendViper(v *viper.Viper, name string) (*viper.Viper, error) {
+	sub := v.Sub("ca-backends." + name)
+	if sub == nil || !sub.IsSet("ca") {
+		return nil, fmt.Errorf("CA backend %q must set ca", name)
+	}
+	bv := viper.New()
+	for _, k := range v.AllKeys() {
+		if strings.HasPrefix(k, "ca-backends.") {
+			continue
+		}
+		bv.SetDefault(k, v.Get(k))
+	}
+	for _, k := range sub.AllKeys() {
+		bv.Set(k, sub.Get(k))
+	}
+	return bv, nil
+}
+
+// newCABackends creates the CA backends configured under ca-backends in v.
+func newCABackends(ctx context.Context, v *viper.Viper) (map[string]certauth.CertificateAuthority, error) {
+	backends := map[string]certauth.CertificateAuthority{}
+	for name := range v.GetStringMap("ca-backends") {
+		bv, err := caBackendViper(v, name)
+		if err == nil {
+			err = checkCAConfig(bv)
+		}
+		var b certauth.CertificateAuthority
+		if err == nil {
+			b, err = newCA(ctx, bv)
+		}
+		if err != nil {
+			for _, b := range backends {
+				b.Close()
+			}
+			return nil, fmt.Errorf("CA backend %q: %w", name, err)
+		}
+		backends[name] = b
+	}
+	return backends, nil
+}
+
+// newCompositeCA combines primary with the CA backends configured in v. The
+// backends named by ca-failover are tried in order when primary fails, and
+// the remaining backends are used for the issuers that name them.
+func newCompositeCA(ctx context.Context, v *viper.Viper, primary certauth.CertificateAuthority) (certauth.CertificateAuthority, error) {
+	backends, err := newCABackends(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	closeAll := func() {
+		for _, b := range backends {
+			b.Close()
+		}
+	}
+
+	def := primary
+	if names := v.GetStringSlice("ca-failover"); len(names) > 0 {
+		failover := []failoverca.Backend{{Name: v.GetString("ca"), CA: primary}}
+		routed := maps.Clone(backends)
+		for _, name := range names {
+			b, ok := backends[name]
+			if !ok {
+				closeAll()
+				return nil, fmt.Errorf("ca-failover: no CA backend named %q is configured in ca-backends", name)
+			}
+			failover = append(failover, failoverca.Backend{Name: name, CA: b})
+			// Failover backends are owned by the failover CA
+			delete(routed, name)
+		}
+		def, err = failoverca.NewFailoverCA(failover,
+			failoverca.WithFailureThreshold(v.GetInt("ca-failover-threshold")),
+			failoverca.WithCooldown(v.GetDuration("ca-failover-cooldown")))
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		backends = routed
+	}
+	if len(backends) == 0 {
+		return def, nil
+	}
+
+	rca, err := routingca.NewRoutingCA(def, backends)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+	return rca, nil
+}
+
+// checkIssuerCAs returns a function that checks that every issuer in a config
+// names one of the CA backends configured under ca-backends in v, other than
+// the backends used for failover, and that none of the backends an issuer
+// can be issued by ignores its settings.
+func checkIssuerCAs(v *viper.Viper) func(*config.FulcioConfig) error {
+	backends := v.GetStringMap("ca-backends")
+	failover := v.GetStringSlice("ca-failover")
+	check := func(issuerURL string, iss config.OIDCIssuer) error {
+		// The types of the CAs that may issue for the issuer
+		var caTypes []string
+		if iss.CA == "" {
+			caTypes = append(caTypes, v.GetString("ca"))
+			for _, name := range failover {
+				caTypes = append(caTypes, v.GetString("ca-backends."+name+".ca"))
+			}
+		} else {
+			if _, ok := backends[iss.CA]; !ok {
+				return fmt.Errorf("issuer %s: no CA backend named %q is configured in ca-backends", issuerURL, iss.CA)
+			}
+			if slices.Contains(failover, iss.CA) {
+				return fmt.Errorf("issuer %s: CA backend %q is used for ca-failover and cannot be selected by issuers", issuerURL, iss.CA)
+			}
+			caTypes = append(caTypes, v.GetString("ca-backends."+iss.CA+".ca"))
+		}
+		// Google CA Service always sets NotBefore to the time of issuance
+		if iss.CertificateNotBeforeSkew() != 0 && slices.Contains(caTypes, "googleca") {
+			return fmt.Errorf("issuer %s: not-before-skew is not supported by the googleca CA backend", issuerURL)
+		}
+		return nil
+	}
+	return func(cfg *config.FulcioConfig) error {
+		for issuerURL, iss := range cfg.OIDCIssuers {
+			if err := check(issuerURL, iss); err != nil {
+				return err
+			}
+		}
+		for metaURL, iss := range cfg.MetaIssuers {
+			if err := check(metaURL, iss); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
 func checkServeCmdConfigFile() error {
 	if serveCmdConfigFilePath != "" {
 		if _, err := os.Stat(serveCmdConfigFilePath); err != nil {
@@ -460,10 +977,10 @@ func duplexHealthz(_ context.Context, mux *runtime.ServeMux, endpoint string, op
 	}
 	registerHealthz := runtime.WithHealthzEndpoint(health.NewHealthClient(cc))
 	registerHealthz(mux)
-	return nil
+	return mux.HandlePath(http.MethodGet, "/readyz", readyz(health.NewHealthClient(cc)))
 }
 
-func StartDuplexServer(ctx context.Context, cfg *config.FulcioConfig, ctClient *ctclient.LogClient, baseca certauth.CertificateAuthority, algorithmRegistry *signature.AlgorithmRegistryConfig, host string, port, metricsPort int, ip identity.IssuerPool) error {
+func StartDuplexServer(ctx context.Context, cfgs *server.ConfigReloader, ctLogs *ctl.MultiLog, memoryCTLog *ctl.MemoryLog, baseca certauth.CertificateAuthority, algorithmRegistry *signature.AlgorithmRegistryConfig, host string, port, metricsPort int, extraOpts ...server.GRPCCAServerOption) error {
 	logger, opts := log.SetupGRPCLogging()
 
 	d := duplex.New(
@@ -472,20 +989,27 @@ func StartDuplexServer(ctx context.Context, cfg *config.FulcioConfig, ctClient *
 		grpc.KeepaliveParams(keepalive.ServerParameters{
 			MaxConnectionIdle: viper.GetDuration("idle-connection-timeout"),
 		}),
-		runtime.WithMetadata(extractOIDCTokenFromAuthHeader),
+		runtime.WithMetadata(server.ExtractOIDCTokenFromAuthHeader),
 		grpc.UnaryInterceptor(grpcmw.ChainUnaryServer(
 			grpc_recovery.UnaryServerInterceptor(grpc_recovery.WithRecoveryHandlerContext(panicRecoveryHandler)), // recovers from per-transaction panics elegantly, so put it first
 			middleware.UnaryRequestID(middleware.UseXRequestIDMetadataOption(true), middleware.XRequestMetadataLimitOption(128)),
 			grpc_zap.UnaryServerInterceptor(logger, opts...),
-			PassFulcioConfigThruContext(cfg),
+			cfgs.UnaryInterceptor(),
 			grpc_prometheus.UnaryServerInterceptor,
 		)),
 		grpc.MaxRecvMsgSize(int(maxMsgSize)),
-		runtime.WithForwardResponseOption(setResponseCodeModifier),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		runtime.WithForwardResponseOption(server.SetResponseCodeModifier),
+		runtime.WithMiddlewares(traceHTTPRequest),
 	)
 
 	// GRPC server
-	grpcCAServer := server.NewGRPCCAServer(ctClient, baseca, algorithmRegistry, ip)
+	caServerOpts, err := caServerOptions(cfgs)
+	if err != nil {
+		return err
+	}
+	grpcCAServer := server.NewGRPCCAServer(ctLogs, baseca, algorithmRegistry, cfgs.IssuerPool(), append(caServerOpts, extraOpts...)...)
 	protobuf.RegisterCAServer(d.Server, grpcCAServer)
 	if err := d.RegisterHandler(ctx, protobuf.RegisterCAHandlerFromEndpoint); err != nil {
 		return fmt.Errorf("registering grpc ca handler: %w", err)
@@ -511,6 +1035,14 @@ func StartDuplexServer(ctx context.Context, cfg *config.FulcioConfig, ctClient *
 		return fmt.Errorf("registering healthz endpoint: %w", err)
 	}
 
+	if memoryCTLog != nil {
+		if err := d.RegisterHandler(ctx, func(_ context.Context, mux *runtime.ServeMux, _ string, _ []grpc.DialOption) error {
+			return mux.HandlePath(http.MethodGet, ctLogDebugPath, ctLogDebug(memoryCTLog))
+		}); err != nil {
+			return fmt.Errorf("registering CT log debug endpoint: %w", err)
+		}
+	}
+
 	// Register prometheus handle.
 	d.RegisterListenAndServeMetrics(metricsPort, false)
 
@@ -17,11 +17,15 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -29,11 +33,15 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sigstore/fulcio/pkg/api"
+	certauth "github.com/sigstore/fulcio/pkg/ca"
 	"github.com/sigstore/fulcio/pkg/ca/ephemeralca"
 	"github.com/sigstore/fulcio/pkg/config"
 	"github.com/sigstore/fulcio/pkg/generated/protobuf"
+	"github.com/sigstore/fulcio/pkg/notify"
+	"github.com/sigstore/fulcio/pkg/server"
 	v1 "github.com/sigstore/protobuf-specs/gen/pb-go/common/v1"
 	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/spf13/viper"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -65,7 +73,7 @@ func TestDuplex(t *testing.T) {
 	}
 
 	go func() {
-		if err := StartDuplexServer(ctx, config.DefaultConfig, nil, ca, algorithmRegistry, "localhost", port, metricsPort, nil); err != nil {
+		if err := StartDuplexServer(ctx, server.NewConfigReloader("", config.DefaultConfig), nil, nil, ca, algorithmRegistry, "localhost", port, metricsPort); err != nil {
 			log.Fatalf("error starting duplex server: %v", err)
 		}
 	}()
@@ -139,4 +147,304 @@ func TestDuplex(t *testing.T) {
 			t.Fatalf("/healthz returned status code %d, want 200", code)
 		}
 	})
+
+	t.Run("readyz", func(t *testing.T) {
+		url := fmt.Sprintf("http://localhost:%d/readyz", port)
+		resp, err := http.Get(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if code := resp.StatusCode; code != 200 {
+			t.Fatalf("/readyz returned status code %d, want 200", code)
+		}
+		var statuses map[string]string
+		if err := json.NewDecoder(resp.Body).Decode(&statuses); err != nil {
+			t.Fatal(err)
+		}
+		if got := statuses[server.ReadinessService]; got != "SERVING" {
+			t.Errorf("/readyz reported %s as %q, want SERVING", server.ReadinessService, got)
+		}
+	})
+}
+
+func TestNewCompositeCA(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(`
+ca: ephemeralca
+ca-failover: [backup]
+ca-failover-threshold: 3
+ca-failover-cooldown: 30s
+ca-backends:
+  team:
+    ca: ephemeralca
+  backup:
+    ca: ephemeralca
+`)); err != nil {
+		t.Fatal(err)
+	}
+	primary, err := ephemeralca.NewEphemeralCA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := newCompositeCA(context.Background(), v, primary)
+	if err != nil {
+		t.Fatalf("newCompositeCA() = %v", err)
+	}
+	defer ca.Close()
+
+	for _, name := range []string{"", "team"} {
+		if _, err := certauth.Resolve(certauth.WithBackend(context.Background(), name), ca); err != nil {
+			t.Errorf("Resolve(%q) = %v", name, err)
+		}
+	}
+	// Failover backends can't be selected directly
+	if _, err := certauth.Resolve(certauth.WithBackend(context.Background(), "backup"), ca); err == nil {
+		t.Error("expected failover backend not to be routable")
+	}
+	chains, err := ca.TrustBundle(context.Background())
+	if err != nil {
+		t.Fatalf("TrustBundle() = %v", err)
+	}
+	if len(chains) != 3 {
+		t.Errorf("expected the chains of all 3 backends, got %d", len(chains))
+	}
+
+	check := checkIssuerCAs(v)
+	for name, wantErr := range map[string]bool{"": false, "team": false, "backup": true, "missing": true} {
+		cfg := &config.FulcioConfig{OIDCIssuers: map[string]config.OIDCIssuer{
+			"https://issuer.example.com": {IssuerURL: "https://issuer.example.com", CA: name},
+		}}
+		if err := check(cfg); (err != nil) != wantErr {
+			t.Errorf("checkIssuerCAs(%q) = %v, wantErr %v", name, err, wantErr)
+		}
+	}
+
+	v.Set("ca-failover", []string{"missing"})
+	if _, err := newCompositeCA(context.Background(), v, primary); err == nil {
+		t.Error("expected error for unknown failover backend")
+	}
+}
+
+func TestCheckIssuerCAsNotBeforeSkew(t *testing.T) {
+	for name, test := range map[string]struct {
+		failover string
+		ca       string
+		skew     string
+		wantErr  bool
+	}{
+		"default CA":              {skew: "30s"},
+		"googleca without skew":   {ca: "gcp"},
+		"googleca with skew":      {ca: "gcp", skew: "30s", wantErr: true},
+		"googleca as failover":    {failover: "gcp", skew: "30s", wantErr: true},
+		"other backend with skew": {failover: "gcp", ca: "team", skew: "30s"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			v := viper.New()
+			v.SetConfigType("yaml")
+			if err := v.ReadConfig(strings.NewReader(`
+ca: ephemeralca
+ca-backends:
+  gcp:
+    ca: googleca
+  team:
+    ca: ephemeralca
+`)); err != nil {
+				t.Fatal(err)
+			}
+			if test.failover != "" {
+				v.Set("ca-failover", []string{test.failover})
+			}
+			cfg := &config.FulcioConfig{OIDCIssuers: map[string]config.OIDCIssuer{
+				"https://issuer.example.com": {IssuerURL: "https://issuer.example.com", CA: test.ca, NotBeforeSkew: test.skew},
+			}}
+			if err := checkIssuerCAs(v)(cfg); (err != nil) != test.wantErr {
+				t.Errorf("checkIssuerCAs() = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewCTLogs(t *testing.T) {
+	for name, test := range map[string]struct {
+		config     string
+		wantNil    bool
+		wantMemory bool
+		wantErr    bool
+	}{
+		"no logs": {
+			config:  `ct-log-url: ""`,
+			wantNil: true,
+		},
+		"several logs": {
+			config: `
+ct-log-url: [http://localhost:6962/a, http://localhost:6962/b]
+ct-log-quorum: 2
+ct-log-deadline: 1s
+`,
+		},
+		"key count does not match logs": {
+			config: `
+ct-log-url: [http://localhost:6962/a, http://localhost:6962/b]
+ct-log-public-key-path: [a.pem]
+`,
+			wantErr: true,
+		},
+		"quorum beyond logs": {
+			config: `
+ct-log-url: [http://localhost:6962/a]
+ct-log-quorum: 2
+`,
+			wantErr: true,
+		},
+		"static-ct and rfc6962 logs": {
+			config: `
+ct-log-url: [http://localhost:6962, http://localhost:6962/test]
+ct-log-type: [static-ct, rfc6962]
+ct-log-public-key-path: [../../config/ctfe/pubkey.pem, ""]
+ct-log-monitoring-url: [http://localhost:8000, ""]
+ct-log-origin: [example.com/log, ""]
+ct-log-quorum: 1
+`,
+		},
+		"static-ct log without public key": {
+			config: `
+ct-log-url: [http://localhost:6962]
+ct-log-type: [static-ct]
+ct-log-quorum: 1
+`,
+			wantErr: true,
+		},
+		"retries and circuit breaker": {
+			config: `
+ct-log-url: [http://localhost:6962/a, http://localhost:6962/b]
+ct-log-quorum: 1
+ct-log-retries: 3
+ct-log-retry-backoff: 100ms
+ct-log-retry-max-backoff: 1s
+ct-log-attempt-timeout: 5s
+ct-log-circuit-breaker-threshold: 5
+ct-log-circuit-breaker-cooldown: 1m
+`,
+		},
+		"circuit breaker without cooldown": {
+			config: `
+ct-log-url: [http://localhost:6962/a]
+ct-log-quorum: 1
+ct-log-circuit-breaker-threshold: 5
+`,
+			wantErr: true,
+		},
+		"in-memory log": {
+			config: `
+ct-log-url: [inmemory://]
+ct-log-quorum: 1
+`,
+			wantMemory: true,
+		},
+		"in-memory and rfc6962 logs": {
+			config: `
+ct-log-url: [inmemory://, http://localhost:6962/test]
+ct-log-inmemory-key-path: ../../config/ctfe/privkey.pem
+ct-log-quorum: 1
+`,
+			wantMemory: true,
+		},
+		"in-memory log twice": {
+			config:  `ct-log-url: [inmemory://, inmemory://]`,
+			wantErr: true,
+		},
+		"in-memory log with missing key": {
+			config: `
+ct-log-url: [inmemory://]
+ct-log-inmemory-key-path: missing.pem
+`,
+			wantErr: true,
+		},
+		"invalid log type": {
+			config: `
+ct-log-url: [http://localhost:6962]
+ct-log-type: [trillian]
+ct-log-quorum: 1
+`,
+			wantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			v := viper.New()
+			v.SetConfigType("yaml")
+			if err := v.ReadConfig(strings.NewReader(test.config)); err != nil {
+				t.Fatal(err)
+			}
+			logs, memoryLog, err := newCTLogs(v)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("newCTLogs() = %v, wantErr %v", err, test.wantErr)
+			}
+			if err == nil && (logs == nil) != test.wantNil {
+				t.Errorf("newCTLogs() returned %v, wantNil %v", logs, test.wantNil)
+			}
+			if err == nil && (memoryLog != nil) != test.wantMemory {
+				t.Errorf("newCTLogs() returned in-memory log %v, wantMemory %v", memoryLog, test.wantMemory)
+			}
+		})
+	}
+}
+
+func TestNewNotifier(t *testing.T) {
+	received := make(chan *http.Request, 10)
+	endpoint := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		received <- r
+	}))
+	defer endpoint.Close()
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secretFile, []byte("secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(fmt.Sprintf(`
+notify-source: https://fulcio.example.com
+notify-queue-size: 10
+notify-retries: 0
+notify-timeout: 5s
+notify-endpoints:
+  - name: security
+    url: %s
+    secret-file: %s
+    filters:
+      - email: [admin@example.com]
+      - job_workflow_ref: [octo/app/.github/workflows/release.yml@refs/heads/main]
+`, endpoint.URL, secretFile))); err != nil {
+		t.Fatal(err)
+	}
+	n, err := newNotifier(v)
+	if err != nil {
+		t.Fatalf("newNotifier() = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go n.Run(ctx)
+
+	n.Notify(ctx, &notify.Event{SerialNumber: "1", Claims: map[string]any{"email": "dev@example.com"}})
+	n.Notify(ctx, &notify.Event{SerialNumber: "2", Claims: map[string]any{"job_workflow_ref": "octo/app/.github/workflows/release.yml@refs/heads/main"}})
+	select {
+	case r := <-received:
+		if r.Header.Get(notify.SignatureHeader) == "" {
+			t.Error("expected a signed payload")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for issuance event")
+	}
+	select {
+	case <-received:
+		t.Error("expected only the event matching a filter to be posted")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	v.Set("notify-endpoints", []map[string]any{{"name": "security", "url": endpoint.URL, "secret-file": filepath.Join(t.TempDir(), "missing")}})
+	if _, err := newNotifier(v); err == nil {
+		t.Error("expected error for a missing secret file")
+	}
 }
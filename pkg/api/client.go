@@ -17,6 +17,8 @@ package api
 
 import (
 	"bytes"
+	"context"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
@@ -27,12 +29,19 @@ import (
 	"net/url"
 	"path"
 	"time"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"google.golang.org/grpc"
 )
 
 type CertificateResponse struct {
 	CertPEM  []byte
 	ChainPEM []byte
 	SCT      []byte
+
+	// Verification is the result of verifying the certificate, if the
+	// client was created WithVerifier.
+	Verification *VerificationResult
 }
 
 type RootResponse struct {
@@ -86,12 +95,15 @@ func NewClient(url *url.URL, opts ...ClientOption) LegacyClient {
 			Transport: createRoundTripper(http.DefaultTransport, o),
 			Timeout:   o.Timeout,
 		},
+		verifier: o.Verifier,
 	}
 }
 
 type client struct {
-	baseURL *url.URL
-	client  *http.Client
+	baseURL     *url.URL
+	client      *http.Client
+	verifier    *Verifier
+	trustBundle trustBundleCache
 }
 
 var _ LegacyClient = (*client)(nil)
@@ -145,11 +157,48 @@ func (c *client) SigningCert(cr CertificateRequest, token string) (*CertificateR
 		return nil, errors.New("did not find a cert from Fulcio")
 	}
 	certPem := pem.EncodeToMemory(certBlock)
-	return &CertificateResponse{
+	cresp := &CertificateResponse{
 		CertPEM:  certPem,
 		ChainPEM: chainPem,
 		SCT:      sct,
-	}, nil
+	}
+	if c.verifier != nil {
+		if cresp.Verification, err = c.verify(cr, token, cresp); err != nil {
+			return nil, fmt.Errorf("verifying issued certificate: %w", err)
+		}
+	}
+	return cresp, nil
+}
+
+// verify verifies a certificate issued for cr.
+func (c *client) verify(cr CertificateRequest, token string, resp *CertificateResponse) (*VerificationResult, error) {
+	cert, err := cryptoutils.UnmarshalCertificatesFromPEM(resp.CertPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parsing certificate: %w", err)
+	}
+	chain, err := cryptoutils.UnmarshalCertificatesFromPEM(resp.ChainPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parsing certificate chain: %w", err)
+	}
+	pub, err := parsePublicKey(cr.PublicKey.Content, cr.CertificateSigningRequest)
+	if err != nil {
+		return nil, err
+	}
+	in := Issuance{Certificate: cert[0], Chain: chain, PublicKey: pub, Token: token}
+	if len(resp.SCT) > 0 {
+		in.DetachedSCTs = [][]byte{resp.SCT}
+	}
+	return c.trustBundle.verify(context.Background(), c.verifier, in, func(context.Context) ([][]*x509.Certificate, error) {
+		root, err := c.RootCert()
+		if err != nil {
+			return nil, err
+		}
+		chain, err := cryptoutils.UnmarshalCertificatesFromPEM(root.ChainPEM)
+		if err != nil {
+			return nil, fmt.Errorf("parsing root certificate chain: %w", err)
+		}
+		return [][]*x509.Certificate{chain}, nil
+	})
 }
 
 func (c *client) RootCert() (*RootResponse, error) {
@@ -179,13 +228,19 @@ func (c *client) RootCert() (*RootResponse, error) {
 }
 
 type clientOptions struct {
-	UserAgent string
-	Timeout   time.Duration
+	UserAgent    string
+	Timeout      time.Duration
+	Retries      int
+	RetryBackoff time.Duration
+	DialOptions  []grpc.DialOption
+	Verifier     *Verifier
 }
 
 func makeOptions(opts ...ClientOption) *clientOptions {
 	o := &clientOptions{
-		UserAgent: "",
+		UserAgent:    "",
+		Retries:      DefaultRetries,
+		RetryBackoff: DefaultRetryBackoff,
 	}
 
 	for _, opt := range opts {
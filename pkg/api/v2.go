@@ -0,0 +1,519 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"path"
+	"sync/atomic"
+	"time"
+
+	fulciogrpc "github.com/sigstore/fulcio/pkg/generated/protobuf"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	signingCertV2Path   = "/api/v2/signingCert"
+	trustBundleV2Path   = "/api/v2/trustBundle"
+	configurationV2Path = "/api/v2/configuration"
+)
+
+const (
+	// DefaultRetries is how many times the v2 clients retry a request that
+	// failed because the server was unavailable. Requests for certificates
+	// are only retried if they never reached the server, as issuance is not
+	// idempotent: a retried token may be rejected as replayed by an issuer
+	// with replay protection, or issued a second certificate otherwise.
+	DefaultRetries = 3
+	// DefaultRetryBackoff is the delay before the first retry, doubling with
+	// each retry.
+	DefaultRetryBackoff = time.Second
+)
+
+// Client is a client of the v2 Fulcio API, over either REST or gRPC.
+// Errors returned by the server are gRPC status errors for both transports,
+// so they can be inspected with status.Code.
+type Client interface {
+	// CreateSigningCertificate requests a certificate for a public key or
+	// certificate signing request.
+	CreateSigningCertificate(ctx context.Context, req SigningCertificateRequest) (*SigningCertificate, error)
+	// GetTrustBundle returns the chains of the certificates that can issue
+	// certificates, each ordered from the issuing certificate to the root.
+	GetTrustBundle(ctx context.Context) ([][]*x509.Certificate, error)
+	// GetConfiguration returns the OIDC issuers the server accepts.
+	GetConfiguration(ctx context.Context) (*fulciogrpc.Configuration, error)
+	// Close releases the connections of the client.
+	Close() error
+}
+
+// SigningCertificateRequest is a request for a certificate. Exactly one of
+// PublicKey and CertificateSigningRequest must be set.
+type SigningCertificateRequest struct {
+	// Token is the OIDC identity token of the caller.
+	Token string
+	// PublicKey is the PEM-encoded public key to certify.
+	PublicKey []byte
+	// ProofOfPossession is a signature by the private key of PublicKey over
+	// Challenge if set, otherwise over the subject of Token.
+	ProofOfPossession []byte
+	// Challenge is a nonce returned by the server to sign as proof of
+	// possession.
+	Challenge string
+	// CertificateSigningRequest is a PEM-encoded PKCS#10 certificate signing
+	// request of the key to certify.
+	CertificateSigningRequest []byte
+	// Validity is the requested lifetime of the certificate, or zero for the
+	// issuer's default.
+	Validity time.Duration
+}
+
+// SigningCertificate is a certificate issued by Fulcio.
+type SigningCertificate struct {
+	// Certificate is the issued certificate.
+	Certificate *x509.Certificate
+	// Chain is the chain of Certificate, from its issuer to the root.
+	Chain []*x509.Certificate
+	// DetachedSCT is the SCT of the certificate when it is returned
+	// alongside the certificate, or nil when the SCT is embedded in the
+	// certificate or the certificate was not logged.
+	DetachedSCT *DetachedSCT
+	// Verification is the result of verifying the certificate, if the
+	// client was created WithVerifier.
+	Verification *VerificationResult
+}
+
+// DetachedSCT holds the SCTs of a certificate returned alongside it, each
+// a JSON-encoded AddChainResponse as defined in
+// https://github.com/google/certificate-transparency-go.
+type DetachedSCT struct {
+	// SCT is the SCT of the first CT log.
+	SCT []byte
+	// SCTs are the SCTs of every CT log the certificate was submitted to.
+	SCTs [][]byte
+}
+
+// Embedded reports whether the SCT of the certificate is embedded in it.
+func (c *SigningCertificate) Embedded() bool {
+	return c.DetachedSCT == nil
+}
+
+// WithRetries sets how many times the v2 clients retry requests that fail
+// because the server is unavailable. Requests for certificates are only
+// retried if they never reached the server. Defaults to DefaultRetries.
+func WithRetries(retries int) ClientOption {
+	return func(o *clientOptions) {
+		o.Retries = retries
+	}
+}
+
+// WithRetryBackoff sets the delay before the first retry of the v2 clients,
+// doubling with each retry. Defaults to DefaultRetryBackoff.
+func WithRetryBackoff(backoff time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.RetryBackoff = backoff
+	}
+}
+
+// WithGRPCDialOptions sets options used by NewGRPCClient to connect to the
+// server, such as its transport credentials.
+func WithGRPCDialOptions(opts ...grpc.DialOption) ClientOption {
+	return func(o *clientOptions) {
+		o.DialOptions = append(o.DialOptions, opts...)
+	}
+}
+
+// notSentError is the error of a request that never reached the server,
+// which can be retried even if the request is not idempotent.
+type notSentError struct {
+	err error
+}
+
+func (e notSentError) Error() string { return e.err.Error() }
+
+func (e notSentError) Unwrap() error { return e.err }
+
+// retry calls f until it succeeds, fails with an error other than
+// Unavailable, or the retries are exhausted. Unless idempotent is set, only
+// the failures of requests that never reached the server are retried.
+func (o *clientOptions) retry(ctx context.Context, idempotent bool, f func(context.Context) error) error {
+	backoff := o.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := f(ctx)
+		retryable := status.Code(err) == codes.Unavailable && (idempotent || errors.As(err, new(notSentError)))
+		if !retryable || attempt >= o.Retries {
+			var notSent notSentError
+			if errors.As(err, &notSent) {
+				return notSent.err
+			}
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// toProto returns the request for the v2 API.
+func (r SigningCertificateRequest) toProto() (*fulciogrpc.CreateSigningCertificateRequest, error) {
+	req := &fulciogrpc.CreateSigningCertificateRequest{
+		Credentials: &fulciogrpc.Credentials{
+			Credentials: &fulciogrpc.Credentials_OidcIdentityToken{OidcIdentityToken: r.Token},
+		},
+	}
+	switch {
+	case len(r.PublicKey) > 0 && len(r.CertificateSigningRequest) > 0:
+		return nil, errors.New("only one of a public key and a certificate signing request may be set")
+	case len(r.PublicKey) > 0:
+		req.Key = &fulciogrpc.CreateSigningCertificateRequest_PublicKeyRequest{
+			PublicKeyRequest: &fulciogrpc.PublicKeyRequest{
+				PublicKey:         &fulciogrpc.PublicKey{Content: string(r.PublicKey)},
+				ProofOfPossession: r.ProofOfPossession,
+				Challenge:         r.Challenge,
+			},
+		}
+	case len(r.CertificateSigningRequest) > 0:
+		req.Key = &fulciogrpc.CreateSigningCertificateRequest_CertificateSigningRequest{
+			CertificateSigningRequest: r.CertificateSigningRequest,
+		}
+	default:
+		return nil, errors.New("a public key or a certificate signing request is required")
+	}
+	if r.Validity > 0 {
+		req.RequestedValidity = durationpb.New(r.Validity)
+	}
+	return req, nil
+}
+
+// parseChain parses a chain of PEM-encoded certificates. Each certificate is
+// parsed on its own, as the server does not end them with a newline.
+func parseChain(chain *fulciogrpc.CertificateChain) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for _, c := range chain.GetCertificates() {
+		parsed, err := cryptoutils.UnmarshalCertificatesFromPEM([]byte(c))
+		if err != nil {
+			return nil, fmt.Errorf("parsing certificate chain: %w", err)
+		}
+		certs = append(certs, parsed...)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("empty certificate chain")
+	}
+	return certs, nil
+}
+
+// fromProto returns the certificate of a response of the v2 API.
+func fromProto(resp *fulciogrpc.SigningCertificate) (*SigningCertificate, error) {
+	var (
+		chain  *fulciogrpc.CertificateChain
+		detach *DetachedSCT
+	)
+	switch c := resp.GetCertificate().(type) {
+	case *fulciogrpc.SigningCertificate_SignedCertificateDetachedSct:
+		chain = c.SignedCertificateDetachedSct.GetChain()
+		detach = &DetachedSCT{
+			SCT:  c.SignedCertificateDetachedSct.GetSignedCertificateTimestamp(),
+			SCTs: c.SignedCertificateDetachedSct.GetSignedCertificateTimestamps(),
+		}
+		if len(detach.SCTs) == 0 && len(detach.SCT) > 0 {
+			detach.SCTs = [][]byte{detach.SCT}
+		}
+	case *fulciogrpc.SigningCertificate_SignedCertificateEmbeddedSct:
+		chain = c.SignedCertificateEmbeddedSct.GetChain()
+	default:
+		return nil, errors.New("response has no certificate")
+	}
+	certs, err := parseChain(chain)
+	if err != nil {
+		return nil, err
+	}
+	return &SigningCertificate{Certificate: certs[0], Chain: certs[1:], DetachedSCT: detach}, nil
+}
+
+// verifyIssued returns the certificate of a response to req, verified with
+// v if it is set.
+func verifyIssued(ctx context.Context, v *Verifier, trustBundle *trustBundleCache, req SigningCertificateRequest, resp *fulciogrpc.SigningCertificate, fetch func(context.Context) ([][]*x509.Certificate, error)) (*SigningCertificate, error) {
+	cert, err := fromProto(resp)
+	if err != nil || v == nil {
+		return cert, err
+	}
+	pub, err := parsePublicKey(req.PublicKey, req.CertificateSigningRequest)
+	if err != nil {
+		return nil, err
+	}
+	in := Issuance{Certificate: cert.Certificate, Chain: cert.Chain, PublicKey: pub, Token: req.Token}
+	if cert.DetachedSCT != nil {
+		in.DetachedSCTs = cert.DetachedSCT.SCTs
+	}
+	if cert.Verification, err = trustBundle.verify(ctx, v, in, fetch); err != nil {
+		return nil, fmt.Errorf("verifying issued certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// fromTrustBundle returns the chains of a trust bundle.
+func fromTrustBundle(resp *fulciogrpc.TrustBundle) ([][]*x509.Certificate, error) {
+	chains := make([][]*x509.Certificate, 0, len(resp.GetChains()))
+	for _, c := range resp.GetChains() {
+		chain, err := parseChain(c)
+		if err != nil {
+			return nil, err
+		}
+		chains = append(chains, chain)
+	}
+	return chains, nil
+}
+
+// NewRESTClient returns a client of the v2 API served at url.
+func NewRESTClient(url *url.URL, opts ...ClientOption) Client {
+	o := makeOptions(opts...)
+
+	return &restClient{
+		baseURL: url,
+		client: &http.Client{
+			Transport: createRoundTripper(http.DefaultTransport, o),
+			Timeout:   o.Timeout,
+		},
+		opts: o,
+	}
+}
+
+type restClient struct {
+	baseURL     *url.URL
+	client      *http.Client
+	opts        *clientOptions
+	trustBundle trustBundleCache
+}
+
+var _ Client = (*restClient)(nil)
+
+func (c *restClient) CreateSigningCertificate(ctx context.Context, req SigningCertificateRequest) (*SigningCertificate, error) {
+	request, err := req.toProto()
+	if err != nil {
+		return nil, err
+	}
+	resp := &fulciogrpc.SigningCertificate{}
+	if err := c.do(ctx, http.MethodPost, signingCertV2Path, false, request, resp); err != nil {
+		return nil, err
+	}
+	return verifyIssued(ctx, c.opts.Verifier, &c.trustBundle, req, resp, c.GetTrustBundle)
+}
+
+func (c *restClient) GetTrustBundle(ctx context.Context) ([][]*x509.Certificate, error) {
+	resp := &fulciogrpc.TrustBundle{}
+	if err := c.do(ctx, http.MethodGet, trustBundleV2Path, true, nil, resp); err != nil {
+		return nil, err
+	}
+	return fromTrustBundle(resp)
+}
+
+func (c *restClient) GetConfiguration(ctx context.Context) (*fulciogrpc.Configuration, error) {
+	resp := &fulciogrpc.Configuration{}
+	if err := c.do(ctx, http.MethodGet, configurationV2Path, true, nil, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *restClient) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+// do sends a request to the API endpoint at p, retrying while the server is
+// unavailable, and decodes the response into resp. Requests that are not
+// idempotent are only retried if they could not be sent.
+func (c *restClient) do(ctx context.Context, method, p string, idempotent bool, body, resp proto.Message) error {
+	endpoint := *c.baseURL
+	endpoint.Path = path.Join(endpoint.Path, p)
+
+	var b []byte
+	if body != nil {
+		var err error
+		if b, err = protojson.Marshal(body); err != nil {
+			return fmt.Errorf("marshal: %w", err)
+		}
+	}
+	return c.opts.retry(ctx, idempotent, func(ctx context.Context) error {
+		// Set by the transport once the request is on the wire
+		var sent atomic.Bool
+		ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+			WroteHeaders: func() { sent.Store(true) },
+		})
+		req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), bytes.NewReader(b))
+		if err != nil {
+			return fmt.Errorf("request: %w", err)
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		r, err := c.client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			err = status.Errorf(codes.Unavailable, "%s %s: %v", method, endpoint.String(), err)
+			if !sent.Load() {
+				return notSentError{err}
+			}
+			return err
+		}
+		defer r.Body.Close()
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			return status.Errorf(codes.Unavailable, "%s read: %v", endpoint.String(), err)
+		}
+		if r.StatusCode < 200 || r.StatusCode > 299 {
+			return restError(method, endpoint.String(), r, data)
+		}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, resp); err != nil {
+			return fmt.Errorf("unmarshal: %w", err)
+		}
+		return nil
+	})
+}
+
+// restError returns the gRPC status error of a failed request, as encoded
+// by the gateway, or one derived from the HTTP status if the body is not a
+// status.
+func restError(method, endpoint string, r *http.Response, body []byte) error {
+	var s struct {
+		Code    codes.Code `json:"code"`
+		Message string     `json:"message"`
+	}
+	if err := json.Unmarshal(body, &s); err == nil && s.Code != codes.OK {
+		return status.Error(s.Code, s.Message)
+	}
+	code := codes.Unknown
+	switch r.StatusCode {
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		code = codes.Unavailable
+	}
+	return status.Errorf(code, "%s %s returned %s: %q", method, endpoint, r.Status, body)
+}
+
+// NewGRPCClient returns a client of the v2 API served over gRPC at target.
+// The connection uses TLS with the system's roots unless other transport
+// credentials are set with WithGRPCDialOptions.
+func NewGRPCClient(target string, opts ...ClientOption) (Client, error) {
+	o := makeOptions(opts...)
+
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(nil, ""))}
+	if o.UserAgent != "" {
+		dialOpts = append(dialOpts, grpc.WithUserAgent(o.UserAgent))
+	}
+	conn, err := grpc.NewClient(target, append(dialOpts, o.DialOptions...)...)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", target, err)
+	}
+	return &grpcClient{conn: conn, client: fulciogrpc.NewCAClient(conn), opts: o}, nil
+}
+
+type grpcClient struct {
+	conn        *grpc.ClientConn
+	client      fulciogrpc.CAClient
+	opts        *clientOptions
+	trustBundle trustBundleCache
+}
+
+var _ Client = (*grpcClient)(nil)
+
+func (c *grpcClient) CreateSigningCertificate(ctx context.Context, req SigningCertificateRequest) (*SigningCertificate, error) {
+	request, err := req.toProto()
+	if err != nil {
+		return nil, err
+	}
+	var resp *fulciogrpc.SigningCertificate
+	if err := c.call(ctx, false, func(ctx context.Context) error {
+		// The peer is only set once the request is sent on a connection
+		var p peer.Peer
+		var err error
+		resp, err = c.client.CreateSigningCertificate(ctx, request, grpc.Peer(&p))
+		if err != nil && p.Addr == nil {
+			return notSentError{err}
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return verifyIssued(ctx, c.opts.Verifier, &c.trustBundle, req, resp, c.GetTrustBundle)
+}
+
+func (c *grpcClient) GetTrustBundle(ctx context.Context) ([][]*x509.Certificate, error) {
+	var resp *fulciogrpc.TrustBundle
+	if err := c.call(ctx, true, func(ctx context.Context) (err error) {
+		resp, err = c.client.GetTrustBundle(ctx, &fulciogrpc.GetTrustBundleRequest{})
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return fromTrustBundle(resp)
+}
+
+func (c *grpcClient) GetConfiguration(ctx context.Context) (*fulciogrpc.Configuration, error) {
+	var resp *fulciogrpc.Configuration
+	if err := c.call(ctx, true, func(ctx context.Context) (err error) {
+		resp, err = c.client.GetConfiguration(ctx, &fulciogrpc.GetConfigurationRequest{})
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *grpcClient) Close() error {
+	return c.conn.Close()
+}
+
+// call calls f, retrying while the server is unavailable, with each attempt
+// bounded by the client's timeout. Calls that are not idempotent are only
+// retried if f returns a notSentError.
+func (c *grpcClient) call(ctx context.Context, idempotent bool, f func(context.Context) error) error {
+	return c.opts.retry(ctx, idempotent, func(ctx context.Context) error {
+		if c.opts.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, c.opts.Timeout)
+			defer cancel()
+		}
+		return f(ctx)
+	})
+}
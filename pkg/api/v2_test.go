@@ -0,0 +1,300 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	fulciogrpc "github.com/sigstore/fulcio/pkg/generated/protobuf"
+	"github.com/sigstore/fulcio/pkg/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// fakeCA serves fixed certificates, after failing a number of requests as
+// unavailable.
+type fakeCA struct {
+	fulciogrpc.UnimplementedCAServer
+	chain   []string
+	leafKey *ecdsa.PrivateKey
+
+	mu          sync.Mutex
+	unavailable int
+	last        *fulciogrpc.CreateSigningCertificateRequest
+}
+
+func (f *fakeCA) fail() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.unavailable > 0 {
+		f.unavailable--
+		return status.Error(codes.Unavailable, "try again")
+	}
+	return nil
+}
+
+func (f *fakeCA) CreateSigningCertificate(_ context.Context, req *fulciogrpc.CreateSigningCertificateRequest) (*fulciogrpc.SigningCertificate, error) {
+	if err := f.fail(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	f.last = req
+	f.mu.Unlock()
+	if req.GetCredentials().GetOidcIdentityToken() == "invalid" {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	chain := &fulciogrpc.CertificateChain{Certificates: f.chain}
+	if req.GetCertificateSigningRequest() != nil {
+		return &fulciogrpc.SigningCertificate{
+			Certificate: &fulciogrpc.SigningCertificate_SignedCertificateEmbeddedSct{
+				SignedCertificateEmbeddedSct: &fulciogrpc.SigningCertificateEmbeddedSCT{Chain: chain},
+			},
+		}, nil
+	}
+	return &fulciogrpc.SigningCertificate{
+		Certificate: &fulciogrpc.SigningCertificate_SignedCertificateDetachedSct{
+			SignedCertificateDetachedSct: &fulciogrpc.SigningCertificateDetachedSCT{
+				Chain:                       chain,
+				SignedCertificateTimestamp:  []byte("sct1"),
+				SignedCertificateTimestamps: [][]byte{[]byte("sct1"), []byte("sct2")},
+			},
+		},
+	}, nil
+}
+
+func (f *fakeCA) GetTrustBundle(context.Context, *fulciogrpc.GetTrustBundleRequest) (*fulciogrpc.TrustBundle, error) {
+	if err := f.fail(); err != nil {
+		return nil, err
+	}
+	return &fulciogrpc.TrustBundle{Chains: []*fulciogrpc.CertificateChain{{Certificates: f.chain[1:]}}}, nil
+}
+
+func (f *fakeCA) GetConfiguration(context.Context, *fulciogrpc.GetConfigurationRequest) (*fulciogrpc.Configuration, error) {
+	if err := f.fail(); err != nil {
+		return nil, err
+	}
+	return &fulciogrpc.Configuration{Issuers: []*fulciogrpc.OIDCIssuer{{
+		Issuer:   &fulciogrpc.OIDCIssuer_IssuerUrl{IssuerUrl: "https://issuer.example.com"},
+		Audience: "sigstore",
+	}}}, nil
+}
+
+func newFakeCA(t *testing.T) *fakeCA {
+	t.Helper()
+	root, rootKey, err := test.GenerateRootCA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, leafKey, err := test.GenerateLeafCert("foo@example.com", "https://issuer.example.com", root, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chain []string
+	for _, c := range []*x509.Certificate{leaf, root} {
+		pem, err := cryptoutils.MarshalCertificateToPEM(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Like Fulcio, without a trailing newline
+		chain = append(chain, strings.TrimSpace(string(pem)))
+	}
+	return &fakeCA{chain: chain, leafKey: leafKey}
+}
+
+// clients returns REST and gRPC clients of ca, created with extraOpts.
+func clients(t *testing.T, ca *fakeCA, extraOpts ...ClientOption) map[string]Client {
+	t.Helper()
+	opts := append([]ClientOption{WithRetryBackoff(time.Millisecond), WithTimeout(10 * time.Second)}, extraOpts...)
+
+	mux := runtime.NewServeMux()
+	if err := fulciogrpc.RegisterCAHandlerServer(context.Background(), mux, ca); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer()
+	fulciogrpc.RegisterCAServer(s, ca)
+	go func() {
+		if err := s.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			t.Errorf("Serve() = %v", err)
+		}
+	}()
+	t.Cleanup(s.Stop)
+	grpcClient, err := NewGRPCClient(lis.Addr().String(), append(opts,
+		WithGRPCDialOptions(grpc.WithTransportCredentials(insecure.NewCredentials())))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { grpcClient.Close() })
+
+	return map[string]Client{
+		"rest": NewRESTClient(u, opts...),
+		"grpc": grpcClient,
+	}
+}
+
+func TestClient(t *testing.T) {
+	ca := newFakeCA(t)
+	for name, c := range clients(t, ca) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			cert, err := c.CreateSigningCertificate(ctx, SigningCertificateRequest{
+				Token:             "token",
+				PublicKey:         []byte("public key"),
+				ProofOfPossession: []byte("proof"),
+				Challenge:         "nonce",
+				Validity:          time.Hour,
+			})
+			if err != nil {
+				t.Fatalf("CreateSigningCertificate() = %v", err)
+			}
+			if cert.Embedded() || len(cert.DetachedSCT.SCTs) != 2 || string(cert.DetachedSCT.SCT) != "sct1" {
+				t.Errorf("unexpected SCTs %+v", cert.DetachedSCT)
+			}
+			if cert.Certificate.IsCA || len(cert.Chain) != 1 || !cert.Chain[0].IsCA {
+				t.Errorf("unexpected chain %v, %v", cert.Certificate.Subject, cert.Chain)
+			}
+			ca.mu.Lock()
+			last := ca.last
+			ca.mu.Unlock()
+			if pk := last.GetPublicKeyRequest(); pk.GetPublicKey().GetContent() != "public key" || string(pk.GetProofOfPossession()) != "proof" ||
+				pk.GetChallenge() != "nonce" || last.GetRequestedValidity().AsDuration() != time.Hour {
+				t.Errorf("unexpected request %v", last)
+			}
+
+			cert, err = c.CreateSigningCertificate(ctx, SigningCertificateRequest{
+				Token:                     "token",
+				CertificateSigningRequest: []byte("csr"),
+			})
+			if err != nil {
+				t.Fatalf("CreateSigningCertificate() = %v", err)
+			}
+			if !cert.Embedded() {
+				t.Error("expected an embedded SCT")
+			}
+
+			_, err = c.CreateSigningCertificate(ctx, SigningCertificateRequest{Token: "invalid", CertificateSigningRequest: []byte("csr")})
+			if status.Code(err) != codes.Unauthenticated {
+				t.Errorf("expected Unauthenticated, got %v", err)
+			}
+			if _, err := c.CreateSigningCertificate(ctx, SigningCertificateRequest{Token: "token"}); err == nil {
+				t.Error("expected an error for a request without a key")
+			}
+
+			ca.mu.Lock()
+			ca.unavailable = 2
+			ca.mu.Unlock()
+			chains, err := c.GetTrustBundle(ctx)
+			if err != nil {
+				t.Fatalf("GetTrustBundle() = %v", err)
+			}
+			if len(chains) != 1 || len(chains[0]) != 1 || !chains[0][0].IsCA {
+				t.Errorf("unexpected trust bundle %v", chains)
+			}
+
+			cfg, err := c.GetConfiguration(ctx)
+			if err != nil {
+				t.Fatalf("GetConfiguration() = %v", err)
+			}
+			if len(cfg.GetIssuers()) != 1 || cfg.GetIssuers()[0].GetIssuerUrl() != "https://issuer.example.com" {
+				t.Errorf("unexpected configuration %v", cfg)
+			}
+		})
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	ca := newFakeCA(t)
+	for name, c := range clients(t, ca) {
+		t.Run(name, func(t *testing.T) {
+			ca.mu.Lock()
+			ca.unavailable = DefaultRetries + 1
+			ca.mu.Unlock()
+			if _, err := c.GetTrustBundle(context.Background()); status.Code(err) != codes.Unavailable {
+				t.Errorf("expected Unavailable once the retries are exhausted, got %v", err)
+			}
+			// The server is available again
+			if _, err := c.GetTrustBundle(context.Background()); err != nil {
+				t.Errorf("GetTrustBundle() = %v", err)
+			}
+
+			// Issuance is not idempotent, so requests the server saw are not
+			// retried
+			ca.mu.Lock()
+			ca.unavailable = 1
+			ca.mu.Unlock()
+			req := SigningCertificateRequest{Token: "token", CertificateSigningRequest: []byte("csr")}
+			if _, err := c.CreateSigningCertificate(context.Background(), req); status.Code(err) != codes.Unavailable {
+				t.Errorf("expected Unavailable without a retry, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRetryNotSent(t *testing.T) {
+	o := makeOptions(WithRetryBackoff(time.Millisecond))
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+	for name, test := range map[string]struct {
+		idempotent bool
+		err        error
+		wantCalls  int
+	}{
+		"idempotent":         {idempotent: true, err: unavailable, wantCalls: 2},
+		"not sent":           {err: notSentError{unavailable}, wantCalls: 2},
+		"sent":               {err: unavailable, wantCalls: 1},
+		"not sent, rejected": {err: notSentError{status.Error(codes.InvalidArgument, "bad")}, wantCalls: 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			err := o.retry(context.Background(), test.idempotent, func(context.Context) error {
+				calls++
+				if calls == 1 {
+					return test.err
+				}
+				return nil
+			})
+			if calls != test.wantCalls {
+				t.Errorf("retry() made %d calls, want %d", calls, test.wantCalls)
+			}
+			if test.wantCalls == 1 && (errors.As(err, new(notSentError)) || status.Code(err) != status.Code(test.err)) {
+				t.Errorf("retry() = %v, want the error of the call", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,380 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/ctutil"
+	ctx509 "github.com/google/certificate-transparency-go/x509"
+	"github.com/google/certificate-transparency-go/x509util"
+	"github.com/sigstore/fulcio/pkg/certificate"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+var (
+	// ErrUntrustedChain is returned when an issued certificate does not
+	// chain to the trust bundle.
+	ErrUntrustedChain = errors.New("certificate does not chain to the trust bundle")
+	// ErrKeyMismatch is returned when the key of an issued certificate is
+	// not the key submitted.
+	ErrKeyMismatch = errors.New("certificate key does not match the submitted key")
+	// ErrIdentityMismatch is returned when the identity of an issued
+	// certificate is not the identity of the token.
+	ErrIdentityMismatch = errors.New("certificate identity does not match the token")
+	// ErrInvalidSCT is returned when an issued certificate has no SCT that
+	// verifies against the CT log keys.
+	ErrInvalidSCT = errors.New("certificate has no valid SCT")
+)
+
+// VerificationResult describes an issued certificate that was verified.
+type VerificationResult struct {
+	// Chain is the chain from the certificate to a root of the trust bundle.
+	Chain []*x509.Certificate
+	// Issuer is the OIDC issuer of the token, as recorded in the
+	// certificate.
+	Issuer string
+	// Identity is the subject alternative name that matched the token.
+	Identity string
+	// SCTs are the SCTs verified against the CT log keys, or empty if no
+	// CT log keys are configured.
+	SCTs []*ct.SignedCertificateTimestamp
+	// EmbeddedSCT reports whether the SCTs are embedded in the certificate.
+	EmbeddedSCT bool
+}
+
+// Issuance is a certificate issued for a request, to be verified.
+type Issuance struct {
+	// Certificate is the issued certificate.
+	Certificate *x509.Certificate
+	// Chain is the chain returned with Certificate.
+	Chain []*x509.Certificate
+	// DetachedSCTs are the SCTs returned alongside Certificate, each a
+	// JSON-encoded AddChainResponse, or empty if its SCTs are embedded.
+	DetachedSCTs [][]byte
+	// PublicKey is the key submitted for the certificate.
+	PublicKey crypto.PublicKey
+	// Token is the OIDC identity token submitted for the certificate.
+	Token string
+}
+
+// Verifier verifies that certificates issued by Fulcio chain to a trust
+// bundle, certify the submitted key and identity, and were logged by a
+// trusted CT log.
+type Verifier struct {
+	trustBundle      [][]*x509.Certificate
+	ctLogKeys        []crypto.PublicKey
+	identityClaim    string
+	expectedIdentity string
+
+	// ctLogs are the CT log keys by log ID
+	ctLogs map[[sha256.Size]byte]crypto.PublicKey
+}
+
+// VerifierOption configures the Verifier returned by NewVerifier.
+type VerifierOption func(*Verifier)
+
+// WithTrustBundle pins the chains certificates are verified against, each
+// ordered from the issuing certificate to the root. By default, clients
+// verify against the trust bundle fetched from the server.
+func WithTrustBundle(chains [][]*x509.Certificate) VerifierOption {
+	return func(v *Verifier) {
+		v.trustBundle = chains
+	}
+}
+
+// WithCTLogPublicKeys sets the keys of the CT logs trusted to log issued
+// certificates. If unset, SCTs are not verified.
+func WithCTLogPublicKeys(keys ...crypto.PublicKey) VerifierOption {
+	return func(v *Verifier) {
+		v.ctLogKeys = append(v.ctLogKeys, keys...)
+	}
+}
+
+// WithIdentityClaim sets the claim of the token holding the identity that
+// the certificate must have as a subject alternative name. Defaults to the
+// email claim if the token has one, and the subject otherwise, which are the
+// subject alternative names of email, SPIFFE and URI issuers.
+func WithIdentityClaim(claim string) VerifierOption {
+	return func(v *Verifier) {
+		v.identityClaim = claim
+	}
+}
+
+// WithExpectedIdentity sets the subject alternative name the certificate
+// must have, rather than a claim of the token. It is required for issuers
+// whose certificates identify the workload by a name that is not a claim,
+// such as Kubernetes service accounts and CI workflows.
+func WithExpectedIdentity(san string) VerifierOption {
+	return func(v *Verifier) {
+		v.expectedIdentity = san
+	}
+}
+
+// NewVerifier returns a Verifier for use with WithVerifier.
+func NewVerifier(opts ...VerifierOption) (*Verifier, error) {
+	v := &Verifier{}
+	for _, opt := range opts {
+		opt(v)
+	}
+	for _, chain := range v.trustBundle {
+		if len(chain) == 0 {
+			return nil, errors.New("trust bundle contains an empty chain")
+		}
+	}
+	v.ctLogs = make(map[[sha256.Size]byte]crypto.PublicKey, len(v.ctLogKeys))
+	for _, k := range v.ctLogKeys {
+		der, err := x509.MarshalPKIXPublicKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("marshalling CT log public key: %w", err)
+		}
+		v.ctLogs[sha256.Sum256(der)] = k
+	}
+	return v, nil
+}
+
+// WithVerifier makes the v2 and legacy clients verify each certificate
+// they are issued with v, failing the request if it does not verify.
+func WithVerifier(v *Verifier) ClientOption {
+	return func(o *clientOptions) {
+		o.Verifier = v
+	}
+}
+
+// Verify verifies a certificate against the pinned trust bundle.
+func (v *Verifier) Verify(in Issuance) (*VerificationResult, error) {
+	if len(v.trustBundle) == 0 {
+		return nil, errors.New("no trust bundle pinned")
+	}
+	return v.verify(in, v.trustBundle)
+}
+
+func (v *Verifier) verify(in Issuance, trustBundle [][]*x509.Certificate) (*VerificationResult, error) {
+	cert := in.Certificate
+	roots, intermediates := x509.NewCertPool(), x509.NewCertPool()
+	for _, chain := range trustBundle {
+		for _, c := range chain[:len(chain)-1] {
+			intermediates.AddCert(c)
+		}
+		roots.AddCert(chain[len(chain)-1])
+	}
+	for _, c := range in.Chain {
+		intermediates.AddCert(c)
+	}
+	chains, err := cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		// Certificates are short-lived, so are verified at issuance
+		CurrentTime: cert.NotBefore,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUntrustedChain, err)
+	}
+	result := &VerificationResult{Chain: chains[0]}
+
+	if err := cryptoutils.EqualKeys(in.PublicKey, cert.PublicKey); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeyMismatch, err)
+	}
+
+	if result.Issuer, result.Identity, err = v.verifyIdentity(cert, in.Token); err != nil {
+		return nil, err
+	}
+
+	if len(v.ctLogs) > 0 {
+		if result.SCTs, result.EmbeddedSCT, err = v.verifySCTs(result.Chain, in.DetachedSCTs); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// verifyIdentity checks that cert was issued by the issuer of token for the
+// expected identity, or the identity in token, returning them.
+func (v *Verifier) verifyIdentity(cert *x509.Certificate, token string) (string, string, error) {
+	claims, err := tokenClaims(token)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrIdentityMismatch, err)
+	}
+	exts, err := certificate.ParseExtensions(cert.Extensions)
+	if err != nil {
+		return "", "", fmt.Errorf("parsing certificate extensions: %w", err)
+	}
+	if iss, _ := claims["iss"].(string); exts.Issuer != iss {
+		return "", "", fmt.Errorf("%w: certificate issuer %q is not the token issuer %q", ErrIdentityMismatch, exts.Issuer, iss)
+	}
+
+	identity, source := v.expectedIdentity, "expected identity"
+	if identity == "" {
+		claim := v.identityClaim
+		if claim == "" {
+			claim = "sub"
+			if _, ok := claims["email"]; ok {
+				claim = "email"
+			}
+		}
+		identity, _ = claims[claim].(string)
+		if identity == "" {
+			return "", "", fmt.Errorf("%w: token has no %s claim", ErrIdentityMismatch, claim)
+		}
+		source = claim
+	}
+	if slices.Contains(cryptoutils.GetSubjectAlternateNames(cert), identity) {
+		return exts.Issuer, identity, nil
+	}
+	return "", "", fmt.Errorf("%w: no subject alternative name is the %s %q", ErrIdentityMismatch, source, identity)
+}
+
+// verifySCTs verifies the detached SCTs if any, or the SCTs embedded in the
+// first certificate of chain, returning those from trusted CT logs. At least
+// one SCT must be from a trusted CT log, and all of those must verify.
+func (v *Verifier) verifySCTs(chain []*x509.Certificate, detached [][]byte) ([]*ct.SignedCertificateTimestamp, bool, error) {
+	ctChain := make([]*ctx509.Certificate, 0, len(chain))
+	for _, c := range chain {
+		parsed, err := ctx509.ParseCertificate(c.Raw)
+		if err != nil {
+			return nil, false, fmt.Errorf("parsing certificate for SCT verification: %w", err)
+		}
+		ctChain = append(ctChain, parsed)
+	}
+
+	embedded := len(detached) == 0
+	var scts []*ct.SignedCertificateTimestamp
+	if embedded {
+		var err error
+		if scts, err = x509util.ParseSCTsFromSCTList(&ctChain[0].SCTList); err != nil {
+			return nil, true, fmt.Errorf("%w: parsing embedded SCTs: %v", ErrInvalidSCT, err)
+		}
+	} else {
+		for _, b := range detached {
+			var resp ct.AddChainResponse
+			if err := json.Unmarshal(b, &resp); err != nil {
+				return nil, false, fmt.Errorf("%w: parsing detached SCT: %v", ErrInvalidSCT, err)
+			}
+			sct, err := resp.ToSignedCertificateTimestamp()
+			if err != nil {
+				return nil, false, fmt.Errorf("%w: parsing detached SCT: %v", ErrInvalidSCT, err)
+			}
+			scts = append(scts, sct)
+		}
+	}
+
+	var verified []*ct.SignedCertificateTimestamp
+	for _, sct := range scts {
+		key, ok := v.ctLogs[sct.LogID.KeyID]
+		if !ok {
+			continue
+		}
+		if err := ctutil.VerifySCT(key, ctChain, sct, embedded); err != nil {
+			return nil, embedded, fmt.Errorf("%w: SCT of log %s: %v", ErrInvalidSCT, base64.StdEncoding.EncodeToString(sct.LogID.KeyID[:]), err)
+		}
+		verified = append(verified, sct)
+	}
+	if len(verified) == 0 {
+		return nil, embedded, fmt.Errorf("%w: none of %d SCTs is from a trusted CT log", ErrInvalidSCT, len(scts))
+	}
+	return verified, embedded, nil
+}
+
+// tokenClaims returns the claims of a JWT, without verifying it.
+func tokenClaims(token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("decoding token payload: %w", err)
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("parsing token claims: %w", err)
+	}
+	return claims, nil
+}
+
+// parsePublicKey parses a PEM or DER-encoded public key, or the key of a
+// PEM or DER-encoded certificate signing request.
+func parsePublicKey(key, csr []byte) (crypto.PublicKey, error) {
+	if len(csr) > 0 {
+		if block, _ := pem.Decode(csr); block != nil {
+			csr = block.Bytes
+		}
+		req, err := x509.ParseCertificateRequest(csr)
+		if err != nil {
+			return nil, fmt.Errorf("parsing certificate signing request: %w", err)
+		}
+		return req.PublicKey, nil
+	}
+	if block, _ := pem.Decode(key); block != nil {
+		key = block.Bytes
+	}
+	pub, err := x509.ParsePKIXPublicKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key: %w", err)
+	}
+	return pub, nil
+}
+
+// trustBundleCache holds the trust bundle fetched by a client, for
+// verifying certificates when none is pinned.
+type trustBundleCache struct {
+	mu     sync.Mutex
+	chains [][]*x509.Certificate
+}
+
+// get returns the cached trust bundle, fetching it if it has not been
+// fetched.
+func (c *trustBundleCache) get(ctx context.Context, fetch func(context.Context) ([][]*x509.Certificate, error)) ([][]*x509.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.chains != nil {
+		return c.chains, nil
+	}
+	chains, err := fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching trust bundle: %w", err)
+	}
+	if len(chains) == 0 {
+		return nil, errors.New("fetching trust bundle: server returned no chains")
+	}
+	c.chains = chains
+	return chains, nil
+}
+
+// verify verifies in with the client's verifier, against its pinned trust
+// bundle or the one fetched with fetch.
+func (c *trustBundleCache) verify(ctx context.Context, v *Verifier, in Issuance, fetch func(context.Context) ([][]*x509.Certificate, error)) (*VerificationResult, error) {
+	trustBundle := v.trustBundle
+	if len(trustBundle) == 0 {
+		var err error
+		if trustBundle, err = c.get(ctx, fetch); err != nil {
+			return nil, err
+		}
+	}
+	return v.verify(in, trustBundle)
+}
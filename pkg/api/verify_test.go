@@ -0,0 +1,342 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/tls"
+	ctx509 "github.com/google/certificate-transparency-go/x509"
+	"github.com/sigstore/fulcio/pkg/certificate"
+	"github.com/sigstore/fulcio/pkg/ctl"
+	"github.com/sigstore/fulcio/pkg/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+// unsignedToken returns a JWT with claims, which the verifier does not
+// check the signature of.
+func unsignedToken(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString(payload) + ".c2ln"
+}
+
+// signSCT returns a detached SCT of the first certificate of chain, signed
+// by key.
+func signSCT(t *testing.T, key *ecdsa.PrivateKey, chain []*x509.Certificate) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ctChain []*ctx509.Certificate
+	for _, c := range chain {
+		parsed, err := ctx509.ParseCertificate(c.Raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctChain = append(ctChain, parsed)
+	}
+	sct := ct.SignedCertificateTimestamp{
+		SCTVersion: ct.V1,
+		LogID:      ct.LogID{KeyID: sha256.Sum256(der)},
+		Timestamp:  uint64(time.Now().UnixMilli()),
+	}
+	leaf, err := ct.MerkleTreeLeafFromChain(ctChain, ct.X509LogEntryType, sct.Timestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ct.SerializeSCTSignatureInput(sct, ct.LogEntry{Leaf: *leaf})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := tls.CreateSignature(*key, tls.SHA256, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sct.Signature = ct.DigitallySigned(sig)
+	resp, err := ctl.ToAddChainResponse(&sct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// uriCert returns a certificate for the URI san issued by parent, and its
+// public key.
+func uriCert(t *testing.T, san string, parent *x509.Certificate, parentKey crypto.Signer) (*x509.Certificate, crypto.PublicKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(san)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer, err := certificate.Extensions{Issuer: "https://issuer.example.com"}.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		URIs:            []*url.URL{u},
+		NotBefore:       time.Now().Add(-time.Minute),
+		NotAfter:        time.Now().Add(time.Hour),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		ExtraExtensions: issuer,
+	}, parent, key.Public(), parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key.Public()
+}
+
+func TestVerifier(t *testing.T) {
+	root, rootKey, err := test.GenerateRootCA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, leafKey, err := test.GenerateLeafCert("foo@example.com", "https://issuer.example.com", root, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherRoot, otherRootKey, err := test.GenerateRootCA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherLeaf, _, err := test.GenerateLeafCert("foo@example.com", "https://issuer.example.com", otherRoot, otherRootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherLogKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := unsignedToken(t, map[string]any{
+		"iss":   "https://issuer.example.com",
+		"sub":   "1234",
+		"email": "foo@example.com",
+	})
+	issuance := Issuance{
+		Certificate:  leaf,
+		Chain:        []*x509.Certificate{root},
+		DetachedSCTs: [][]byte{signSCT(t, logKey, []*x509.Certificate{leaf, root})},
+		PublicKey:    leafKey.Public(),
+		Token:        token,
+	}
+
+	trusted := []VerifierOption{WithTrustBundle([][]*x509.Certificate{{root}}), WithCTLogPublicKeys(logKey.Public())}
+	v, err := NewVerifier(trusted...)
+	if err != nil {
+		t.Fatalf("NewVerifier() = %v", err)
+	}
+	result, err := v.Verify(issuance)
+	if err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+	if result.Identity != "foo@example.com" || result.Issuer != "https://issuer.example.com" ||
+		len(result.Chain) != 2 || len(result.SCTs) != 1 || result.EmbeddedSCT {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	tests := map[string]struct {
+		opts   []VerifierOption
+		modify func(*Issuance)
+		want   error
+	}{
+		"untrusted root": {
+			opts: []VerifierOption{WithTrustBundle([][]*x509.Certificate{{otherRoot}}), WithCTLogPublicKeys(logKey.Public())},
+			want: ErrUntrustedChain,
+		},
+		"other key": {
+			modify: func(in *Issuance) {
+				key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				in.PublicKey = key.Public()
+			},
+			want: ErrKeyMismatch,
+		},
+		"other email": {
+			modify: func(in *Issuance) {
+				in.Token = unsignedToken(t, map[string]any{"iss": "https://issuer.example.com", "email": "bar@example.com"})
+			},
+			want: ErrIdentityMismatch,
+		},
+		"other issuer": {
+			modify: func(in *Issuance) {
+				in.Token = unsignedToken(t, map[string]any{"iss": "https://other.example.com", "email": "foo@example.com"})
+			},
+			want: ErrIdentityMismatch,
+		},
+		"identity claim": {
+			opts: []VerifierOption{WithTrustBundle([][]*x509.Certificate{{root}}), WithIdentityClaim("sub")},
+			want: ErrIdentityMismatch,
+		},
+		"expected identity": {
+			opts: append([]VerifierOption{WithExpectedIdentity("foo@example.com")}, trusted...),
+			modify: func(in *Issuance) {
+				in.Token = unsignedToken(t, map[string]any{"iss": "https://issuer.example.com", "sub": "system:serviceaccount:ns:sa"})
+			},
+		},
+		"other expected identity": {
+			opts: append([]VerifierOption{WithExpectedIdentity("bar@example.com")}, trusted...),
+			want: ErrIdentityMismatch,
+		},
+		"suffix of a URI": {
+			opts: append([]VerifierOption{WithIdentityClaim("sub")}, trusted...),
+			modify: func(in *Issuance) {
+				uriLeaf, uriKey := uriCert(t, "https://example.com/repo/main", root, rootKey)
+				in.Certificate, in.PublicKey = uriLeaf, uriKey
+				in.DetachedSCTs = [][]byte{signSCT(t, logKey, []*x509.Certificate{uriLeaf, root})}
+				in.Token = unsignedToken(t, map[string]any{"iss": "https://issuer.example.com", "sub": "main"})
+			},
+			want: ErrIdentityMismatch,
+		},
+		"expected URI": {
+			opts: append([]VerifierOption{WithExpectedIdentity("https://example.com/repo/main")}, trusted...),
+			modify: func(in *Issuance) {
+				uriLeaf, uriKey := uriCert(t, "https://example.com/repo/main", root, rootKey)
+				in.Certificate, in.PublicKey = uriLeaf, uriKey
+				in.DetachedSCTs = [][]byte{signSCT(t, logKey, []*x509.Certificate{uriLeaf, root})}
+				in.Token = unsignedToken(t, map[string]any{"iss": "https://issuer.example.com", "sub": "main"})
+			},
+		},
+		"not a token": {
+			modify: func(in *Issuance) { in.Token = "not a token" },
+			want:   ErrIdentityMismatch,
+		},
+		"untrusted log": {
+			opts: []VerifierOption{WithTrustBundle([][]*x509.Certificate{{root}}), WithCTLogPublicKeys(otherLogKey.Public())},
+			want: ErrInvalidSCT,
+		},
+		"SCT of another certificate": {
+			modify: func(in *Issuance) {
+				in.DetachedSCTs = [][]byte{signSCT(t, logKey, []*x509.Certificate{otherLeaf, otherRoot})}
+			},
+			want: ErrInvalidSCT,
+		},
+		"no SCTs": {
+			modify: func(in *Issuance) { in.DetachedSCTs = nil },
+			want:   ErrInvalidSCT,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := test.opts
+			if opts == nil {
+				opts = trusted
+			}
+			v, err := NewVerifier(opts...)
+			if err != nil {
+				t.Fatalf("NewVerifier() = %v", err)
+			}
+			in := issuance
+			if test.modify != nil {
+				test.modify(&in)
+			}
+			if _, err := v.Verify(in); !errors.Is(err, test.want) {
+				t.Errorf("Verify() = %v, want %v", err, test.want)
+			}
+		})
+	}
+
+	// SCTs are not checked without CT log keys
+	v, err = NewVerifier(WithTrustBundle([][]*x509.Certificate{{root}}))
+	if err != nil {
+		t.Fatalf("NewVerifier() = %v", err)
+	}
+	in := issuance
+	in.DetachedSCTs = nil
+	if result, err := v.Verify(in); err != nil || len(result.SCTs) != 0 {
+		t.Errorf("Verify() = %+v, %v", result, err)
+	}
+}
+
+func TestClientVerification(t *testing.T) {
+	ca := newFakeCA(t)
+	pub, err := cryptoutils.MarshalPublicKeyToPEM(ca.leafKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, err := cryptoutils.MarshalPublicKeyToPEM(otherKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := unsignedToken(t, map[string]any{"iss": "https://issuer.example.com", "email": "foo@example.com"})
+
+	// The trust bundle is fetched from the server
+	v, err := NewVerifier()
+	if err != nil {
+		t.Fatalf("NewVerifier() = %v", err)
+	}
+	for name, c := range clients(t, ca, WithVerifier(v)) {
+		t.Run(name, func(t *testing.T) {
+			cert, err := c.CreateSigningCertificate(context.Background(), SigningCertificateRequest{
+				Token:             token,
+				PublicKey:         pub,
+				ProofOfPossession: []byte("proof"),
+			})
+			if err != nil {
+				t.Fatalf("CreateSigningCertificate() = %v", err)
+			}
+			if cert.Verification == nil || cert.Verification.Identity != "foo@example.com" {
+				t.Errorf("unexpected verification %+v", cert.Verification)
+			}
+
+			_, err = c.CreateSigningCertificate(context.Background(), SigningCertificateRequest{
+				Token:             token,
+				PublicKey:         otherPub,
+				ProofOfPossession: []byte("proof"),
+			})
+			if !errors.Is(err, ErrKeyMismatch) {
+				t.Errorf("expected a key mismatch, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit records every decision Fulcio makes on a certificate
+// request in a tamper-evident log.
+//
+// The log is a file of JSON Lines. Each entry holds the SHA-256 digest of
+// the line before it, so that removing or altering an entry breaks the
+// chain, and checkpoint entries periodically sign the digest of the chain
+// so far, so that the chain cannot be rewritten without the signing key.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+const (
+	// EntryDecision is the type of entries recording a Decision.
+	EntryDecision = "decision"
+	// EntryCheckpoint is the type of entries recording a Checkpoint.
+	EntryCheckpoint = "checkpoint"
+)
+
+const (
+	// OutcomeAttempted is recorded for requests that passed every check,
+	// before their certificate is signed. It is followed by an accepted or
+	// rejected decision for the same request, unless that could not be
+	// recorded, in which case a certificate may have been issued.
+	OutcomeAttempted = "attempted"
+	// OutcomeAccepted is the outcome of requests for which a certificate
+	// was issued.
+	OutcomeAccepted = "accepted"
+	// OutcomeRejected is the outcome of requests that failed.
+	OutcomeRejected = "rejected"
+)
+
+// Decision describes the outcome of a certificate request.
+type Decision struct {
+	// Outcome is OutcomeAttempted, OutcomeAccepted or OutcomeRejected.
+	Outcome string `json:"outcome"`
+	// Code is the name of the gRPC status code returned to the client. It
+	// is empty for attempts.
+	Code string `json:"code"`
+	// Reason is the message returned to the client.
+	Reason string `json:"reason,omitempty"`
+	// Error is the cause of a rejection, which may hold more detail than
+	// the message returned to the client.
+	Error string `json:"error,omitempty"`
+	// Issuer is the OIDC issuer of the identity token. It is recorded
+	// even if the token could not be authenticated.
+	Issuer string `json:"issuer,omitempty"`
+	// Subject is the name of the authenticated principal, such as an
+	// email address.
+	Subject string `json:"subject,omitempty"`
+	// SerialNumber is the hexadecimal serial number of the issued
+	// certificate.
+	SerialNumber string `json:"serialNumber,omitempty"`
+}
+
+// Checkpoint signs the chain of entries before it.
+type Checkpoint struct {
+	// Signature is over the SHA-256 digest in the checkpoint entry's
+	// PrevHash, which commits to every entry before it.
+	Signature []byte `json:"signature"`
+}
+
+// Entry is a line of the audit log.
+type Entry struct {
+	// Seq is the position of the entry in the log, starting at 1.
+	Seq uint64 `json:"seq"`
+	// Time is when the entry was written.
+	Time time.Time `json:"time"`
+	// Type is EntryDecision or EntryCheckpoint.
+	Type string `json:"type"`
+	// PrevHash is the hex-encoded SHA-256 digest of the previous line,
+	// without its trailing newline, or empty for the first entry.
+	PrevHash   string      `json:"prevHash,omitempty"`
+	Decision   *Decision   `json:"decision,omitempty"`
+	Checkpoint *Checkpoint `json:"checkpoint,omitempty"`
+}
+
+// Sink records decisions. Implementations must be safe for concurrent use.
+type Sink interface {
+	// Record appends a decision to the log.
+	Record(ctx context.Context, d *Decision) error
+	// Close releases the sink's resources.
+	Close() error
+}
+
+// hashLine returns the value of PrevHash for the entry after line.
+func hashLine(line []byte) string {
+	digest := sha256.Sum256(line)
+	return hex.EncodeToString(digest[:])
+}
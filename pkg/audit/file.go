@@ -0,0 +1,228 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sigstore/fulcio/pkg/log"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+const (
+	// DefaultCheckpointEvery is the number of decisions after which a
+	// checkpoint is written.
+	DefaultCheckpointEvery = 100
+	// DefaultCheckpointInterval is the time after which a checkpoint is
+	// written with the next decision.
+	DefaultCheckpointInterval = 5 * time.Minute
+)
+
+var metricCheckpointFailures = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "fulcio_audit_checkpoint_failures_total",
+	Help: "The total number of audit log checkpoints that could not be written, and were retried with the next decision",
+})
+
+// FileSink appends entries to a JSON Lines file. Entries are synced to disk
+// before Record returns.
+type FileSink struct {
+	signer   signature.Signer
+	every    int
+	interval time.Duration
+	now      func() time.Time
+
+	mu   sync.Mutex
+	f    *os.File
+	seq  uint64
+	prev string
+	// unsigned is the number of entries since the last checkpoint
+	unsigned       int
+	lastCheckpoint time.Time
+}
+
+// FileSinkOption configures the FileSink returned by NewFileSink.
+type FileSinkOption func(*FileSink)
+
+// WithSigner signs checkpoints with s. Without a signer, no checkpoints are
+// written, and the log is only protected by its hash chain.
+func WithSigner(s signature.Signer) FileSinkOption {
+	return func(fs *FileSink) {
+		fs.signer = s
+	}
+}
+
+// WithCheckpointEvery sets the number of decisions after which a checkpoint
+// is written. Defaults to DefaultCheckpointEvery.
+func WithCheckpointEvery(n int) FileSinkOption {
+	return func(fs *FileSink) {
+		fs.every = n
+	}
+}
+
+// WithCheckpointInterval sets the time after the last checkpoint at which
+// the next decision is followed by a checkpoint. Defaults to
+// DefaultCheckpointInterval.
+func WithCheckpointInterval(d time.Duration) FileSinkOption {
+	return func(fs *FileSink) {
+		fs.interval = d
+	}
+}
+
+// NewFileSink returns a FileSink that appends to the file at path, creating
+// it if needed. The hash chain continues from the last entry in the file.
+func NewFileSink(path string, opts ...FileSinkOption) (*FileSink, error) {
+	fs := &FileSink{
+		every:    DefaultCheckpointEvery,
+		interval: DefaultCheckpointInterval,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(fs)
+	}
+	if fs.every <= 0 {
+		return nil, fmt.Errorf("audit checkpoint count must be positive, got %d", fs.every)
+	}
+	if fs.interval <= 0 {
+		return nil, fmt.Errorf("audit checkpoint interval must be positive, got %v", fs.interval)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+	if err := fs.resume(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("reading audit log %s: %w", path, err)
+	}
+	fs.f = f
+	fs.lastCheckpoint = fs.now()
+	return fs, nil
+}
+
+// resume reads the position and digest of the last entry in f.
+func (fs *FileSink) resume(f *os.File) error {
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) > 0 {
+				return errors.New("last entry is incomplete")
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line = bytes.TrimSuffix(line, []byte("\n"))
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return fmt.Errorf("entry %d: %w", fs.seq+1, err)
+		}
+		fs.seq = e.Seq
+		fs.prev = hashLine(line)
+		if e.Type == EntryCheckpoint {
+			fs.unsigned = 0
+		} else {
+			fs.unsigned++
+		}
+	}
+}
+
+// Record appends d to the log, followed by a checkpoint if one is due. A
+// checkpoint that cannot be written does not fail Record, as d is already
+// logged; it is retried with the next decision.
+func (fs *FileSink) Record(_ context.Context, d *Decision) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if err := fs.append(&Entry{Type: EntryDecision, Decision: d}); err != nil {
+		return err
+	}
+	if fs.signer != nil && (fs.unsigned >= fs.every || fs.now().Sub(fs.lastCheckpoint) >= fs.interval) {
+		// Failing the request now would leave an accepted decision in the
+		// log for a certificate the client never received
+		if err := fs.checkpoint(); err != nil {
+			metricCheckpointFailures.Inc()
+			log.Logger.Errorf("writing audit checkpoint, retrying with the next decision: %v", err)
+		}
+	}
+	return nil
+}
+
+// Close writes a checkpoint over any unsigned entries and closes the file.
+func (fs *FileSink) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	var err error
+	if fs.signer != nil && fs.unsigned > 0 {
+		err = fs.checkpoint()
+	}
+	return errors.Join(err, fs.f.Close())
+}
+
+// checkpoint appends a checkpoint signing the chain so far. fs.mu must be
+// held.
+func (fs *FileSink) checkpoint() error {
+	digest, err := hex.DecodeString(fs.prev)
+	if err != nil {
+		return err
+	}
+	sig, err := fs.signer.SignMessage(bytes.NewReader(digest))
+	if err != nil {
+		return fmt.Errorf("signing audit checkpoint: %w", err)
+	}
+	if err := fs.append(&Entry{Type: EntryCheckpoint, Checkpoint: &Checkpoint{Signature: sig}}); err != nil {
+		return err
+	}
+	fs.unsigned = 0
+	fs.lastCheckpoint = fs.now()
+	return nil
+}
+
+// append writes e as the next entry in the chain. fs.mu must be held.
+func (fs *FileSink) append(e *Entry) error {
+	e.Seq = fs.seq + 1
+	e.Time = fs.now().UTC()
+	e.PrevHash = fs.prev
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := fs.f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+	if err := fs.f.Sync(); err != nil {
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+	fs.seq = e.Seq
+	fs.prev = hashLine(line)
+	if e.Type == EntryDecision {
+		fs.unsigned++
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func newTestSigner(t *testing.T) signature.SignerVerifier {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sv
+}
+
+func TestFileSink(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sv := newTestSigner(t)
+
+	s, err := NewFileSink(path, WithSigner(sv), WithCheckpointEvery(2))
+	if err != nil {
+		t.Fatalf("NewFileSink() = %v", err)
+	}
+	for _, outcome := range []string{OutcomeAccepted, OutcomeRejected, OutcomeAccepted} {
+		if err := s.Record(ctx, &Decision{Outcome: outcome}); err != nil {
+			t.Fatalf("Record() = %v", err)
+		}
+	}
+	// A checkpoint is written after the second decision, and on Close
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	// The chain continues when the log is reopened
+	s, err = NewFileSink(path, WithSigner(sv))
+	if err != nil {
+		t.Fatalf("NewFileSink() = %v", err)
+	}
+	if err := s.Record(ctx, &Decision{Outcome: OutcomeRejected}); err != nil {
+		t.Fatalf("Record() = %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	summary, err := Verify(f, sv)
+	if err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+	if *summary != (Summary{Decisions: 4, Checkpoints: 3}) {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+}
+
+func TestFileSinkCheckpointInterval(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sv := newTestSigner(t)
+
+	s, err := NewFileSink(path, WithSigner(sv), WithCheckpointInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("NewFileSink() = %v", err)
+	}
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	if err := s.Record(ctx, &Decision{Outcome: OutcomeAccepted}); err != nil {
+		t.Fatalf("Record() = %v", err)
+	}
+	now = now.Add(time.Hour)
+	if err := s.Record(ctx, &Decision{Outcome: OutcomeAccepted}); err != nil {
+		t.Fatalf("Record() = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary, err := Verify(strings.NewReader(string(data)), sv)
+	if err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+	if *summary != (Summary{Decisions: 2, Checkpoints: 1}) {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+}
+
+// flakySigner fails to sign while failing is set.
+type flakySigner struct {
+	signature.Signer
+	failing bool
+}
+
+func (s *flakySigner) SignMessage(message io.Reader, opts ...signature.SignOption) ([]byte, error) {
+	if s.failing {
+		return nil, errors.New("signer unavailable")
+	}
+	return s.Signer.SignMessage(message, opts...)
+}
+
+func TestFileSinkCheckpointFailure(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sv := newTestSigner(t)
+	signer := &flakySigner{Signer: sv, failing: true}
+
+	s, err := NewFileSink(path, WithSigner(signer), WithCheckpointEvery(1))
+	if err != nil {
+		t.Fatalf("NewFileSink() = %v", err)
+	}
+	// The decision is logged, so Record succeeds without its checkpoint
+	failures := testutil.ToFloat64(metricCheckpointFailures)
+	if err := s.Record(ctx, &Decision{Outcome: OutcomeAccepted}); err != nil {
+		t.Fatalf("Record() = %v", err)
+	}
+	if got := testutil.ToFloat64(metricCheckpointFailures); got != failures+1 {
+		t.Errorf("expected the failure to be counted, failures went from %v to %v", failures, got)
+	}
+	// The checkpoint is retried with the next decision
+	signer.failing = false
+	if err := s.Record(ctx, &Decision{Outcome: OutcomeAccepted}); err != nil {
+		t.Fatalf("Record() = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary, err := Verify(strings.NewReader(string(data)), sv)
+	if err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+	if *summary != (Summary{Decisions: 2, Checkpoints: 1}) {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+}
+
+func TestFileSinkWithoutSigner(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	s, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("NewFileSink() = %v", err)
+	}
+	if err := s.Record(context.Background(), &Decision{Outcome: OutcomeAccepted}); err != nil {
+		t.Fatalf("Record() = %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	summary, err := Verify(f, nil)
+	if err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+	if *summary != (Summary{Decisions: 1, Unsigned: 1}) {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+}
+
+func TestNewFileSinkIncompleteEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	if err := os.WriteFile(path, []byte(`{"seq":1,"type":"decision"`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileSink(path); err == nil {
+		t.Error("expected error opening a log with an incomplete entry")
+	}
+}
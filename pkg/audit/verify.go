@@ -0,0 +1,104 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+// Summary describes a verified audit log.
+type Summary struct {
+	// Decisions is the number of decision entries.
+	Decisions int
+	// Checkpoints is the number of checkpoint entries.
+	Checkpoints int
+	// Unsigned is the number of decisions after the last checkpoint, which
+	// are only protected by the hash chain.
+	Unsigned int
+}
+
+// Verify checks the hash chain of the audit log read from r, and the
+// signature of each checkpoint with v. If v is nil, checkpoints are not
+// accepted.
+func Verify(r io.Reader, v signature.Verifier) (*Summary, error) {
+	var (
+		s    Summary
+		seq  uint64
+		prev string
+	)
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) > 0 {
+				return nil, fmt.Errorf("entry %d is incomplete", seq+1)
+			}
+			return &s, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line = bytes.TrimSuffix(line, []byte("\n"))
+
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, fmt.Errorf("entry %d: %w", seq+1, err)
+		}
+		if e.Seq != seq+1 {
+			return nil, fmt.Errorf("entry %d has sequence number %d", seq+1, e.Seq)
+		}
+		if e.PrevHash != prev {
+			return nil, fmt.Errorf("entry %d does not chain to the previous entry", e.Seq)
+		}
+
+		switch e.Type {
+		case EntryDecision:
+			if e.Decision == nil {
+				return nil, fmt.Errorf("entry %d has no decision", e.Seq)
+			}
+			s.Decisions++
+			s.Unsigned++
+		case EntryCheckpoint:
+			if e.Checkpoint == nil {
+				return nil, fmt.Errorf("entry %d has no checkpoint", e.Seq)
+			}
+			if v == nil {
+				return nil, fmt.Errorf("entry %d is a checkpoint, but no key was given to verify it", e.Seq)
+			}
+			digest, err := hex.DecodeString(e.PrevHash)
+			if err != nil {
+				return nil, fmt.Errorf("entry %d: %w", e.Seq, err)
+			}
+			if err := v.VerifySignature(bytes.NewReader(e.Checkpoint.Signature), bytes.NewReader(digest)); err != nil {
+				return nil, fmt.Errorf("entry %d has an invalid signature: %w", e.Seq, err)
+			}
+			s.Checkpoints++
+			s.Unsigned = 0
+		default:
+			return nil, fmt.Errorf("entry %d has unknown type %q", e.Seq, e.Type)
+		}
+
+		seq = e.Seq
+		prev = hashLine(line)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+func TestVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sv := newTestSigner(t)
+	s, err := NewFileSink(path, WithSigner(sv), WithCheckpointEvery(2))
+	if err != nil {
+		t.Fatalf("NewFileSink() = %v", err)
+	}
+	for _, subject := range []string{"alice@example.com", "bob@example.com", "carol@example.com"} {
+		if err := s.Record(context.Background(), &Decision{Outcome: OutcomeAccepted, Subject: subject}); err != nil {
+			t.Fatalf("Record() = %v", err)
+		}
+	}
+	// Leave the last decision unsigned
+	if err := s.f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	lines = lines[:len(lines)-1]
+
+	summary, err := Verify(strings.NewReader(string(data)), sv)
+	if err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+	if *summary != (Summary{Decisions: 3, Checkpoints: 1, Unsigned: 1}) {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+
+	for name, test := range map[string]struct {
+		lines    []string
+		verifier signature.Verifier
+	}{
+		"altered entry": {
+			slices.Concat(lines[:1], []string{strings.Replace(lines[1], "bob", "eve", 1)}, lines[2:]),
+			sv,
+		},
+		"removed entry":     {slices.Concat(lines[:1], lines[2:]), sv},
+		"reordered entries": {slices.Concat(lines[1:2], lines[:1], lines[2:]), sv},
+		"incomplete entry":  {[]string{lines[0], strings.TrimSuffix(lines[1], "\n")}, sv},
+		"wrong key":         {lines, newTestSigner(t)},
+		"no key":            {lines, nil},
+		"invalid entry":     {append(slices.Clone(lines), "{}\n"), sv},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Verify(strings.NewReader(strings.Join(test.lines, "")), test.verifier)
+			if err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
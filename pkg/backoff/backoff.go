@@ -0,0 +1,71 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backoff retries failed operations with jittered exponential
+// backoff.
+package backoff
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff retries an operation up to Retries times. The delay before the
+// first retry is Initial, and doubles with each retry up to Max. Each delay
+// is randomly shortened by up to half to spread out retries.
+type Backoff struct {
+	Retries int
+	Initial time.Duration
+	Max     time.Duration
+}
+
+// Validate checks that b is usable. what names the retried operation in
+// errors.
+func (b Backoff) Validate(what string) error {
+	if b.Retries < 0 {
+		return fmt.Errorf("%s retries must not be negative, got %d", what, b.Retries)
+	}
+	if b.Initial < 0 || b.Max < b.Initial {
+		return fmt.Errorf("%s backoff must be between 0 and the maximum backoff, got %v and %v", what, b.Initial, b.Max)
+	}
+	return nil
+}
+
+// Retry calls op, numbering its attempts from 0, until it succeeds or has
+// been retried b.Retries times. A failed attempt with retries left is only
+// retried if retry returns true for its error. Retry stops as soon as ctx
+// is done, returning the error of the last attempt, or ctx.Err() if ctx was
+// done while waiting to retry.
+func (b Backoff) Retry(ctx context.Context, op func(attempt int) error, retry func(error) bool) error {
+	backoff := b.Initial
+	for attempt := 0; ; attempt++ {
+		err := op(attempt)
+		if err == nil || ctx.Err() != nil || attempt == b.Retries || !retry(err) {
+			return err
+		}
+
+		delay := backoff
+		if delay > 0 {
+			delay -= rand.N(delay/2 + 1)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		backoff = min(2*backoff, b.Max)
+	}
+}
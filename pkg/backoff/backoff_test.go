@@ -0,0 +1,97 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		b       Backoff
+		wantErr bool
+	}{
+		"valid":            {b: Backoff{Retries: 2, Initial: time.Millisecond, Max: time.Second}},
+		"no backoff":       {b: Backoff{Retries: 2}},
+		"negative retries": {b: Backoff{Retries: -1}, wantErr: true},
+		"negative backoff": {b: Backoff{Initial: -time.Second}, wantErr: true},
+		"max below initial": {
+			b:       Backoff{Initial: time.Second, Max: time.Millisecond},
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := test.b.Validate("test"); (err != nil) != test.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	failure := errors.New("unavailable")
+	permanent := errors.New("rejected")
+	b := Backoff{Retries: 2, Initial: time.Millisecond, Max: 2 * time.Millisecond}
+	retry := func(err error) bool { return !errors.Is(err, permanent) }
+
+	tests := map[string]struct {
+		// failures is the number of attempts that fail with err
+		failures  int
+		err       error
+		wantCalls int
+		wantErr   error
+	}{
+		"success":           {wantCalls: 1},
+		"retried":           {failures: 2, err: failure, wantCalls: 3},
+		"retries exhausted": {failures: 3, err: failure, wantCalls: 3, wantErr: failure},
+		"not retried":       {failures: 1, err: permanent, wantCalls: 1, wantErr: permanent},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			err := b.Retry(context.Background(), func(attempt int) error {
+				if attempt != calls {
+					t.Errorf("got attempt %d, want %d", attempt, calls)
+				}
+				calls++
+				if calls <= test.failures {
+					return test.err
+				}
+				return nil
+			}, retry)
+			if !errors.Is(err, test.wantErr) || (test.wantErr == nil && err != nil) {
+				t.Errorf("Retry() = %v, want %v", err, test.wantErr)
+			}
+			if calls != test.wantCalls {
+				t.Errorf("Retry() made %d calls, want %d", calls, test.wantCalls)
+			}
+		})
+	}
+
+	// Waiting to retry stops with the context
+	ctx, cancel := context.WithCancel(context.Background())
+	slow := Backoff{Retries: 1, Initial: time.Hour, Max: time.Hour}
+	err := slow.Retry(ctx, func(int) error {
+		cancel()
+		return failure
+	}, retry)
+	if !errors.Is(err, failure) {
+		t.Errorf("expected the error of the attempt once canceled, got %v", err)
+	}
+}
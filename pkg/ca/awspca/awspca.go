@@ -0,0 +1,296 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package awspca implements a CertificateAuthority that issues certificates
+// with AWS Private CA (ACM PCA).
+//
+// AWS Private CA only issues certificates for a CSR signed by the key being
+// certified, so this CA can only serve clients that send a CSR. The CSR
+// provides the public key and proof of possession, while the names and
+// extensions of the certificate come from Fulcio through an APIPassthrough
+// template. AWS Private CA assigns serial numbers itself, so a final
+// certificate cannot match a precertificate, and SCTs are always detached.
+package awspca
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/sigstore/fulcio/pkg/ca"
+	"github.com/sigstore/fulcio/pkg/identity"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+// passthroughTemplateARN is the template that takes the extensions of an
+// end-entity certificate from the APIPassthrough parameter.
+const passthroughTemplateARN = "arn:aws:acm-pca:::template/BlankEndEntityCertificate_APIPassthrough/V1"
+
+// pollInterval is how long to wait between checks whether an issued
+// certificate is ready.
+var pollInterval = time.Second
+
+type awsPCA struct {
+	arn              string
+	client           *client
+	signingAlgorithm string
+
+	mu          sync.Mutex
+	cachedRoots [][]*x509.Certificate
+}
+
+var _ ca.Reloader = (*awsPCA)(nil)
+
+// Option configures the CA returned by NewAWSPCA.
+type Option func(*awsPCA)
+
+// WithEndpoint sets the URL of the ACM PCA API, such as a VPC endpoint.
+// Defaults to the regional endpoint of the certificate authority.
+func WithEndpoint(endpoint string) Option {
+	return func(p *awsPCA) {
+		p.client.endpoint = endpoint
+	}
+}
+
+// WithCredentials sets the AWS credentials to use. By default they are
+// loaded from the environment, shared configuration or instance role.
+func WithCredentials(credentials aws.CredentialsProvider) Option {
+	return func(p *awsPCA) {
+		p.client.credentials = credentials
+	}
+}
+
+// NewAWSPCA returns a CertificateAuthority that issues certificates with the
+// AWS Private CA certificate authority caARN. The certificate authority's
+// chain is fetched on creation, so that a misconfigured CA is reported at
+// startup.
+func NewAWSPCA(ctx context.Context, caARN string, opts ...Option) (ca.CertificateAuthority, error) {
+	parsed, err := arn.Parse(caARN)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate authority ARN %q: %w", caARN, err)
+	}
+	if parsed.Service != "acm-pca" || !strings.HasPrefix(parsed.Resource, "certificate-authority/") {
+		return nil, fmt.Errorf("%q is not an AWS Private CA certificate authority ARN", caARN)
+	}
+	domain := "amazonaws.com"
+	if parsed.Partition == "aws-cn" {
+		domain = "amazonaws.com.cn"
+	}
+
+	p := &awsPCA{
+		arn: caARN,
+		client: &client{
+			endpoint:   fmt.Sprintf("https://acm-pca.%s.%s", parsed.Region, domain),
+			region:     parsed.Region,
+			signer:     v4.NewSigner(),
+			httpClient: http.DefaultClient,
+		},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.client.credentials == nil {
+		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(parsed.Region))
+		if err != nil {
+			return nil, fmt.Errorf("loading AWS configuration: %w", err)
+		}
+		p.client.credentials = cfg.Credentials
+	}
+
+	chains, err := p.TrustBundle(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.signingAlgorithm, err = signingAlgorithm(chains[0][0].PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// signingAlgorithm returns the ACM PCA signing algorithm for a CA key.
+func signingAlgorithm(pub crypto.PublicKey) (string, error) {
+	switch pub := pub.(type) {
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return "SHA256WITHECDSA", nil
+		case elliptic.P384():
+			return "SHA384WITHECDSA", nil
+		case elliptic.P521():
+			return "SHA512WITHECDSA", nil
+		}
+		return "", fmt.Errorf("unsupported certificate authority curve %s", pub.Curve.Params().Name)
+	case *rsa.PublicKey:
+		return "SHA256WITHRSA", nil
+	default:
+		return "", fmt.Errorf("unsupported certificate authority key type %T", pub)
+	}
+}
+
+func (p *awsPCA) CreateCertificate(ctx context.Context, principal identity.Principal, publicKey crypto.PublicKey) (*ca.CodeSigningCertificate, error) {
+	csr := ca.CertificateRequestFromContext(ctx)
+	if csr == nil {
+		return nil, ca.ValidationError{Err: errors.New("this certificate authority requires a certificate signing request")}
+	}
+	if err := cryptoutils.EqualKeys(csr.PublicKey, publicKey); err != nil {
+		return nil, ca.ValidationError{Err: err}
+	}
+	cert, err := ca.MakeX509(ctx, principal, publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var issued issueCertificateOutput
+	err = p.client.call(ctx, "IssueCertificate", &issueCertificateInput{
+		APIPassthrough:          passthrough(cert),
+		CertificateAuthorityArn: p.arn,
+		Csr:                     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr.Raw}),
+		SigningAlgorithm:        p.signingAlgorithm,
+		TemplateArn:             passthroughTemplateARN,
+		Validity:                validity{Type: "ABSOLUTE", Value: cert.NotAfter.Unix()},
+		ValidityNotBefore:       &validity{Type: "ABSOLUTE", Value: cert.NotBefore.Unix()},
+	}, &issued)
+	if err != nil {
+		return nil, err
+	}
+	out, err := p.getCertificate(ctx, issued.CertificateArn)
+	if err != nil {
+		return nil, err
+	}
+
+	certs, err := cryptoutils.UnmarshalCertificatesFromPEM([]byte(out.Certificate))
+	if err != nil {
+		return nil, fmt.Errorf("parsing issued certificate: %w", err)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("AWS Private CA returned no certificate")
+	}
+	chain, err := cryptoutils.UnmarshalCertificatesFromPEM([]byte(out.CertificateChain))
+	if err != nil {
+		return nil, fmt.Errorf("parsing issued certificate chain: %w", err)
+	}
+	if err := cryptoutils.EqualKeys(certs[0].PublicKey, publicKey); err != nil {
+		return nil, fmt.Errorf("AWS Private CA issued a certificate for a different key: %w", err)
+	}
+	return ca.CreateCSCFromDER(certs[0].Raw, chain)
+}
+
+// getCertificate waits until the certificate certARN has been issued.
+func (p *awsPCA) getCertificate(ctx context.Context, certARN string) (*certificateOutput, error) {
+	in := &getCertificateInput{
+		CertificateArn:          certARN,
+		CertificateAuthorityArn: p.arn,
+	}
+	for {
+		var out certificateOutput
+		err := p.client.call(ctx, "GetCertificate", in, &out)
+		if err == nil {
+			return &out, nil
+		}
+		var apiErr *apiError
+		if !errors.As(err, &apiErr) || apiErr.Code != errRequestInProgress {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// passthrough returns the APIPassthrough parameter that gives the issued
+// certificate the names and extensions of cert.
+func passthrough(cert *x509.Certificate) *apiPassthrough {
+	ext := &extensions{
+		ExtendedKeyUsage: []extendedKeyUsage{{ExtendedKeyUsageType: "CODE_SIGNING"}},
+		KeyUsage:         &keyUsage{DigitalSignature: true},
+	}
+	for _, email := range cert.EmailAddresses {
+		ext.SubjectAlternativeNames = append(ext.SubjectAlternativeNames, generalName{Rfc822Name: email})
+	}
+	for _, uri := range cert.URIs {
+		ext.SubjectAlternativeNames = append(ext.SubjectAlternativeNames, generalName{UniformResourceIdentifier: uri.String()})
+	}
+	for _, name := range cert.DNSNames {
+		ext.SubjectAlternativeNames = append(ext.SubjectAlternativeNames, generalName{DNSName: name})
+	}
+	for _, ip := range cert.IPAddresses {
+		ext.SubjectAlternativeNames = append(ext.SubjectAlternativeNames, generalName{IPAddress: ip.String()})
+	}
+	for _, e := range cert.ExtraExtensions {
+		ext.CustomExtensions = append(ext.CustomExtensions, customExtension{
+			ObjectIdentifier: e.Id.String(),
+			Value:            base64.StdEncoding.EncodeToString(e.Value),
+			Critical:         e.Critical,
+		})
+	}
+	return &apiPassthrough{Extensions: ext}
+}
+
+func (p *awsPCA) TrustBundle(ctx context.Context) ([][]*x509.Certificate, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cachedRoots != nil {
+		return p.cachedRoots, nil
+	}
+
+	var out certificateOutput
+	err := p.client.call(ctx, "GetCertificateAuthorityCertificate", &getCertificateAuthorityCertificateInput{
+		CertificateAuthorityArn: p.arn,
+	}, &out)
+	if err != nil {
+		return nil, err
+	}
+	// The chain is empty for a root certificate authority
+	certs, err := cryptoutils.UnmarshalCertificatesFromPEM([]byte(out.Certificate + "\n" + out.CertificateChain))
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing certificate authority chain: %w", err)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("error fetching certificate authority chain")
+	}
+	p.cachedRoots = [][]*x509.Certificate{certs}
+	return p.cachedRoots, nil
+}
+
+// Reload drops the cached certificate authority chain, so that it is fetched
+// again after the certificate authority's certificate is renewed.
+func (p *awsPCA) Reload() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cachedRoots = nil
+	return nil
+}
+
+func (p *awsPCA) Close() error {
+	return nil
+}
@@ -0,0 +1,344 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package awspca
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/sigstore/fulcio/pkg/ca"
+	"github.com/sigstore/fulcio/pkg/config"
+	"github.com/sigstore/fulcio/pkg/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+const testARN = "arn:aws:acm-pca:us-east-1:123456789012:certificate-authority/11111111-2222-3333-4444-555555555555"
+
+var testExtension = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+
+type testPrincipal struct{}
+
+func (tp testPrincipal) Name(context.Context) string {
+	return "doesntmatter"
+}
+
+func (tp testPrincipal) Embed(_ context.Context, cert *x509.Certificate) error {
+	cert.EmailAddresses = []string{"alice@example.com"}
+	cert.ExtraExtensions = append(cert.ExtraExtensions, pkix.Extension{Id: testExtension, Value: []byte("https://issuer.example.com")})
+	return nil
+}
+
+// fakePCA is a local stand-in for the ACM PCA API. Certificates are issued
+// from the CSR's public key and the APIPassthrough extensions, and are only
+// returned by GetCertificate after pending calls.
+type fakePCA struct {
+	t       *testing.T
+	certs   []*x509.Certificate
+	signer  crypto.Signer
+	pending int
+
+	mu         sync.Mutex
+	operations []string
+	issued     map[string]string
+	polls      int
+}
+
+func newFakePCA(t *testing.T) *fakePCA {
+	t.Helper()
+	rootCert, rootKey, err := test.GenerateRootCA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	subCert, subKey, err := test.GenerateSubordinateCA(rootCert, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &fakePCA{t: t, certs: []*x509.Certificate{subCert, rootCert}, signer: subKey, issued: map[string]string{}}
+}
+
+func encodePEM(certs ...*x509.Certificate) string {
+	var b strings.Builder
+	for _, c := range certs {
+		b.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Raw}))
+	}
+	return b.String()
+}
+
+func (f *fakePCA) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		f.fail(w, http.StatusForbidden, "AccessDeniedException", "unsigned request")
+		return
+	}
+	operation := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "ACMPrivateCA.")
+	f.operations = append(f.operations, operation)
+	switch operation {
+	case "GetCertificateAuthorityCertificate":
+		f.respond(w, certificateOutput{Certificate: encodePEM(f.certs[0]), CertificateChain: encodePEM(f.certs[1])})
+	case "IssueCertificate":
+		var in issueCertificateInput
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			f.fail(w, http.StatusBadRequest, "ValidationException", err.Error())
+			return
+		}
+		certPEM, err := f.issue(&in)
+		if err != nil {
+			f.fail(w, http.StatusBadRequest, "MalformedCSRException", err.Error())
+			return
+		}
+		arn := fmt.Sprintf("%s/certificate/%d", in.CertificateAuthorityArn, len(f.issued))
+		f.issued[arn] = certPEM
+		f.respond(w, issueCertificateOutput{CertificateArn: arn})
+	case "GetCertificate":
+		var in getCertificateInput
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			f.fail(w, http.StatusBadRequest, "ValidationException", err.Error())
+			return
+		}
+		if f.polls < f.pending {
+			f.polls++
+			f.fail(w, http.StatusBadRequest, "com.amazonaws.acmpca#"+errRequestInProgress, "pending")
+			return
+		}
+		certPEM, ok := f.issued[in.CertificateArn]
+		if !ok {
+			f.fail(w, http.StatusBadRequest, "ResourceNotFoundException", "no such certificate")
+			return
+		}
+		f.respond(w, certificateOutput{Certificate: certPEM, CertificateChain: encodePEM(f.certs...)})
+	default:
+		f.fail(w, http.StatusBadRequest, "UnknownOperationException", operation)
+	}
+}
+
+func (f *fakePCA) issue(in *issueCertificateInput) (string, error) {
+	block, _ := pem.Decode(in.Csr)
+	if block == nil {
+		return "", errors.New("invalid CSR")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return "", err
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return "", err
+	}
+	if in.TemplateArn != passthroughTemplateARN || in.SigningAlgorithm != "SHA256WITHECDSA" || in.Validity.Type != "ABSOLUTE" {
+		return "", fmt.Errorf("unexpected request %+v", in)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		NotBefore:    time.Unix(in.ValidityNotBefore.Value, 0),
+		NotAfter:     time.Unix(in.Validity.Value, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	ext := in.APIPassthrough.Extensions
+	for _, name := range ext.SubjectAlternativeNames {
+		if name.Rfc822Name != "" {
+			tmpl.EmailAddresses = append(tmpl.EmailAddresses, name.Rfc822Name)
+		}
+		if name.UniformResourceIdentifier != "" {
+			u, err := url.Parse(name.UniformResourceIdentifier)
+			if err != nil {
+				return "", err
+			}
+			tmpl.URIs = append(tmpl.URIs, u)
+		}
+	}
+	for _, e := range ext.CustomExtensions {
+		var id asn1.ObjectIdentifier
+		for _, arc := range strings.Split(e.ObjectIdentifier, ".") {
+			var n int
+			if _, err := fmt.Sscan(arc, &n); err != nil {
+				return "", err
+			}
+			id = append(id, n)
+		}
+		value, err := base64.StdEncoding.DecodeString(e.Value)
+		if err != nil {
+			return "", err
+		}
+		tmpl.ExtraExtensions = append(tmpl.ExtraExtensions, pkix.Extension{Id: id, Critical: e.Critical, Value: value})
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, f.certs[0], csr.PublicKey, f.signer)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), nil
+}
+
+func (f *fakePCA) respond(w http.ResponseWriter, out interface{}) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	if err := json.NewEncoder(w).Encode(out); err != nil {
+		f.t.Error(err)
+	}
+}
+
+func (f *fakePCA) fail(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"__type": code, "message": message})
+}
+
+func newTestPCA(t *testing.T, f *fakePCA) (*awsPCA, error) {
+	t.Helper()
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	c, err := NewAWSPCA(context.Background(), testARN,
+		WithEndpoint(server.URL),
+		WithCredentials(credentials.NewStaticCredentialsProvider("AKID", "SECRET", "")))
+	if err != nil {
+		return nil, err
+	}
+	return c.(*awsPCA), nil
+}
+
+func newCSR(t *testing.T) (*x509.CertificateRequest, crypto.PublicKey) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return csr, priv.Public()
+}
+
+func TestNewAWSPCA(t *testing.T) {
+	for _, arn := range []string{
+		"",
+		"not-an-arn",
+		"arn:aws:kms:us-east-1:123456789012:key/1234",
+	} {
+		if _, err := NewAWSPCA(context.Background(), arn); err == nil {
+			t.Errorf("expected error for ARN %q", arn)
+		}
+	}
+
+	f := newFakePCA(t)
+	p, err := newTestPCA(t, f)
+	if err != nil {
+		t.Fatalf("NewAWSPCA() = %v", err)
+	}
+	chains, err := p.TrustBundle(context.Background())
+	if err != nil {
+		t.Fatalf("TrustBundle() = %v", err)
+	}
+	if len(chains) != 1 || len(chains[0]) != 2 || !chains[0][0].Equal(f.certs[0]) || !chains[0][1].Equal(f.certs[1]) {
+		t.Error("expected trust bundle with the certificate authority chain")
+	}
+
+	// The chain is cached until reloaded
+	if err := p.Reload(); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+	if _, err := p.TrustBundle(context.Background()); err != nil {
+		t.Fatalf("TrustBundle() = %v", err)
+	}
+	if len(f.operations) != 2 {
+		t.Errorf("expected chain to be fetched on creation and after reload, got %v", f.operations)
+	}
+}
+
+func TestCreateCertificate(t *testing.T) {
+	pollInterval = time.Millisecond
+	f := newFakePCA(t)
+	f.pending = 2
+	p, err := newTestPCA(t, f)
+	if err != nil {
+		t.Fatalf("NewAWSPCA() = %v", err)
+	}
+	csr, pub := newCSR(t)
+	ctx := ca.WithCertificateRequest(context.Background(), csr)
+
+	csc, err := p.CreateCertificate(ctx, testPrincipal{}, pub)
+	if err != nil {
+		t.Fatalf("CreateCertificate() = %v", err)
+	}
+	if f.polls != 2 {
+		t.Errorf("expected certificate to be polled until issued, got %d polls", f.polls)
+	}
+	cert := csc.FinalCertificate
+	if err := cryptoutils.EqualKeys(cert.PublicKey, pub); err != nil {
+		t.Errorf("expected certificate for the requested key: %v", err)
+	}
+	if err := cert.CheckSignatureFrom(f.certs[0]); err != nil {
+		t.Errorf("expected certificate signed by the certificate authority: %v", err)
+	}
+	if len(cert.EmailAddresses) != 1 || cert.EmailAddresses[0] != "alice@example.com" {
+		t.Errorf("unexpected email addresses %v", cert.EmailAddresses)
+	}
+	var found bool
+	for _, e := range cert.Extensions {
+		found = found || e.Id.Equal(testExtension)
+	}
+	if !found {
+		t.Error("expected Fulcio extension in certificate")
+	}
+	if lifetime := time.Until(cert.NotAfter); lifetime > config.DefaultCertificateLifetime || lifetime < config.DefaultCertificateLifetime-time.Minute {
+		t.Errorf("unexpected certificate lifetime %v", lifetime)
+	}
+	if len(csc.FinalChain) != 2 {
+		t.Errorf("expected 2 certificates in chain, got %d", len(csc.FinalChain))
+	}
+
+	// Requests without a CSR cannot be issued
+	_, err = p.CreateCertificate(context.Background(), testPrincipal{}, pub)
+	if !errors.As(err, new(ca.ValidationError)) {
+		t.Errorf("expected validation error without CSR, got %v", err)
+	}
+	_, other := newCSR(t)
+	_, err = p.CreateCertificate(ctx, testPrincipal{}, other)
+	if !errors.As(err, new(ca.ValidationError)) {
+		t.Errorf("expected validation error for key not in CSR, got %v", err)
+	}
+
+	// The wait for issuance is bounded by the request
+	f.polls, f.pending = 0, 1000
+	cctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := p.CreateCertificate(cctx, testPrincipal{}, pub); err == nil {
+		t.Error("expected error when issuance does not complete")
+	}
+}
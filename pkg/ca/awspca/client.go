@@ -0,0 +1,177 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package awspca
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// errRequestInProgress is the error code returned by GetCertificate until
+// an issued certificate is ready.
+const errRequestInProgress = "RequestInProgressException"
+
+// apiError is an error returned by the ACM PCA API.
+type apiError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("acm-pca: %s (HTTP %d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+// client calls the ACM PCA JSON API. Only the few operations the CA needs
+// are implemented, which avoids depending on the generated service SDK.
+type client struct {
+	endpoint    string
+	region      string
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	httpClient  *http.Client
+}
+
+// call invokes the named operation with in, and decodes the response into out.
+func (c *client) call(ctx context.Context, operation string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "ACMPrivateCA."+operation)
+
+	creds, err := c.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("retrieving AWS credentials: %w", err)
+	}
+	hash := sha256.Sum256(body)
+	if err := c.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "acm-pca", c.region, time.Now()); err != nil {
+		return fmt.Errorf("signing request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(data, &e)
+		// Error types may be qualified, as in namespace#Code
+		code := e.Type[strings.LastIndex(e.Type, "#")+1:]
+		if code == "" {
+			code = resp.Status
+		}
+		return &apiError{StatusCode: resp.StatusCode, Code: code, Message: e.Message}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// The types below mirror the request and response shapes of the ACM PCA API.
+
+type validity struct {
+	Type  string `json:"Type"`
+	Value int64  `json:"Value"`
+}
+
+type generalName struct {
+	DNSName                   string `json:"DnsName,omitempty"`
+	IPAddress                 string `json:"IpAddress,omitempty"`
+	Rfc822Name                string `json:"Rfc822Name,omitempty"`
+	UniformResourceIdentifier string `json:"UniformResourceIdentifier,omitempty"`
+}
+
+type customExtension struct {
+	ObjectIdentifier string `json:"ObjectIdentifier"`
+	Value            string `json:"Value"`
+	Critical         bool   `json:"Critical,omitempty"`
+}
+
+type extendedKeyUsage struct {
+	ExtendedKeyUsageType string `json:"ExtendedKeyUsageType"`
+}
+
+type keyUsage struct {
+	DigitalSignature bool `json:"DigitalSignature"`
+}
+
+type extensions struct {
+	CustomExtensions        []customExtension  `json:"CustomExtensions,omitempty"`
+	ExtendedKeyUsage        []extendedKeyUsage `json:"ExtendedKeyUsage,omitempty"`
+	KeyUsage                *keyUsage          `json:"KeyUsage,omitempty"`
+	SubjectAlternativeNames []generalName      `json:"SubjectAlternativeNames,omitempty"`
+}
+
+type apiPassthrough struct {
+	Extensions *extensions `json:"Extensions,omitempty"`
+}
+
+type issueCertificateInput struct {
+	APIPassthrough          *apiPassthrough `json:"ApiPassthrough,omitempty"`
+	CertificateAuthorityArn string          `json:"CertificateAuthorityArn"`
+	Csr                     []byte          `json:"Csr"`
+	IdempotencyToken        string          `json:"IdempotencyToken,omitempty"`
+	SigningAlgorithm        string          `json:"SigningAlgorithm"`
+	TemplateArn             string          `json:"TemplateArn"`
+	Validity                validity        `json:"Validity"`
+	ValidityNotBefore       *validity       `json:"ValidityNotBefore,omitempty"`
+}
+
+type issueCertificateOutput struct {
+	CertificateArn string `json:"CertificateArn"`
+}
+
+type getCertificateInput struct {
+	CertificateArn          string `json:"CertificateArn"`
+	CertificateAuthorityArn string `json:"CertificateAuthorityArn"`
+}
+
+type getCertificateAuthorityCertificateInput struct {
+	CertificateAuthorityArn string `json:"CertificateAuthorityArn"`
+}
+
+// certificateOutput is returned by both GetCertificate and
+// GetCertificateAuthorityCertificate.
+type certificateOutput struct {
+	Certificate      string `json:"Certificate"`
+	CertificateChain string `json:"CertificateChain"`
+}
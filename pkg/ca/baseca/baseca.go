@@ -22,12 +22,15 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
+	"errors"
 
 	ct "github.com/google/certificate-transparency-go"
 	cttls "github.com/google/certificate-transparency-go/tls"
 	ctx509 "github.com/google/certificate-transparency-go/x509"
 	"github.com/sigstore/fulcio/pkg/ca"
 	"github.com/sigstore/fulcio/pkg/identity"
+	"github.com/sigstore/fulcio/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
@@ -57,7 +60,7 @@ func (bca *BaseCA) CreatePrecertificate(ctx context.Context, principal identity.
 		Value:    asn1.NullBytes,
 	})
 
-	finalCertBytes, err := x509.CreateCertificate(rand.Reader, cert, certChain[0], publicKey, privateKey)
+	finalCertBytes, err := createCertificate(ctx, "precertificate", cert, certChain[0], publicKey, privateKey)
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +101,11 @@ func generateSCTListExt(scts []ct.SignedCertificateTimestamp) (pkix.Extension, e
 	}, nil
 }
 
-func (bca *BaseCA) IssueFinalCertificate(_ context.Context, precert *ca.CodeSigningPreCertificate, sct *ct.SignedCertificateTimestamp) (*ca.CodeSigningCertificate, error) {
+func (bca *BaseCA) IssueFinalCertificate(ctx context.Context, precert *ca.CodeSigningPreCertificate, scts ...*ct.SignedCertificateTimestamp) (*ca.CodeSigningCertificate, error) {
+	if len(scts) == 0 {
+		return nil, errors.New("at least one SCT is required to issue a final certificate")
+	}
+
 	// remove poison extension from precertificate.
 	var exts []pkix.Extension
 	for _, ext := range precert.PreCert.Extensions {
@@ -106,8 +113,12 @@ func (bca *BaseCA) IssueFinalCertificate(_ context.Context, precert *ca.CodeSign
 			exts = append(exts, ext)
 		}
 	}
-	// append SCT extension. Supports multiple SCTs, but Fulcio only writes to one log currently.
-	sctExt, err := generateSCTListExt([]ct.SignedCertificateTimestamp{*sct})
+	// append SCT extension with the SCTs of every log
+	list := make([]ct.SignedCertificateTimestamp, 0, len(scts))
+	for _, sct := range scts {
+		list = append(list, *sct)
+	}
+	sctExt, err := generateSCTListExt(list)
 	if err != nil {
 		return nil, err
 	}
@@ -115,7 +126,7 @@ func (bca *BaseCA) IssueFinalCertificate(_ context.Context, precert *ca.CodeSign
 
 	cert := precert.PreCert
 	cert.ExtraExtensions = exts
-	finalCertBytes, err := x509.CreateCertificate(rand.Reader, cert, precert.CertChain[0], precert.PreCert.PublicKey, precert.PrivateKey)
+	finalCertBytes, err := createCertificate(ctx, "final", cert, precert.CertChain[0], precert.PreCert.PublicKey, precert.PrivateKey)
 	if err != nil {
 		return nil, err
 	}
@@ -131,7 +142,7 @@ func (bca *BaseCA) CreateCertificate(ctx context.Context, principal identity.Pri
 
 	certChain, privateKey := bca.GetSignerWithChain()
 
-	finalCertBytes, err := x509.CreateCertificate(rand.Reader, cert, certChain[0], publicKey, privateKey)
+	finalCertBytes, err := createCertificate(ctx, "certificate", cert, certChain[0], publicKey, privateKey)
 	if err != nil {
 		return nil, err
 	}
@@ -139,6 +150,15 @@ func (bca *BaseCA) CreateCertificate(ctx context.Context, principal identity.Pri
 	return ca.CreateCSCFromDER(finalCertBytes, certChain)
 }
 
+// createCertificate signs cert with the CA key, which may be held in a KMS
+// or HSM, tracing the signature as a child of the span in ctx.
+func createCertificate(ctx context.Context, kind string, cert, parent *x509.Certificate, pub crypto.PublicKey, priv crypto.Signer) ([]byte, error) {
+	_, span := tracing.Start(ctx, "ca.sign", attribute.String("ca.certificate", kind))
+	der, err := x509.CreateCertificate(rand.Reader, cert, parent, pub, priv)
+	tracing.End(span, err)
+	return der, err
+}
+
 func (bca *BaseCA) TrustBundle(_ context.Context) ([][]*x509.Certificate, error) {
 	certs, _ := bca.GetSignerWithChain()
 	return [][]*x509.Certificate{certs}, nil
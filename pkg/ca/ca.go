@@ -30,3 +30,9 @@ type CertificateAuthority interface {
 	TrustBundle(ctx context.Context) ([][]*x509.Certificate, error)
 	Close() error
 }
+
+// Reloader is implemented by CertificateAuthorities that can reload their
+// keys and certificates on demand, such as when the process receives SIGHUP.
+type Reloader interface {
+	Reload() error
+}
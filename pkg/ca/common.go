@@ -49,10 +49,12 @@ func MakeX509(ctx context.Context, principal identity.Principal, publicKey crypt
 	}
 	// ========================================
 
+	validity := ValidityFromContext(ctx)
+	now := time.Now()
 	cert := &x509.Certificate{
 		SerialNumber: serialNumber,
-		NotBefore:    time.Now(),
-		NotAfter:     time.Now().Add(time.Minute * 10),
+		NotBefore:    now.Add(-validity.NotBeforeSkew),
+		NotAfter:     now.Add(validity.Lifetime),
 		SubjectKeyId: skid,
 		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
 		KeyUsage:     x509.KeyUsageDigitalSignature,
@@ -60,7 +62,7 @@ func MakeX509(ctx context.Context, principal identity.Principal, publicKey crypt
 
 	err = principal.Embed(ctx, cert)
 	if err != nil {
-		return nil, ValidationError(err)
+		return nil, ValidationError{Err: err}
 	}
 
 	return cert, nil
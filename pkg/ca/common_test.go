@@ -70,6 +70,25 @@ func TestMakeX509(t *testing.T) {
 	}
 }
 
+func TestMakeX509WithValidity(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error generating key: %v", err)
+	}
+	ctx := WithValidity(context.TODO(), Validity{Lifetime: 30 * time.Minute, NotBeforeSkew: time.Minute})
+	before := time.Now()
+	cert, err := MakeX509(ctx, &testPrincipal{}, key.Public())
+	if err != nil {
+		t.Fatalf("unexpected error calling MakeX509: %v", err)
+	}
+	if got := cert.NotAfter.Sub(cert.NotBefore); got != 31*time.Minute {
+		t.Fatalf("expected 31 minute validity window, got %v", got)
+	}
+	if !cert.NotBefore.Before(before.Add(-59 * time.Second)) {
+		t.Fatalf("expected NotBefore to be backdated, got %v", cert.NotBefore)
+	}
+}
+
 func TestVerifyCertChain(t *testing.T) {
 	rootCert, rootKey, _ := test.GenerateRootCA()
 	subCert, subKey, _ := test.GenerateSubordinateCA(rootCert, rootKey)
@@ -0,0 +1,38 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ca
+
+import (
+	"context"
+	"crypto/x509"
+)
+
+type certificateRequestKey struct{}
+
+// WithCertificateRequest returns a context carrying the verified CSR the
+// client proved possession of its private key with. Backends that issue
+// through an external CA, which requires its own proof of possession, can
+// forward it.
+func WithCertificateRequest(ctx context.Context, csr *x509.CertificateRequest) context.Context {
+	return context.WithValue(ctx, certificateRequestKey{}, csr)
+}
+
+// CertificateRequestFromContext returns the CSR set with
+// WithCertificateRequest, or nil if the client did not send one.
+func CertificateRequestFromContext(ctx context.Context) *x509.CertificateRequest {
+	csr, _ := ctx.Value(certificateRequestKey{}).(*x509.CertificateRequest)
+	return csr
+}
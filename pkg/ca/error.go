@@ -17,4 +17,14 @@ package ca
 
 // ValidationError indicates that there is an issue with the content in the HTTP Request that
 // should result in an HTTP 400 Bad Request error being returned to the client
-type ValidationError error
+type ValidationError struct {
+	Err error
+}
+
+func (e ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e ValidationError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,348 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package failoverca implements a CertificateAuthority that issues
+// certificates with the first of an ordered list of backends that succeeds,
+// so that an outage of one backend does not stop issuance.
+package failoverca
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sigstore/fulcio/pkg/ca"
+	"github.com/sigstore/fulcio/pkg/identity"
+	"github.com/sigstore/fulcio/pkg/log"
+)
+
+const (
+	// DefaultFailureThreshold is the number of consecutive failures after
+	// which a backend is skipped.
+	DefaultFailureThreshold = 3
+	// DefaultCooldown is how long a failing backend is skipped before it is
+	// tried again.
+	DefaultCooldown = 30 * time.Second
+)
+
+var (
+	metricBackendHealthy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "fulcio_ca_backend_healthy",
+		Help: "Whether a failover CA backend is in use (1) or skipped after repeated failures (0)",
+	}, []string{"backend"})
+
+	metricFailovers = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulcio_ca_failovers_total",
+		Help: "The total number of requests that failed over from one CA backend to the next",
+	}, []string{"from", "to"})
+)
+
+// Backend is a named CertificateAuthority. The name is used in logs and
+// metrics.
+type Backend struct {
+	Name string
+	CA   ca.CertificateAuthority
+}
+
+// backend tracks the circuit breaker state of a Backend. The circuit opens
+// after threshold consecutive failures, and the backend is then skipped until
+// openUntil. After that a single request at a time is let through as a
+// probe, which closes the circuit again if it succeeds, and reopens it if it
+// fails.
+type backend struct {
+	Backend
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	// probing is set while a request probes the half-open circuit
+	probing bool
+}
+
+type failoverCA struct {
+	backends  []*backend
+	threshold int
+	cooldown  time.Duration
+	// embedded is set if all backends support EmbeddedSCTCA
+	embedded bool
+	now      func() time.Time
+}
+
+var (
+	_ ca.Router   = (*failoverCA)(nil)
+	_ ca.Reloader = (*failoverCA)(nil)
+)
+
+// Option configures the CA returned by NewFailoverCA.
+type Option func(*failoverCA)
+
+// WithFailureThreshold sets the number of consecutive failures after which a
+// backend is skipped. Defaults to DefaultFailureThreshold.
+func WithFailureThreshold(n int) Option {
+	return func(f *failoverCA) {
+		f.threshold = n
+	}
+}
+
+// WithCooldown sets how long a failing backend is skipped before it is tried
+// again. Defaults to DefaultCooldown.
+func WithCooldown(d time.Duration) Option {
+	return func(f *failoverCA) {
+		f.cooldown = d
+	}
+}
+
+// NewFailoverCA returns a CertificateAuthority that issues each certificate
+// with the first backend that succeeds, in order. Backends that fail
+// repeatedly are skipped for a cooldown period. Requests that fail with a
+// ca.ValidationError are never retried with another backend. Its trust
+// bundle is the union of the trust bundles of all backends.
+func NewFailoverCA(backends []Backend, opts ...Option) (ca.CertificateAuthority, error) {
+	if len(backends) == 0 {
+		return nil, errors.New("failover CA requires at least one backend")
+	}
+	f := &failoverCA{
+		threshold: DefaultFailureThreshold,
+		cooldown:  DefaultCooldown,
+		embedded:  true,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if f.threshold < 1 {
+		return nil, fmt.Errorf("failure threshold must be at least 1, got %d", f.threshold)
+	}
+	if f.cooldown <= 0 {
+		return nil, fmt.Errorf("cooldown must be positive, got %v", f.cooldown)
+	}
+
+	names := make(map[string]bool, len(backends))
+	for _, b := range backends {
+		if b.Name == "" {
+			return nil, errors.New("CA backend names must not be empty")
+		}
+		if names[b.Name] {
+			return nil, fmt.Errorf("duplicate CA backend %q", b.Name)
+		}
+		names[b.Name] = true
+		if b.CA == nil {
+			return nil, fmt.Errorf("CA backend %q is nil", b.Name)
+		}
+		if _, ok := b.CA.(ca.EmbeddedSCTCA); !ok {
+			f.embedded = false
+		}
+		f.backends = append(f.backends, &backend{Backend: b})
+		metricBackendHealthy.WithLabelValues(b.Name).Set(1)
+	}
+	return f, nil
+}
+
+// Route returns a CertificateAuthority for a single request. When all
+// backends support EmbeddedSCTCA it does too, and issues the final
+// certificate with the backend that created the precertificate.
+func (f *failoverCA) Route(context.Context) (ca.CertificateAuthority, error) {
+	if f.embedded {
+		return &embeddedRequest{request: request{f: f}}, nil
+	}
+	return &request{f: f}, nil
+}
+
+func (f *failoverCA) CreateCertificate(ctx context.Context, principal identity.Principal, publicKey crypto.PublicKey) (*ca.CodeSigningCertificate, error) {
+	var csc *ca.CodeSigningCertificate
+	_, err := f.do(ctx, func(b *backend) (err error) {
+		csc, err = b.CA.CreateCertificate(ctx, principal, publicKey)
+		return
+	})
+	return csc, err
+}
+
+func (f *failoverCA) TrustBundle(ctx context.Context) ([][]*x509.Certificate, error) {
+	var bundle [][]*x509.Certificate
+	for _, b := range f.backends {
+		chains, err := b.CA.TrustBundle(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("CA backend %q: %w", b.Name, err)
+		}
+		bundle = ca.AppendChains(bundle, chains...)
+	}
+	return bundle, nil
+}
+
+func (f *failoverCA) Close() error {
+	var errs []error
+	for _, b := range f.backends {
+		errs = append(errs, b.CA.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Reload reloads the backends that support it.
+func (f *failoverCA) Reload() error {
+	var errs []error
+	for _, b := range f.backends {
+		if rl, ok := b.CA.(ca.Reloader); ok {
+			if err := rl.Reload(); err != nil {
+				errs = append(errs, fmt.Errorf("CA backend %q: %w", b.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// candidates returns the backends whose circuit is closed, or half-open and
+// not already being probed, in order, along with the half-open backends that
+// the caller now probes and must release. If no backend is a candidate, all
+// backends are returned, since trying them is better than failing the
+// request outright.
+func (f *failoverCA) candidates() (candidates, probes []*backend) {
+	now := f.now()
+	for _, b := range f.backends {
+		b.mu.Lock()
+		halfOpen := b.failures >= f.threshold
+		ok := !now.Before(b.openUntil) && !(halfOpen && b.probing)
+		if ok && halfOpen {
+			b.probing = true
+			probes = append(probes, b)
+		}
+		b.mu.Unlock()
+		if ok {
+			candidates = append(candidates, b)
+		}
+	}
+	if len(candidates) == 0 {
+		return f.backends, nil
+	}
+	return candidates, probes
+}
+
+// do calls op with each candidate backend in turn until one succeeds, and
+// returns the backend that succeeded.
+func (f *failoverCA) do(ctx context.Context, op func(*backend) error) (*backend, error) {
+	candidates, probes := f.candidates()
+	// Probes that were not tried, or whose outcome says nothing about the
+	// backend, leave the circuit half-open for the next request
+	defer func() {
+		for _, b := range probes {
+			b.mu.Lock()
+			b.probing = false
+			b.mu.Unlock()
+		}
+	}()
+	var errs []error
+	for i, b := range candidates {
+		err := op(b)
+		if err == nil {
+			f.succeeded(b)
+			return b, nil
+		}
+		// The request itself is invalid, and would fail with any backend
+		if errors.As(err, new(ca.ValidationError)) {
+			return nil, err
+		}
+		// The caller gave up, which says nothing about the backend
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		f.failed(b)
+		errs = append(errs, fmt.Errorf("CA backend %q: %w", b.Name, err))
+		if i+1 < len(candidates) {
+			next := candidates[i+1].Name
+			metricFailovers.WithLabelValues(b.Name, next).Inc()
+			log.ContextLogger(ctx).Warnf("CA backend %q failed, failing over to %q: %v", b.Name, next, err)
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (f *failoverCA) succeeded(b *backend) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.openUntil = time.Time{}
+	metricBackendHealthy.WithLabelValues(b.Name).Set(1)
+}
+
+func (f *failoverCA) failed(b *backend) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures >= f.threshold {
+		b.openUntil = f.now().Add(f.cooldown)
+		metricBackendHealthy.WithLabelValues(b.Name).Set(0)
+	}
+}
+
+// request handles a single request routed through the failover CA.
+type request struct {
+	f *failoverCA
+}
+
+func (r *request) CreateCertificate(ctx context.Context, principal identity.Principal, publicKey crypto.PublicKey) (*ca.CodeSigningCertificate, error) {
+	return r.f.CreateCertificate(ctx, principal, publicKey)
+}
+
+func (r *request) TrustBundle(ctx context.Context) ([][]*x509.Certificate, error) {
+	return r.f.TrustBundle(ctx)
+}
+
+// Close is a no-op, since the backends are owned by the failover CA.
+func (r *request) Close() error {
+	return nil
+}
+
+// embeddedRequest pins the backend that created a precertificate, since the
+// final certificate must be issued by the same CA.
+type embeddedRequest struct {
+	request
+	precertBackend *backend
+}
+
+var _ ca.EmbeddedSCTCA = (*embeddedRequest)(nil)
+
+func (r *embeddedRequest) CreatePrecertificate(ctx context.Context, principal identity.Principal, publicKey crypto.PublicKey) (*ca.CodeSigningPreCertificate, error) {
+	var precert *ca.CodeSigningPreCertificate
+	b, err := r.f.do(ctx, func(b *backend) (err error) {
+		precert, err = b.CA.(ca.EmbeddedSCTCA).CreatePrecertificate(ctx, principal, publicKey)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.precertBackend = b
+	return precert, nil
+}
+
+func (r *embeddedRequest) IssueFinalCertificate(ctx context.Context, precert *ca.CodeSigningPreCertificate, scts ...*ct.SignedCertificateTimestamp) (*ca.CodeSigningCertificate, error) {
+	b := r.precertBackend
+	if b == nil {
+		return nil, errors.New("no precertificate was created with this request")
+	}
+	csc, err := b.CA.(ca.EmbeddedSCTCA).IssueFinalCertificate(ctx, precert, scts...)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.f.failed(b)
+		}
+		return nil, fmt.Errorf("CA backend %q: %w", b.Name, err)
+	}
+	return csc, nil
+}
@@ -0,0 +1,302 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package failoverca
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sigstore/fulcio/pkg/ca"
+	"github.com/sigstore/fulcio/pkg/ca/ephemeralca"
+	"github.com/sigstore/fulcio/pkg/identity"
+)
+
+type testPrincipal struct{}
+
+func (tp testPrincipal) Name(context.Context) string {
+	return "doesntmatter"
+}
+
+func (tp testPrincipal) Embed(_ context.Context, cert *x509.Certificate) error {
+	cert.EmailAddresses = []string{"alice@example.com"}
+	return nil
+}
+
+// fakeCA is an ephemeral CA that fails with err when it is set.
+type fakeCA struct {
+	*ephemeralca.EphemeralCA
+	err   error
+	calls int
+}
+
+func newFakeCA(t *testing.T) *fakeCA {
+	t.Helper()
+	eca, err := ephemeralca.NewEphemeralCA()
+	if err != nil {
+		t.Fatalf("NewEphemeralCA() = %v", err)
+	}
+	return &fakeCA{EphemeralCA: eca}
+}
+
+func (f *fakeCA) CreateCertificate(ctx context.Context, principal identity.Principal, publicKey crypto.PublicKey) (*ca.CodeSigningCertificate, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.EphemeralCA.CreateCertificate(ctx, principal, publicKey)
+}
+
+func (f *fakeCA) CreatePrecertificate(ctx context.Context, principal identity.Principal, publicKey crypto.PublicKey) (*ca.CodeSigningPreCertificate, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.EphemeralCA.CreatePrecertificate(ctx, principal, publicKey)
+}
+
+func (f *fakeCA) root() *x509.Certificate {
+	certs, _ := f.GetSignerWithChain()
+	return certs[len(certs)-1]
+}
+
+// detachedCA only supports certificates with a detached SCT.
+type detachedCA struct {
+	ca.CertificateAuthority
+}
+
+func newKey(t *testing.T) crypto.PublicKey {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv.Public()
+}
+
+func TestNewFailoverCA(t *testing.T) {
+	a := newFakeCA(t)
+	for name, test := range map[string]struct {
+		backends []Backend
+		opts     []Option
+	}{
+		"no backends":       {},
+		"empty name":        {backends: []Backend{{CA: a}}},
+		"nil backend":       {backends: []Backend{{Name: "a"}}},
+		"duplicate name":    {backends: []Backend{{Name: "a", CA: a}, {Name: "a", CA: a}}},
+		"zero threshold":    {backends: []Backend{{Name: "a", CA: a}}, opts: []Option{WithFailureThreshold(0)}},
+		"negative cooldown": {backends: []Backend{{Name: "a", CA: a}}, opts: []Option{WithCooldown(-time.Second)}},
+	} {
+		if _, err := NewFailoverCA(test.backends, test.opts...); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestFailover(t *testing.T) {
+	primary, secondary := newFakeCA(t), newFakeCA(t)
+	fca, err := NewFailoverCA([]Backend{{Name: "primary", CA: primary}, {Name: "secondary", CA: secondary}})
+	if err != nil {
+		t.Fatalf("NewFailoverCA() = %v", err)
+	}
+	ctx := context.Background()
+
+	csc, err := fca.CreateCertificate(ctx, testPrincipal{}, newKey(t))
+	if err != nil {
+		t.Fatalf("CreateCertificate() = %v", err)
+	}
+	if err := csc.FinalCertificate.CheckSignatureFrom(primary.root()); err != nil {
+		t.Errorf("expected certificate from primary: %v", err)
+	}
+
+	failovers := testutil.ToFloat64(metricFailovers.WithLabelValues("primary", "secondary"))
+	primary.err = errors.New("unavailable")
+	csc, err = fca.CreateCertificate(ctx, testPrincipal{}, newKey(t))
+	if err != nil {
+		t.Fatalf("CreateCertificate() = %v", err)
+	}
+	if err := csc.FinalCertificate.CheckSignatureFrom(secondary.root()); err != nil {
+		t.Errorf("expected certificate from secondary: %v", err)
+	}
+	if got := testutil.ToFloat64(metricFailovers.WithLabelValues("primary", "secondary")); got != failovers+1 {
+		t.Errorf("expected failover to be counted, counter went from %v to %v", failovers, got)
+	}
+
+	secondary.err = errors.New("also unavailable")
+	if _, err := fca.CreateCertificate(ctx, testPrincipal{}, newKey(t)); err == nil {
+		t.Error("expected error when all backends fail")
+	}
+}
+
+func TestNoFailoverOnValidationError(t *testing.T) {
+	primary, secondary := newFakeCA(t), newFakeCA(t)
+	fca, err := NewFailoverCA([]Backend{{Name: "primary", CA: primary}, {Name: "secondary", CA: secondary}}, WithFailureThreshold(1))
+	if err != nil {
+		t.Fatalf("NewFailoverCA() = %v", err)
+	}
+
+	primary.err = ca.ValidationError{Err: errors.New("bad request")}
+	_, err = fca.CreateCertificate(context.Background(), testPrincipal{}, newKey(t))
+	if !errors.As(err, new(ca.ValidationError)) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if secondary.calls != 0 {
+		t.Error("expected validation error not to fail over")
+	}
+	if got := testutil.ToFloat64(metricBackendHealthy.WithLabelValues("primary")); got != 1 {
+		t.Error("expected validation error not to count against the backend")
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	primary, secondary := newFakeCA(t), newFakeCA(t)
+	rca, err := NewFailoverCA([]Backend{{Name: "primary", CA: primary}, {Name: "secondary", CA: secondary}},
+		WithFailureThreshold(2), WithCooldown(time.Minute))
+	if err != nil {
+		t.Fatalf("NewFailoverCA() = %v", err)
+	}
+	fca := rca.(*failoverCA)
+	now := time.Now()
+	fca.now = func() time.Time { return now }
+	ctx := context.Background()
+	create := func() {
+		t.Helper()
+		if _, err := fca.CreateCertificate(ctx, testPrincipal{}, newKey(t)); err != nil {
+			t.Fatalf("CreateCertificate() = %v", err)
+		}
+	}
+
+	primary.err = errors.New("unavailable")
+	create()
+	create()
+	if primary.calls != 2 {
+		t.Fatalf("expected primary to be tried until the threshold, got %d calls", primary.calls)
+	}
+	if got := testutil.ToFloat64(metricBackendHealthy.WithLabelValues("primary")); got != 0 {
+		t.Error("expected primary to be reported unhealthy")
+	}
+
+	// The open circuit skips the primary
+	create()
+	if primary.calls != 2 {
+		t.Errorf("expected primary to be skipped during cooldown, got %d calls", primary.calls)
+	}
+
+	// After the cooldown a single request at a time probes the primary
+	now = now.Add(time.Minute)
+	candidates, probes := fca.candidates()
+	if len(candidates) != 2 || len(probes) != 1 || probes[0].Name != "primary" {
+		t.Errorf("expected the primary to be probed, got %d candidates and %d probes", len(candidates), len(probes))
+	}
+	if candidates, _ := fca.candidates(); len(candidates) != 1 || candidates[0].Name != "secondary" {
+		t.Errorf("expected the primary to be skipped while it is probed, got %d candidates", len(candidates))
+	}
+	probes[0].probing = false
+
+	// A successful probe closes the circuit
+	primary.err = nil
+	create()
+	if primary.calls != 3 {
+		t.Errorf("expected primary to be retried after cooldown, got %d calls", primary.calls)
+	}
+	if got := testutil.ToFloat64(metricBackendHealthy.WithLabelValues("primary")); got != 1 {
+		t.Error("expected primary to be reported healthy")
+	}
+
+	// If every circuit is open, backends are tried anyway
+	primary.err = errors.New("unavailable")
+	secondary.err = errors.New("unavailable")
+	for i := 0; i < 2; i++ {
+		if _, err := fca.CreateCertificate(ctx, testPrincipal{}, newKey(t)); err == nil {
+			t.Fatal("expected error when all backends fail")
+		}
+	}
+	primary.err = nil
+	create()
+}
+
+func TestEmbeddedSCT(t *testing.T) {
+	primary, secondary := newFakeCA(t), newFakeCA(t)
+	fca, err := NewFailoverCA([]Backend{{Name: "primary", CA: primary}, {Name: "secondary", CA: secondary}})
+	if err != nil {
+		t.Fatalf("NewFailoverCA() = %v", err)
+	}
+	ctx := context.Background()
+
+	resolved, err := ca.Resolve(ctx, fca)
+	if err != nil {
+		t.Fatalf("Resolve() = %v", err)
+	}
+	sctCA, ok := resolved.(ca.EmbeddedSCTCA)
+	if !ok {
+		t.Fatal("expected failover CA to support embedded SCTs when all backends do")
+	}
+
+	primary.err = errors.New("unavailable")
+	precert, err := sctCA.CreatePrecertificate(ctx, testPrincipal{}, newKey(t))
+	if err != nil {
+		t.Fatalf("CreatePrecertificate() = %v", err)
+	}
+	// The final certificate comes from the same backend, even once the
+	// primary has recovered
+	primary.err = nil
+	csc, err := sctCA.IssueFinalCertificate(ctx, precert, &ct.SignedCertificateTimestamp{SCTVersion: 1})
+	if err != nil {
+		t.Fatalf("IssueFinalCertificate() = %v", err)
+	}
+	if err := csc.FinalCertificate.CheckSignatureFrom(secondary.root()); err != nil {
+		t.Errorf("expected final certificate from secondary: %v", err)
+	}
+
+	// A backend without embedded SCT support disables the precertificate flow
+	fca, err = NewFailoverCA([]Backend{{Name: "primary", CA: primary}, {Name: "detached", CA: detachedCA{secondary}}})
+	if err != nil {
+		t.Fatalf("NewFailoverCA() = %v", err)
+	}
+	resolved, err = ca.Resolve(ctx, fca)
+	if err != nil {
+		t.Fatalf("Resolve() = %v", err)
+	}
+	if _, ok := resolved.(ca.EmbeddedSCTCA); ok {
+		t.Error("expected failover CA not to support embedded SCTs when a backend does not")
+	}
+}
+
+func TestTrustBundle(t *testing.T) {
+	primary, secondary := newFakeCA(t), newFakeCA(t)
+	fca, err := NewFailoverCA([]Backend{{Name: "primary", CA: primary}, {Name: "secondary", CA: secondary}})
+	if err != nil {
+		t.Fatalf("NewFailoverCA() = %v", err)
+	}
+	chains, err := fca.TrustBundle(context.Background())
+	if err != nil {
+		t.Fatalf("TrustBundle() = %v", err)
+	}
+	if len(chains) != 2 || !chains[0][0].Equal(primary.root()) || !chains[1][0].Equal(secondary.root()) {
+		t.Errorf("expected trust bundle with the chains of both backends, got %d chains", len(chains))
+	}
+	if err := fca.Close(); err != nil {
+		t.Errorf("Close() = %v", err)
+	}
+}
@@ -16,18 +16,35 @@
 package fileca
 
 import (
+	"context"
 	"crypto"
 	"crypto/x509"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/sigstore/fulcio/pkg/ca"
 	"github.com/sigstore/fulcio/pkg/ca/baseca"
+	"github.com/sigstore/fulcio/pkg/identity"
+	"github.com/sigstore/fulcio/pkg/log"
 )
 
 type fileCA struct {
 	baseca.BaseCA
+
+	certPath, keyPath, keyPass string
+	// dir and rotation are set if key pairs are loaded from a rotation
+	// directory
+	dir      string
+	rotation *keyRotation
+	watcher  *fsnotify.Watcher
+	now      func() time.Time
 }
 
+var _ ca.Reloader = (*fileCA)(nil)
+
 // RHTAS FIPS - DO NOT REMOVE
 // ========================================
 // NewFileCA returns a file backed certificate authority. Expects paths to a
@@ -36,34 +53,44 @@ type fileCA struct {
 // unencrypted PEM.
 // ========================================
 func NewFileCA(certPath, keyPath, keyPass string, watch bool) (ca.CertificateAuthority, error) {
-	var fca fileCA
+	fca := fileCA{certPath: certPath, keyPath: keyPath, keyPass: keyPass, now: time.Now}
 
 	var err error
 	fca.SignerWithChain, err = loadKeyPair(certPath, keyPath, keyPass)
-	if err != nil {
+	if recordReload(certPath, err) != nil {
 		return nil, err
 	}
 
 	if watch {
-		watcher, err := fsnotify.NewWatcher()
-		if err != nil {
-			return nil, err
-		}
-		err = watcher.Add(certPath)
-		if err != nil {
-			return nil, err
-		}
-		err = watcher.Add(keyPath)
+		// Watch the directories rather than the files, since files that
+		// are replaced by a rename, such as those of a Kubernetes Secret
+		// volume, would lose their watch
+		fca.watcher, err = watchDirs(filepath.Dir(certPath), filepath.Dir(keyPath))
 		if err != nil {
 			return nil, err
 		}
 
-		go ioWatch(certPath, keyPath, keyPass, watcher, fca.updateX509KeyPair)
+		go ioWatch(certPath, keyPath, keyPass, fca.watcher, fca.updateX509KeyPair)
 	}
 
 	return &fca, err
 }
 
+// watchDirs returns a watcher for the given directories.
+func watchDirs(dirs ...string) (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+	}
+	return watcher, nil
+}
+
 func (fca *fileCA) updateX509KeyPair(certs []*x509.Certificate, signer crypto.Signer) {
 	scm := fca.SignerWithChain.(*ca.SignerCertsMutex)
 	scm.Lock()
@@ -75,3 +102,102 @@ func (fca *fileCA) updateX509KeyPair(certs []*x509.Certificate, signer crypto.Si
 	scm.Certs = certs
 	scm.Signer = signer
 }
+
+// NewFileCAFromDir returns a file backed certificate authority that rotates
+// between the key pairs in dir. Each key pair is a PEM encoded certificate
+// chain named <name>-cert.pem and key named <name>-key.pem, and is activated
+// at the time in an optional <name>-activate-at file, in RFC 3339 format, or
+// else at the certificate's NotBefore. Certificates are signed with the most
+// recently activated key pair, and the trust bundle holds the chains of all
+// key pairs whose certificate has not expired. If keyPass is non-empty the
+// keys are decrypted with the given password.
+func NewFileCAFromDir(dir, keyPass string, watch bool) (ca.CertificateAuthority, error) {
+	fca := fileCA{dir: dir, keyPass: keyPass, now: time.Now}
+	fca.rotation = &keyRotation{now: fca.now}
+	if err := recordReload(dir, fca.rotation.load(dir, keyPass)); err != nil {
+		return nil, err
+	}
+	fca.SignerWithChain = fca.rotation
+
+	if watch {
+		var err error
+		fca.watcher, err = watchDirs(dir)
+		if err != nil {
+			return nil, err
+		}
+		go watchEvents(fca.watcher, func(string) bool { return true }, func() {
+			if err := fca.Reload(); err != nil {
+				// Files may be mid-update, and the next update is
+				// picked up
+				log.Logger.Warnf("error reloading key pairs from %s: %v", dir, err)
+			}
+		})
+	}
+
+	return &fca, nil
+}
+
+// Reload reloads the keys and certificates from disk. If they fail to load,
+// the current ones continue to be used.
+func (fca *fileCA) Reload() error {
+	if fca.rotation != nil {
+		return recordReload(fca.dir, fca.rotation.load(fca.dir, fca.keyPass))
+	}
+	scm, err := loadKeyPair(fca.certPath, fca.keyPath, fca.keyPass)
+	if recordReload(fca.certPath, err) != nil {
+		return err
+	}
+	fca.updateX509KeyPair(scm.Certs, scm.Signer)
+	return nil
+}
+
+func (fca *fileCA) Close() error {
+	if fca.watcher != nil {
+		return fca.watcher.Close()
+	}
+	return nil
+}
+
+// signerWithChain returns the chain and signer to issue a certificate with
+// the validity in ctx. Certificates must not outlive the certificate that
+// signs them.
+func (fca *fileCA) signerWithChain(ctx context.Context) (*baseca.BaseCA, error) {
+	certs, signer := fca.GetSignerWithChain()
+	if len(certs) == 0 {
+		return nil, errors.New("fileca: no active key pair")
+	}
+	notAfter := fca.now().Add(ca.ValidityFromContext(ctx).Lifetime)
+	if notAfter.After(certs[0].NotAfter) {
+		return nil, fmt.Errorf("fileca: certificate would expire at %v, after the signing certificate %q expires at %v", notAfter, certs[0].Subject, certs[0].NotAfter)
+	}
+	// Pin the chain and signer, so that a concurrent rotation can't change
+	// them halfway through issuance
+	return &baseca.BaseCA{SignerWithChain: &ca.SignerCerts{Certs: certs, Signer: signer}}, nil
+}
+
+func (fca *fileCA) CreateCertificate(ctx context.Context, principal identity.Principal, publicKey crypto.PublicKey) (*ca.CodeSigningCertificate, error) {
+	bca, err := fca.signerWithChain(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return bca.CreateCertificate(ctx, principal, publicKey)
+}
+
+func (fca *fileCA) CreatePrecertificate(ctx context.Context, principal identity.Principal, publicKey crypto.PublicKey) (*ca.CodeSigningPreCertificate, error) {
+	bca, err := fca.signerWithChain(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return bca.CreatePrecertificate(ctx, principal, publicKey)
+}
+
+func (fca *fileCA) TrustBundle(ctx context.Context) ([][]*x509.Certificate, error) {
+	if fca.rotation == nil {
+		return fca.BaseCA.TrustBundle(ctx)
+	}
+	chains := fca.rotation.trustBundle()
+	if len(chains) == 0 {
+		return nil, errors.New("fileca: every key pair has expired")
+	}
+	return chains, nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package fileca
+
+import (
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	certSuffix       = "-cert.pem"
+	keySuffix        = "-key.pem"
+	activationSuffix = "-activate-at"
+)
+
+// keyPair is a certificate chain and signing key from a rotation directory,
+// and the time from which it is used to sign certificates.
+type keyPair struct {
+	name       string
+	activation time.Time
+	certs      []*x509.Certificate
+	signer     crypto.Signer
+}
+
+// keyRotation holds the key pairs loaded from a rotation directory. The pair
+// with the latest activation time that has passed signs certificates, and
+// every pair whose certificate has not expired is in the trust bundle, so
+// verifiers can fetch a chain before it is used and keep verifying
+// certificates issued by a chain after it is replaced.
+type keyRotation struct {
+	mu    sync.RWMutex
+	pairs []keyPair // sorted by activation time
+	now   func() time.Time
+}
+
+// GetSignerWithChain returns the active key pair, or nil if no pair is
+// active.
+func (r *keyRotation) GetSignerWithChain() ([]*x509.Certificate, crypto.Signer) {
+	now := r.now()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var active *keyPair
+	for i := range r.pairs {
+		if r.pairs[i].activation.After(now) {
+			break
+		}
+		active = &r.pairs[i]
+	}
+	if active == nil {
+		return nil, nil
+	}
+	return active.certs, active.signer
+}
+
+func (r *keyRotation) trustBundle() [][]*x509.Certificate {
+	now := r.now()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var chains [][]*x509.Certificate
+	for _, p := range r.pairs {
+		if now.Before(p.certs[0].NotAfter) {
+			chains = append(chains, p.certs)
+		}
+	}
+	return chains
+}
+
+// load replaces the key pairs with those in dir. On error the current key
+// pairs are kept.
+func (r *keyRotation) load(dir, keyPass string) error {
+	pairs, err := loadKeyPairDir(dir, keyPass)
+	if err != nil {
+		return err
+	}
+	now := r.now()
+	if pairs[0].activation.After(now) {
+		return fmt.Errorf("fileca: no key pair in %s is active yet, the first is activated at %v", dir, pairs[0].activation)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pairs = pairs
+	return nil
+}
+
+// loadKeyPairDir loads the key pairs in dir, sorted by activation time. Each
+// pair is a <name>-cert.pem certificate chain and a <name>-key.pem key, and an
+// optional <name>-activate-at file holding an RFC 3339 activation time. The
+// activation time defaults to the certificate's NotBefore.
+func loadKeyPairDir(dir, keyPass string) ([]keyPair, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs []keyPair
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), certSuffix)
+		// Skip the hidden files Kubernetes uses to swap volume contents
+		if !ok || strings.HasPrefix(name, ".") {
+			continue
+		}
+		certPath := filepath.Join(dir, entry.Name())
+		keyPath := filepath.Join(dir, name+keySuffix)
+		scm, err := loadKeyPair(certPath, keyPath, keyPass)
+		if err != nil {
+			return nil, fmt.Errorf("fileca: loading key pair %q: %w", name, err)
+		}
+
+		activation := scm.Certs[0].NotBefore
+		data, err := os.ReadFile(filepath.Join(dir, name+activationSuffix))
+		switch {
+		case err == nil:
+			activation, err = time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+			if err != nil {
+				return nil, fmt.Errorf("fileca: parsing activation time of key pair %q: %w", name, err)
+			}
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, err
+		}
+
+		pairs = append(pairs, keyPair{
+			name:       name,
+			activation: activation,
+			certs:      scm.Certs,
+			signer:     scm.Signer,
+		})
+	}
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("fileca: no key pairs found in %s", dir)
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].activation.Before(pairs[j].activation)
+	})
+	return pairs, nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package fileca
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sigstore/fulcio/pkg/ca"
+	"github.com/sigstore/fulcio/pkg/test"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+type testPrincipal struct{}
+
+func (tp testPrincipal) Name(context.Context) string {
+	return "doesntmatter"
+}
+
+func (tp testPrincipal) Embed(_ context.Context, cert *x509.Certificate) error {
+	cert.EmailAddresses = []string{"alice@example.com"}
+	return nil
+}
+
+// writeKeyPair writes a new intermediate, valid for 2 hours, and its root to
+// dir as the key pair name.
+func writeKeyPair(t *testing.T, dir, name string) *x509.Certificate {
+	t.Helper()
+	rootCert, rootKey, err := test.GenerateRootCA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	subCert, subKey, err := test.GenerateSubordinateCA(rootCert, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := cryptoutils.MarshalCertificatesToPEM([]*x509.Certificate{subCert, rootCert})
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := cryptoutils.MarshalPrivateKeyToPEM(subKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+certSuffix), chain, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+keySuffix), key, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return subCert
+}
+
+func writeActivation(t *testing.T, dir, name string, at time.Time) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name+activationSuffix), []byte(at.Format(time.RFC3339)+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newPublicKey(t *testing.T) crypto.PublicKey {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv.Public()
+}
+
+func TestNewFileCAFromDir(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewFileCAFromDir(dir, "", false); err == nil {
+		t.Error("expected error for empty directory")
+	}
+
+	writeKeyPair(t, dir, "next")
+	writeActivation(t, dir, "next", time.Now().Add(time.Hour))
+	if _, err := NewFileCAFromDir(dir, "", false); err == nil {
+		t.Error("expected error when no key pair is active")
+	}
+
+	writeKeyPair(t, dir, "current")
+	if _, err := NewFileCAFromDir(dir, "", false); err != nil {
+		t.Fatalf("NewFileCAFromDir() = %v", err)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "current"+keySuffix)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileCAFromDir(dir, "", false); err == nil {
+		t.Error("expected error for certificate without key")
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Now()
+	current := writeKeyPair(t, dir, "current")
+	next := writeKeyPair(t, dir, "next")
+	writeActivation(t, dir, "next", start.Add(time.Hour))
+
+	c, err := NewFileCAFromDir(dir, "", false)
+	if err != nil {
+		t.Fatalf("NewFileCAFromDir() = %v", err)
+	}
+	fca := c.(*fileCA)
+	now := start
+	fca.now = func() time.Time { return now }
+	fca.rotation.now = fca.now
+	ctx := context.Background()
+
+	// Before activation, the next chain is published but not used
+	chains, err := fca.TrustBundle(ctx)
+	if err != nil {
+		t.Fatalf("TrustBundle() = %v", err)
+	}
+	if len(chains) != 2 || !chains[0][0].Equal(current) || !chains[1][0].Equal(next) {
+		t.Fatalf("expected trust bundle with current and next chains, got %d chains", len(chains))
+	}
+	csc, err := fca.CreateCertificate(ctx, testPrincipal{}, newPublicKey(t))
+	if err != nil {
+		t.Fatalf("CreateCertificate() = %v", err)
+	}
+	if err := csc.FinalCertificate.CheckSignatureFrom(current); err != nil {
+		t.Errorf("expected certificate signed by current key pair: %v", err)
+	}
+
+	// After activation, the next key pair signs
+	now = start.Add(time.Hour)
+	precert, err := fca.CreatePrecertificate(ctx, testPrincipal{}, newPublicKey(t))
+	if err != nil {
+		t.Fatalf("CreatePrecertificate() = %v", err)
+	}
+	if err := precert.PreCert.CheckSignatureFrom(next); err != nil {
+		t.Errorf("expected precertificate signed by next key pair: %v", err)
+	}
+
+	// Certificates may not outlive the signing certificate
+	longLived := ca.WithValidity(ctx, ca.Validity{Lifetime: 2 * time.Hour})
+	if _, err := fca.CreateCertificate(longLived, testPrincipal{}, newPublicKey(t)); err == nil {
+		t.Error("expected error for certificate outliving the intermediate")
+	}
+
+	// Expired chains are dropped from the trust bundle
+	now = start.Add(3 * time.Hour)
+	if _, err := fca.TrustBundle(ctx); err == nil {
+		t.Error("expected error when every chain has expired")
+	}
+}
+
+func TestKeyRotationReload(t *testing.T) {
+	dir := t.TempDir()
+	first := writeKeyPair(t, dir, "first")
+	c, err := NewFileCAFromDir(dir, "", true)
+	if err != nil {
+		t.Fatalf("NewFileCAFromDir() = %v", err)
+	}
+
+	second := writeKeyPair(t, dir, "second")
+	writeActivation(t, dir, "second", first.NotBefore.Add(time.Second))
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		certs, _ := c.(*fileCA).GetSignerWithChain()
+		if certs[0].Equal(second) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("new key pair was not loaded")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	chains, err := c.TrustBundle(context.Background())
+	if err != nil {
+		t.Fatalf("TrustBundle() = %v", err)
+	}
+	if len(chains) != 2 {
+		t.Errorf("expected both chains in trust bundle, got %d", len(chains))
+	}
+}
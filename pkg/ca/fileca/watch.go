@@ -18,22 +18,116 @@ package fileca
 import (
 	"crypto"
 	"crypto/x509"
+	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sigstore/fulcio/pkg/log"
 )
 
+// debounceInterval is how long to wait after a change before reloading, so
+// that a certificate and key that are updated together are loaded together.
+var debounceInterval = 100 * time.Millisecond
+
+var (
+	metricLastReload = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "fulcio_fileca_last_reload_timestamp_seconds",
+		Help: "The time of the last successful load of the file CA's keys and certificates",
+	}, []string{"path"})
+
+	metricReloadFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulcio_fileca_reload_failures_total",
+		Help: "The total number of times the file CA's keys and certificates failed to reload",
+	}, []string{"path"})
+)
+
+// recordReload updates the reload metrics for the file CA loaded from path.
+func recordReload(path string, err error) error {
+	if err != nil {
+		metricReloadFailures.WithLabelValues(path).Inc()
+		return err
+	}
+	metricLastReload.WithLabelValues(path).SetToCurrentTime()
+	return nil
+}
+
+// isKubernetesSwap reports whether name is the symlink that Kubernetes swaps
+// to atomically update the files of a Secret or ConfigMap volume.
+func isKubernetesSwap(name string) bool {
+	return filepath.Base(name) == "..data"
+}
+
 func ioWatch(certPath, keyPath, keyPass string, watcher *fsnotify.Watcher, callback func([]*x509.Certificate, crypto.Signer)) {
-	for event := range watcher.Events {
-		if event.Op&fsnotify.Write == fsnotify.Write {
-			signerWithMutex, err := loadKeyPair(certPath, keyPath, keyPass)
-			if err != nil {
-				// Don't sweat it if this errors out. One file might
-				// have updated and the other isn't causing a key-pair
-				// mismatch
-				continue
+	certPath, keyPath = filepath.Clean(certPath), filepath.Clean(keyPath)
+	dirs := []string{filepath.Dir(certPath), filepath.Dir(keyPath)}
+	match := func(name string) bool {
+		name = filepath.Clean(name)
+		return name == certPath || name == keyPath || (isKubernetesSwap(name) && slices.Contains(dirs, filepath.Dir(name)))
+	}
+
+	// Files that are watched directly, rather than through their directory,
+	// lose their watch when they are removed or renamed over
+	var rewatch []string
+	for _, p := range []string{certPath, keyPath} {
+		if slices.Contains(watcher.WatchList(), p) {
+			rewatch = append(rewatch, p)
+		}
+	}
+
+	watchEvents(watcher, match, func() {
+		for _, p := range rewatch {
+			if err := watcher.Add(p); err != nil {
+				log.Logger.Warnf("error re-establishing watch on %s: %v", p, err)
 			}
+		}
+		signerWithMutex, err := loadKeyPair(certPath, keyPath, keyPass)
+		if recordReload(certPath, err) != nil {
+			// Don't sweat it if this errors out. One file might
+			// have updated and the other isn't causing a key-pair
+			// mismatch, and the next update will be picked up
+			log.Logger.Warnf("error reloading %s and %s: %v", certPath, keyPath, err)
+			return
+		}
 
-			callback(signerWithMutex.Certs, signerWithMutex.Signer)
+		callback(signerWithMutex.Certs, signerWithMutex.Signer)
+	})
+}
+
+// watchEvents calls reload once changes to files that match have stopped
+// arriving for debounceInterval. Files that are written, created, removed or
+// renamed over all count as changes. It returns when the watcher is closed.
+func watchEvents(watcher *fsnotify.Watcher, match func(name string) bool, reload func()) {
+	var timer *time.Timer
+	var fire <-chan time.Time
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Remove) && !event.Has(fsnotify.Rename) {
+				continue
+			}
+			if !match(event.Name) {
+				continue
+			}
+			if timer == nil {
+				timer = time.NewTimer(debounceInterval)
+			} else {
+				timer.Reset(debounceInterval)
+			}
+			fire = timer.C
+		case <-fire:
+			fire = nil
+			reload()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Logger.Errorf("fileca watch error: %v", err)
 		}
 	}
 }
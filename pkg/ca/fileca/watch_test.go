@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 func cp(src, dst string) error {
@@ -105,3 +106,155 @@ func TestIOWatch(t *testing.T) {
 		t.Error("Should have loaded an ecdsa private key on update")
 	}
 }
+
+// waitForKey waits for the file CA to load a key of type K.
+func waitForKey[K crypto.Signer](t *testing.T, fca *fileCA) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		_, key := fca.GetSignerWithChain()
+		if _, ok := key.(K); ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("file CA did not load the updated key, have %T", key)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestWatchKubernetesSecretSwap(t *testing.T) {
+	// Lay out the directory like a Kubernetes Secret volume, where the
+	// files are symlinks through the ..data symlink to a timestamped
+	// directory
+	dir := t.TempDir()
+	for _, ts := range []string{"..2026_01", "..2026_02"} {
+		if err := os.Mkdir(filepath.Join(dir, ts), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for src, dst := range map[string]string{
+		"testdata/ed25519-cert.pem": "..2026_01/cert.pem",
+		"testdata/ed25519-key.pem":  "..2026_01/key.pem",
+		"testdata/ecdsa-cert.pem":   "..2026_02/cert.pem",
+		"testdata/ecdsa-key.pem":    "..2026_02/key.pem",
+	} {
+		if err := cp(src, filepath.Join(dir, dst)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("..2026_01", filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"cert.pem", "key.pem"} {
+		if err := os.Symlink(filepath.Join("..data", name), filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ca, err := NewFileCA(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), testKeyPass, true)
+	if err != nil {
+		t.Fatalf("NewFileCA() = %v", err)
+	}
+	defer ca.Close()
+	fca := ca.(*fileCA)
+
+	// Swap ..data atomically, as the kubelet does
+	if err := os.Symlink("..2026_02", filepath.Join(dir, "..data_tmp")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(dir, "..2026_01")); err != nil {
+		t.Fatal(err)
+	}
+
+	waitForKey[*ecdsa.PrivateKey](t, fca)
+}
+
+func TestWatchRemovedFiles(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "key.pem")
+	certPath := filepath.Join(dir, "cert.pem")
+	if err := cp("testdata/ed25519-key.pem", keyPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := cp("testdata/ed25519-cert.pem", certPath); err != nil {
+		t.Fatal(err)
+	}
+
+	ca, err := NewFileCA(certPath, keyPath, testKeyPass, true)
+	if err != nil {
+		t.Fatalf("NewFileCA() = %v", err)
+	}
+	defer ca.Close()
+	fca := ca.(*fileCA)
+
+	// Removing the files keeps the current key pair and does not stop the
+	// watch
+	for _, p := range []string{certPath, keyPath} {
+		if err := os.Remove(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(2 * debounceInterval)
+	if _, key := fca.GetSignerWithChain(); key == nil {
+		t.Fatal("expected current key pair to be kept")
+	}
+
+	if err := cp("testdata/ecdsa-key.pem", keyPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := cp("testdata/ecdsa-cert.pem", certPath); err != nil {
+		t.Fatal(err)
+	}
+	waitForKey[*ecdsa.PrivateKey](t, fca)
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "key.pem")
+	certPath := filepath.Join(dir, "cert.pem")
+	if err := cp("testdata/ed25519-key.pem", keyPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := cp("testdata/ed25519-cert.pem", certPath); err != nil {
+		t.Fatal(err)
+	}
+	ca, err := NewFileCA(certPath, keyPath, testKeyPass, false)
+	if err != nil {
+		t.Fatalf("NewFileCA() = %v", err)
+	}
+	fca := ca.(*fileCA)
+
+	// A mismatched pair fails to reload and keeps the current pair
+	failures := testutil.ToFloat64(metricReloadFailures.WithLabelValues(certPath))
+	if err := cp("testdata/ecdsa-cert.pem", certPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := fca.Reload(); err == nil {
+		t.Fatal("expected mismatched key pair to fail to reload")
+	}
+	if got := testutil.ToFloat64(metricReloadFailures.WithLabelValues(certPath)); got != failures+1 {
+		t.Errorf("expected reload failure to be counted, counter went from %v to %v", failures, got)
+	}
+	if _, key := fca.GetSignerWithChain(); key == nil {
+		t.Fatal("expected current key pair to be kept")
+	}
+
+	if err := cp("testdata/ecdsa-key.pem", keyPath); err != nil {
+		t.Fatal(err)
+	}
+	before := time.Now().Unix()
+	if err := fca.Reload(); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+	if _, key := fca.GetSignerWithChain(); key == nil {
+		t.Fatal("expected key pair to be loaded")
+	}
+	waitForKey[*ecdsa.PrivateKey](t, fca)
+	if got := testutil.ToFloat64(metricLastReload.WithLabelValues(certPath)); got < float64(before) {
+		t.Errorf("expected last reload time to be updated, got %v", got)
+	}
+}
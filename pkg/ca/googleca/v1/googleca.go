@@ -134,6 +134,9 @@ func Req(parent, certAuthority string, pemBytes []byte, cert *x509.Certificate)
 	req := &privatecapb.CreateCertificateRequest{
 		Parent: parent,
 		Certificate: &privatecapb.Certificate{
+			// CA Service sets NotBefore to the time of issuance, so a
+			// NotBeforeSkew cannot be applied; serve rejects configs that
+			// set one for issuers this backend issues for
 			Lifetime: durationpb.New(time.Until(cert.NotAfter)),
 			CertificateConfig: &privatecapb.Certificate_Config{
 				Config: &privatecapb.CertificateConfig{
@@ -238,17 +241,17 @@ func (c *CertAuthorityService) listCertificateAuthorityTrustBundle(ctx context.C
 func (c *CertAuthorityService) CreateCertificate(ctx context.Context, principal identity.Principal, publicKey crypto.PublicKey) (*ca.CodeSigningCertificate, error) {
 	cert, err := ca.MakeX509(ctx, principal, publicKey)
 	if err != nil {
-		return nil, ca.ValidationError(err)
+		return nil, ca.ValidationError{Err: err}
 	}
 
 	pubKeyBytes, err := cryptoutils.MarshalPublicKeyToPEM(publicKey)
 	if err != nil {
-		return nil, ca.ValidationError(err)
+		return nil, ca.ValidationError{Err: err}
 	}
 
 	req, err := Req(c.caPoolResource, c.certAuthorityID, pubKeyBytes, cert)
 	if err != nil {
-		return nil, ca.ValidationError(err)
+		return nil, ca.ValidationError{Err: err}
 	}
 
 	resp, err := c.client.CreateCertificate(ctx, req)
@@ -0,0 +1,70 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ca
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+// probeMessage is signed by Probe.
+var probeMessage = []byte("fulcio CA health probe")
+
+// Probe checks that the default backend of ca can issue certificates. If
+// the backend holds its signing key, such as in a KMS or HSM, a probe
+// message is signed with the key and verified against the issuing
+// certificate. Otherwise the backend's trust bundle is fetched.
+func Probe(ctx context.Context, ca CertificateAuthority) error {
+	backend, err := Resolve(ctx, ca)
+	if err != nil {
+		return err
+	}
+	s, ok := backend.(SignerWithChain)
+	if !ok {
+		_, err := backend.TrustBundle(ctx)
+		return err
+	}
+
+	chain, signer := s.GetSignerWithChain()
+	if len(chain) == 0 || signer == nil {
+		return errors.New("CA has no signing certificate")
+	}
+	var sig []byte
+	if _, ok := signer.Public().(ed25519.PublicKey); ok {
+		sig, err = signer.Sign(rand.Reader, probeMessage, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(probeMessage)
+		sig, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		return fmt.Errorf("signing probe: %w", err)
+	}
+	verifier, err := signature.LoadVerifier(chain[0].PublicKey, crypto.SHA256)
+	if err != nil {
+		return fmt.Errorf("loading verifier of signing certificate: %w", err)
+	}
+	if err := verifier.VerifySignature(bytes.NewReader(sig), bytes.NewReader(probeMessage)); err != nil {
+		return fmt.Errorf("probe signature does not match signing certificate: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ca
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"testing"
+
+	"github.com/sigstore/fulcio/pkg/identity"
+	"github.com/sigstore/fulcio/pkg/test"
+)
+
+// remoteCA is a CertificateAuthority that does not hold its signing key,
+// and only serves its trust bundle.
+type remoteCA struct {
+	bundleErr error
+}
+
+func (r *remoteCA) CreateCertificate(context.Context, identity.Principal, crypto.PublicKey) (*CodeSigningCertificate, error) {
+	return nil, errors.New("unimplemented")
+}
+
+func (r *remoteCA) TrustBundle(context.Context) ([][]*x509.Certificate, error) {
+	return nil, r.bundleErr
+}
+
+func (r *remoteCA) Close() error {
+	return nil
+}
+
+// probeCA is a CertificateAuthority that holds its signing key.
+type probeCA struct {
+	remoteCA
+	SignerWithChain
+}
+
+func TestProbe(t *testing.T) {
+	rootCert, rootKey, _ := test.GenerateRootCA()
+	_, otherKey, _ := test.GenerateRootCA()
+	edPub, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	edCert, err := test.GenerateRootCAFromSigner(edKey)
+	if err != nil {
+		t.Fatalf("GenerateRootCAFromSigner() = %v", err)
+	}
+	if !edPub.Equal(edCert.PublicKey) {
+		t.Fatal("unexpected Ed25519 certificate key")
+	}
+
+	tests := map[string]struct {
+		ca      CertificateAuthority
+		wantErr bool
+	}{
+		"signer":           {ca: &probeCA{SignerWithChain: &SignerCerts{Certs: []*x509.Certificate{rootCert}, Signer: rootKey}}},
+		"ed25519 signer":   {ca: &probeCA{SignerWithChain: &SignerCerts{Certs: []*x509.Certificate{edCert}, Signer: edKey}}},
+		"mismatched key":   {ca: &probeCA{SignerWithChain: &SignerCerts{Certs: []*x509.Certificate{rootCert}, Signer: otherKey}}, wantErr: true},
+		"no chain":         {ca: &probeCA{SignerWithChain: &SignerCerts{Signer: rootKey}}, wantErr: true},
+		"remote CA":        {ca: &remoteCA{}},
+		"remote CA failed": {ca: &remoteCA{bundleErr: errors.New("unavailable")}, wantErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := Probe(context.Background(), test.ca); (err != nil) != test.wantErr {
+				t.Errorf("Probe() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ca
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+)
+
+// Router is implemented by CertificateAuthorities that delegate each request
+// to one of several backends. Callers that need a backend's optional
+// interfaces, such as EmbeddedSCTCA, should use Resolve to find the backend
+// that handles a request.
+type Router interface {
+	// Route returns the backend that handles requests made with ctx.
+	Route(ctx context.Context) (CertificateAuthority, error)
+}
+
+type backendKey struct{}
+
+// WithBackend returns a context selecting the named backend for requests
+// made with it. An empty name selects the default backend.
+func WithBackend(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, backendKey{}, name)
+}
+
+// BackendFromContext returns the backend name set with WithBackend, or the
+// empty string if none was set.
+func BackendFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(backendKey{}).(string)
+	return name
+}
+
+// Resolve returns the CertificateAuthority that handles requests made with
+// ctx, following Routers until a backend is reached.
+func Resolve(ctx context.Context, ca CertificateAuthority) (CertificateAuthority, error) {
+	for {
+		r, ok := ca.(Router)
+		if !ok {
+			return ca, nil
+		}
+		next, err := r.Route(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ca = next
+	}
+}
+
+// AppendChains appends to bundle the chains that it does not already contain,
+// for CAs whose trust bundle is the union of their backends' trust bundles.
+func AppendChains(bundle [][]*x509.Certificate, chains ...[]*x509.Certificate) [][]*x509.Certificate {
+	for _, chain := range chains {
+		if !containsChain(bundle, chain) {
+			bundle = append(bundle, chain)
+		}
+	}
+	return bundle
+}
+
+// containsChain reports whether bundle already has a chain with the same
+// certificates as chain, which happens when backends share an intermediate.
+func containsChain(bundle [][]*x509.Certificate, chain []*x509.Certificate) bool {
+	for _, c := range bundle {
+		if len(c) != len(chain) {
+			continue
+		}
+		same := true
+		for i := range c {
+			if !bytes.Equal(c[i].Raw, chain[i].Raw) {
+				same = false
+				break
+			}
+		}
+		if same {
+			return true
+		}
+	}
+	return false
+}
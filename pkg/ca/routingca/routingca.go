@@ -0,0 +1,136 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package routingca implements a CertificateAuthority that issues each
+// certificate with a backend selected by name, so that different identities
+// can be signed by different intermediates.
+package routingca
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/sigstore/fulcio/pkg/ca"
+	"github.com/sigstore/fulcio/pkg/identity"
+)
+
+type routingCA struct {
+	def      ca.CertificateAuthority
+	backends map[string]ca.CertificateAuthority
+}
+
+var (
+	_ ca.Router   = (*routingCA)(nil)
+	_ ca.Reloader = (*routingCA)(nil)
+)
+
+// NewRoutingCA returns a CertificateAuthority that issues certificates with
+// the backend named by ca.WithBackend on the request context, or with def if
+// no backend is named. Its trust bundle is the union of the trust bundles
+// of all backends.
+func NewRoutingCA(def ca.CertificateAuthority, backends map[string]ca.CertificateAuthority) (ca.CertificateAuthority, error) {
+	if def == nil {
+		return nil, errors.New("routing CA requires a default backend")
+	}
+	for name, b := range backends {
+		if name == "" {
+			return nil, errors.New("CA backend names must not be empty")
+		}
+		if b == nil {
+			return nil, fmt.Errorf("CA backend %q is nil", name)
+		}
+	}
+	return &routingCA{def: def, backends: backends}, nil
+}
+
+// names returns the names of the backends, in sorted order.
+func (r *routingCA) names() []string {
+	names := make([]string, 0, len(r.backends))
+	for name := range r.backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *routingCA) Route(ctx context.Context) (ca.CertificateAuthority, error) {
+	name := ca.BackendFromContext(ctx)
+	if name == "" {
+		return r.def, nil
+	}
+	b, ok := r.backends[name]
+	if !ok {
+		return nil, fmt.Errorf("no CA backend named %q", name)
+	}
+	return b, nil
+}
+
+func (r *routingCA) CreateCertificate(ctx context.Context, principal identity.Principal, publicKey crypto.PublicKey) (*ca.CodeSigningCertificate, error) {
+	b, err := r.Route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return b.CreateCertificate(ctx, principal, publicKey)
+}
+
+func (r *routingCA) TrustBundle(ctx context.Context) ([][]*x509.Certificate, error) {
+	var bundle [][]*x509.Certificate
+	add := func(b ca.CertificateAuthority) error {
+		chains, err := b.TrustBundle(ctx)
+		if err != nil {
+			return err
+		}
+		bundle = ca.AppendChains(bundle, chains...)
+		return nil
+	}
+
+	if err := add(r.def); err != nil {
+		return nil, err
+	}
+	for _, name := range r.names() {
+		if err := add(r.backends[name]); err != nil {
+			return nil, fmt.Errorf("CA backend %q: %w", name, err)
+		}
+	}
+	return bundle, nil
+}
+
+func (r *routingCA) Close() error {
+	errs := []error{r.def.Close()}
+	for _, name := range r.names() {
+		errs = append(errs, r.backends[name].Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Reload reloads the backends that support it.
+func (r *routingCA) Reload() error {
+	var errs []error
+	if rl, ok := r.def.(ca.Reloader); ok {
+		errs = append(errs, rl.Reload())
+	}
+	for _, name := range r.names() {
+		if rl, ok := r.backends[name].(ca.Reloader); ok {
+			if err := rl.Reload(); err != nil {
+				errs = append(errs, fmt.Errorf("CA backend %q: %w", name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
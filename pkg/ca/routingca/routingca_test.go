@@ -0,0 +1,144 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package routingca
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"testing"
+
+	"github.com/sigstore/fulcio/pkg/ca"
+	"github.com/sigstore/fulcio/pkg/ca/ephemeralca"
+)
+
+type testPrincipal struct{}
+
+func (tp testPrincipal) Name(context.Context) string {
+	return "doesntmatter"
+}
+
+func (tp testPrincipal) Embed(_ context.Context, cert *x509.Certificate) error {
+	cert.EmailAddresses = []string{"alice@example.com"}
+	return nil
+}
+
+func newEphemeralCA(t *testing.T) *ephemeralca.EphemeralCA {
+	t.Helper()
+	eca, err := ephemeralca.NewEphemeralCA()
+	if err != nil {
+		t.Fatalf("NewEphemeralCA() = %v", err)
+	}
+	return eca
+}
+
+func rootOf(t *testing.T, b ca.CertificateAuthority) *x509.Certificate {
+	t.Helper()
+	chains, err := b.TrustBundle(context.Background())
+	if err != nil {
+		t.Fatalf("TrustBundle() = %v", err)
+	}
+	return chains[0][len(chains[0])-1]
+}
+
+func TestNewRoutingCA(t *testing.T) {
+	def := newEphemeralCA(t)
+	if _, err := NewRoutingCA(nil, nil); err == nil {
+		t.Error("expected error without a default backend")
+	}
+	if _, err := NewRoutingCA(def, map[string]ca.CertificateAuthority{"": def}); err == nil {
+		t.Error("expected error for empty backend name")
+	}
+	if _, err := NewRoutingCA(def, map[string]ca.CertificateAuthority{"other": nil}); err == nil {
+		t.Error("expected error for nil backend")
+	}
+}
+
+func TestCreateCertificate(t *testing.T) {
+	def, other := newEphemeralCA(t), newEphemeralCA(t)
+	rca, err := NewRoutingCA(def, map[string]ca.CertificateAuthority{"other": other})
+	if err != nil {
+		t.Fatalf("NewRoutingCA() = %v", err)
+	}
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		backend string
+		want    ca.CertificateAuthority
+	}{
+		{backend: "", want: def},
+		{backend: "other", want: other},
+	} {
+		ctx := ca.WithBackend(context.Background(), c.backend)
+		csc, err := rca.CreateCertificate(ctx, testPrincipal{}, priv.Public())
+		if err != nil {
+			t.Fatalf("CreateCertificate(%q) = %v", c.backend, err)
+		}
+		if err := csc.FinalCertificate.CheckSignatureFrom(rootOf(t, c.want)); err != nil {
+			t.Errorf("expected certificate for %q to be signed by its backend: %v", c.backend, err)
+		}
+
+		resolved, err := ca.Resolve(ctx, rca)
+		if err != nil {
+			t.Fatalf("Resolve(%q) = %v", c.backend, err)
+		}
+		if resolved != c.want {
+			t.Errorf("expected Resolve(%q) to return its backend", c.backend)
+		}
+		// Callers rely on Resolve to reach the precertificate flow
+		if _, ok := resolved.(ca.EmbeddedSCTCA); !ok {
+			t.Errorf("expected resolved backend %q to support embedded SCTs", c.backend)
+		}
+	}
+
+	ctx := ca.WithBackend(context.Background(), "missing")
+	if _, err := rca.CreateCertificate(ctx, testPrincipal{}, priv.Public()); err == nil {
+		t.Error("expected error for unknown backend")
+	}
+	if _, err := ca.Resolve(ctx, rca); err == nil {
+		t.Error("expected Resolve to fail for unknown backend")
+	}
+}
+
+func TestTrustBundle(t *testing.T) {
+	def, a, b := newEphemeralCA(t), newEphemeralCA(t), newEphemeralCA(t)
+	// b is also registered under a second name, and its chain should only
+	// be returned once
+	rca, err := NewRoutingCA(def, map[string]ca.CertificateAuthority{"a": a, "b": b, "b2": b})
+	if err != nil {
+		t.Fatalf("NewRoutingCA() = %v", err)
+	}
+	chains, err := rca.TrustBundle(context.Background())
+	if err != nil {
+		t.Fatalf("TrustBundle() = %v", err)
+	}
+	if len(chains) != 3 {
+		t.Fatalf("expected 3 chains, got %d", len(chains))
+	}
+	for i, want := range []ca.CertificateAuthority{def, a, b} {
+		if !chains[i][0].Equal(rootOf(t, want)) {
+			t.Errorf("unexpected chain at index %d", i)
+		}
+	}
+	if err := rca.Close(); err != nil {
+		t.Errorf("Close() = %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ca
+
+import (
+	"context"
+	"time"
+
+	"github.com/sigstore/fulcio/pkg/config"
+)
+
+// Validity describes the validity window of an issued certificate.
+type Validity struct {
+	// Lifetime is the duration between issuance and NotAfter.
+	Lifetime time.Duration
+	// NotBeforeSkew is how far NotBefore is backdated from issuance.
+	NotBeforeSkew time.Duration
+}
+
+type validityKey struct{}
+
+// WithValidity returns a context carrying the validity window to use for
+// certificates created with it.
+func WithValidity(ctx context.Context, v Validity) context.Context {
+	return context.WithValue(ctx, validityKey{}, v)
+}
+
+// ValidityFromContext returns the validity window set with WithValidity,
+// or the default ten minute window with no backdating.
+func ValidityFromContext(ctx context.Context) Validity {
+	v, ok := ctx.Value(validityKey{}).(Validity)
+	if !ok || v.Lifetime <= 0 {
+		v.Lifetime = config.DefaultCertificateLifetime
+	}
+	if v.NotBeforeSkew < 0 {
+		v.NotBeforeSkew = 0
+	}
+	return v
+}
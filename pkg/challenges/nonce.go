@@ -0,0 +1,105 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package challenges
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultNonceTTL is how long a nonce is accepted after it is issued.
+const DefaultNonceTTL = 5 * time.Minute
+
+const (
+	nonceRandomLen = 16
+	// 8 byte expiry, random value, HMAC-SHA256 tag
+	nonceLen = 8 + nonceRandomLen + sha256.Size
+)
+
+// NonceIssuer issues and verifies stateless, server-signed nonces that
+// clients sign as proof of possession of a private key. A nonce carries its
+// own expiry and an HMAC over it, so any replica sharing the key can verify
+// nonces issued by another.
+type NonceIssuer struct {
+	key []byte
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewNonceIssuer returns a NonceIssuer that authenticates nonces with key
+// and accepts them for ttl after issuance. If ttl is not positive,
+// DefaultNonceTTL is used.
+func NewNonceIssuer(key []byte, ttl time.Duration) (*NonceIssuer, error) {
+	if len(key) < 32 {
+		return nil, errors.New("nonce key must be at least 32 bytes")
+	}
+	if ttl <= 0 {
+		ttl = DefaultNonceTTL
+	}
+	return &NonceIssuer{key: key, ttl: ttl, now: time.Now}, nil
+}
+
+// NewEphemeralNonceIssuer returns a NonceIssuer with a random key. Nonces it
+// issues are only accepted by the same process.
+func NewEphemeralNonceIssuer(ttl time.Duration) (*NonceIssuer, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return NewNonceIssuer(key, ttl)
+}
+
+// Issue returns a new nonce and the time at which it expires.
+func (n *NonceIssuer) Issue() (string, time.Time, error) {
+	expiry := n.now().Add(n.ttl).Truncate(time.Second)
+	buf := make([]byte, 8+nonceRandomLen, nonceLen)
+	binary.BigEndian.PutUint64(buf, uint64(expiry.Unix())) // nolint:gosec
+	if _, err := rand.Read(buf[8:]); err != nil {
+		return "", time.Time{}, err
+	}
+	buf = append(buf, n.mac(buf)...)
+	return base64.RawURLEncoding.EncodeToString(buf), expiry, nil
+}
+
+// Verify checks that nonce was issued by this NonceIssuer and has not
+// expired.
+func (n *NonceIssuer) Verify(nonce string) error {
+	buf, err := base64.RawURLEncoding.DecodeString(nonce)
+	if err != nil || len(buf) != nonceLen {
+		return errors.New("malformed challenge nonce")
+	}
+	msg, tag := buf[:8+nonceRandomLen], buf[8+nonceRandomLen:]
+	if !hmac.Equal(tag, n.mac(msg)) {
+		return errors.New("challenge nonce was not issued by this server")
+	}
+	expiry := time.Unix(int64(binary.BigEndian.Uint64(msg)), 0) // nolint:gosec
+	if n.now().After(expiry) {
+		return fmt.Errorf("challenge nonce expired at %s", expiry.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+func (n *NonceIssuer) mac(msg []byte) []byte {
+	h := hmac.New(sha256.New, n.key)
+	h.Write(msg)
+	return h.Sum(nil)
+}
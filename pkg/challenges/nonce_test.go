@@ -0,0 +1,91 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package challenges
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestNonceIssuer(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	issuer, err := NewNonceIssuer(key, time.Minute)
+	if err != nil {
+		t.Fatalf("NewNonceIssuer() = %v", err)
+	}
+
+	nonce, expiry, err := issuer.Issue()
+	if err != nil {
+		t.Fatalf("Issue() = %v", err)
+	}
+	if time.Until(expiry) > time.Minute {
+		t.Errorf("expected expiry within a minute, got %v", expiry)
+	}
+	if err := issuer.Verify(nonce); err != nil {
+		t.Errorf("Verify() = %v", err)
+	}
+
+	other, _, err := issuer.Issue()
+	if err != nil {
+		t.Fatalf("Issue() = %v", err)
+	}
+	if other == nonce {
+		t.Error("expected distinct nonces")
+	}
+
+	// A replica sharing the key accepts the nonce
+	replica, err := NewNonceIssuer(key, time.Minute)
+	if err != nil {
+		t.Fatalf("NewNonceIssuer() = %v", err)
+	}
+	if err := replica.Verify(nonce); err != nil {
+		t.Errorf("Verify() on replica = %v", err)
+	}
+
+	// A server with a different key does not
+	stranger, err := NewEphemeralNonceIssuer(time.Minute)
+	if err != nil {
+		t.Fatalf("NewEphemeralNonceIssuer() = %v", err)
+	}
+	if err := stranger.Verify(nonce); err == nil {
+		t.Error("expected nonce from another key to be rejected")
+	}
+
+	// Tampering with the expiry invalidates the tag
+	raw, _ := base64.RawURLEncoding.DecodeString(nonce)
+	raw[7]++
+	if err := issuer.Verify(base64.RawURLEncoding.EncodeToString(raw)); err == nil {
+		t.Error("expected tampered nonce to be rejected")
+	}
+
+	if err := issuer.Verify("not-a-nonce"); err == nil {
+		t.Error("expected malformed nonce to be rejected")
+	}
+
+	// Expired nonces are rejected
+	issuer.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if err := issuer.Verify(nonce); err == nil {
+		t.Error("expected expired nonce to be rejected")
+	}
+}
+
+func TestNewNonceIssuerShortKey(t *testing.T) {
+	if _, err := NewNonceIssuer([]byte("short"), time.Minute); err == nil {
+		t.Fatal("expected short key to be rejected")
+	}
+}
@@ -37,7 +37,10 @@ import (
 	"github.com/sigstore/fulcio/pkg/certificate"
 	fulciogrpc "github.com/sigstore/fulcio/pkg/generated/protobuf"
 	"github.com/sigstore/fulcio/pkg/log"
+	"github.com/sigstore/fulcio/pkg/policy"
+	"github.com/sigstore/fulcio/pkg/tracing"
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"go.opentelemetry.io/otel/attribute"
 	"go.yaml.in/yaml/v3"
 )
 
@@ -85,6 +88,11 @@ type FulcioConfig struct {
 	verifiers map[string][]*verifierWithConfig
 	// lru is an LRU cache of recently used verifiers for our meta issuers.
 	lru *lru.TwoQueueCache[string, []*verifierWithConfig]
+	// metaRegexes maps our MetaIssuers to their compiled regular expressions.
+	metaRegexes map[string]*regexp.Regexp
+	// policies maps our OIDCIssuers and MetaIssuers to their compiled
+	// issuance policies.
+	policies map[string]*policy.Policy
 }
 
 type IssuerMetadata struct {
@@ -139,6 +147,100 @@ type OIDCIssuer struct {
 	// This should only be set to true for trusted internal identity providers (e.g., Microsoft Entra, ADFS)
 	// that perform email verification through their own processes but don't include the email_verified claim.
 	SkipEmailVerification bool `json:"SkipEmailVerification,omitempty" yaml:"skip-email-verification,omitempty"`
+
+	// Optional, the lifetime of certificates issued for this issuer when the
+	// client does not request one, e.g. "5m". Defaults to 10 minutes, or to
+	// the max certificate lifetime if that is shorter.
+	DefaultCertificateLifetime string `json:"DefaultCertificateLifetime,omitempty" yaml:"default-certificate-lifetime,omitempty"`
+	// Optional, the upper bound for a client-requested certificate lifetime,
+	// e.g. "30m". Requests for longer lifetimes are clamped to this value.
+	// Defaults to the default certificate lifetime.
+	MaxCertificateLifetime string `json:"MaxCertificateLifetime,omitempty" yaml:"max-certificate-lifetime,omitempty"`
+	// Optional, how far NotBefore is backdated from the time of issuance to
+	// tolerate clock skew between Fulcio and verifiers, e.g. "30s". Not
+	// supported by the googleca backend, which cannot backdate certificates.
+	NotBeforeSkew string `json:"NotBeforeSkew,omitempty" yaml:"not-before-skew,omitempty"`
+
+	// RequireChallenge requires proof of possession for this issuer to be a
+	// signature over a server-issued nonce from GetChallenge, rather than over
+	// the token's subject, so that a leaked proof cannot be replayed.
+	RequireChallenge bool `json:"RequireChallenge,omitempty" yaml:"require-challenge,omitempty"`
+
+	// ReplayProtection rejects tokens from this issuer that have already
+	// been used to request a certificate, so that a stolen token can mint
+	// at most one certificate.
+	ReplayProtection bool `json:"ReplayProtection,omitempty" yaml:"replay-protection,omitempty"`
+
+	// Optional, rules that must all hold for a certificate to be issued for
+	// this issuer, e.g. to only issue for repositories of some organizations.
+	IssuancePolicy []IssuancePolicyRule `json:"IssuancePolicy,omitempty" yaml:"issuance-policy,omitempty"`
+
+	// Optional, the name of the CA backend that signs certificates for this
+	// issuer, as configured with --ca-backends. Defaults to the backend
+	// selected with --ca.
+	CA string `json:"CA,omitempty" yaml:"ca,omitempty"`
+}
+
+// IssuancePolicyRule is a named CEL expression over the token's claims and
+// the certificate's extensions that must evaluate to true for a certificate
+// to be issued. See the policy package for the variables that expressions
+// can refer to.
+type IssuancePolicyRule struct {
+	Name       string `json:"Name" yaml:"name"`
+	Expression string `json:"Expression" yaml:"expression"`
+}
+
+// Policy compiles the issuance policy of this issuer.
+func (iss OIDCIssuer) Policy() (*policy.Policy, error) {
+	rules := make([]policy.Rule, 0, len(iss.IssuancePolicy))
+	for _, r := range iss.IssuancePolicy {
+		rules = append(rules, policy.Rule{Name: r.Name, Expression: r.Expression})
+	}
+	return policy.New(rules)
+}
+
+// DefaultCertificateLifetime is the lifetime of issued certificates for
+// issuers that do not configure one.
+const DefaultCertificateLifetime = 10 * time.Minute
+
+// CertificateLifetime returns the lifetime of a certificate issued for this
+// issuer. A requested lifetime of zero selects the issuer's default, and
+// requests above the issuer's maximum are clamped to the maximum.
+func (iss OIDCIssuer) CertificateLifetime(requested time.Duration) time.Duration {
+	def := DefaultCertificateLifetime
+	maxLifetime := time.Duration(0)
+	if d, err := time.ParseDuration(iss.MaxCertificateLifetime); err == nil && iss.MaxCertificateLifetime != "" {
+		maxLifetime = d
+		// An issuer that only sets a shorter max defaults to it
+		def = min(def, maxLifetime)
+	}
+	if d, err := time.ParseDuration(iss.DefaultCertificateLifetime); err == nil && iss.DefaultCertificateLifetime != "" {
+		def = d
+	}
+	if maxLifetime == 0 {
+		maxLifetime = def
+	}
+	switch {
+	case requested <= 0:
+		return def
+	case requested > maxLifetime:
+		return maxLifetime
+	default:
+		return requested
+	}
+}
+
+// CertificateNotBeforeSkew returns how far NotBefore is backdated for
+// certificates issued for this issuer.
+func (iss OIDCIssuer) CertificateNotBeforeSkew() time.Duration {
+	if iss.NotBeforeSkew == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(iss.NotBeforeSkew)
+	if err != nil {
+		return 0
+	}
+	return d
 }
 
 func MetaRegex(issuer string) (*regexp.Regexp, error) {
@@ -171,33 +273,76 @@ func (fc *FulcioConfig) GetIssuer(issuerURL string) (OIDCIssuer, bool) {
 		return iss, ok
 	}
 
+	if _, iss, ok := fc.MatchMetaIssuer(issuerURL); ok {
+		// If it matches, then return a concrete OIDCIssuer
+		// configuration for this issuer URL.
+		return OIDCIssuer{
+			IssuerURL:             issuerURL,
+			ClientID:              iss.ClientID,
+			Type:                  iss.Type,
+			IssuerClaim:           iss.IssuerClaim,
+			SubjectDomain:         iss.SubjectDomain,
+			CIProvider:            iss.CIProvider,
+			SkipEmailVerification: iss.SkipEmailVerification,
+
+			DefaultCertificateLifetime: iss.DefaultCertificateLifetime,
+			MaxCertificateLifetime:     iss.MaxCertificateLifetime,
+			NotBeforeSkew:              iss.NotBeforeSkew,
+			RequireChallenge:           iss.RequireChallenge,
+			ReplayProtection:           iss.ReplayProtection,
+			IssuancePolicy:             iss.IssuancePolicy,
+			CA:                         iss.CA,
+		}, true
+	}
+
+	return OIDCIssuer{}, false
+}
+
+// IssuerPolicy returns the compiled issuance policy of the issuer for
+// issuerURL. Issuers without a configuration have an empty policy.
+func (fc *FulcioConfig) IssuerPolicy(issuerURL string) (*policy.Policy, error) {
+	key := issuerURL
+	iss, ok := fc.OIDCIssuers[issuerURL]
+	if !ok {
+		key, iss, _ = fc.MatchMetaIssuer(issuerURL)
+	}
+	if p, ok := fc.policies[key]; ok {
+		return p, nil
+	}
+	// The config was not loaded with Read or Load
+	return iss.Policy()
+}
+
+// MatchMetaIssuer returns the MetaIssuers pattern matching issuerURL and its
+// configuration, and whether one matches.
+func (fc *FulcioConfig) MatchMetaIssuer(issuerURL string) (string, OIDCIssuer, bool) {
 	for meta, iss := range fc.MetaIssuers {
-		re, err := MetaRegex(meta)
-		if err != nil {
-			continue // Shouldn't happen, we check parsing the config
+		re, ok := fc.metaRegexes[meta]
+		if !ok {
+			// The config was not loaded with Read or Load
+			var err error
+			if re, err = MetaRegex(meta); err != nil {
+				continue // Shouldn't happen, we check parsing the config
+			}
 		}
 		if re.MatchString(issuerURL) {
-			// If it matches, then return a concrete OIDCIssuer
-			// configuration for this issuer URL.
-			return OIDCIssuer{
-				IssuerURL:             issuerURL,
-				ClientID:              iss.ClientID,
-				Type:                  iss.Type,
-				IssuerClaim:           iss.IssuerClaim,
-				SubjectDomain:         iss.SubjectDomain,
-				CIProvider:            iss.CIProvider,
-				SkipEmailVerification: iss.SkipEmailVerification,
-			}, true
+			return meta, iss, true
 		}
 	}
-
-	return OIDCIssuer{}, false
+	return "", OIDCIssuer{}, false
 }
 
 // GetVerifier fetches a token verifier for the given `issuerURL`
 // coming from an incoming OIDC token.  If no matching configuration
 // is found, then it returns `false`.
 func (fc *FulcioConfig) GetVerifier(issuerURL string, opts ...InsecureOIDCConfigOption) (*oidc.IDTokenVerifier, bool) {
+	return fc.GetVerifierContext(context.Background(), issuerURL, opts...)
+}
+
+// GetVerifierContext is GetVerifier, tracing OIDC discovery as a child of
+// the span in ctx. Discovery is not cancelled with ctx, since the verifier
+// is cached for later requests.
+func (fc *FulcioConfig) GetVerifierContext(ctx context.Context, issuerURL string, opts ...InsecureOIDCConfigOption) (*oidc.IDTokenVerifier, bool) {
 	iss, ok := fc.GetIssuer(issuerURL)
 	if !ok {
 		return nil, false
@@ -235,7 +380,8 @@ func (fc *FulcioConfig) GetVerifier(issuerURL string, opts ...InsecureOIDCConfig
 		return nil, false
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultOIDCDiscoveryTimeout)
+	ctx, span := tracing.Start(ctx, "oidc.discovery", attribute.String("oidc.issuer", issuerURL))
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), defaultOIDCDiscoveryTimeout)
 	defer cancel()
 
 	// RHTAS FIPS - DO NOT REMOVE
@@ -249,6 +395,7 @@ func (fc *FulcioConfig) GetVerifier(issuerURL string, opts ...InsecureOIDCConfig
 		provider, err = oidc.NewProvider(oidc.ClientContext(ctx, client), issuerURL)
 	})
 	// ========================================
+	tracing.End(span, err)
 	if err != nil {
 		log.Logger.Errorf("Failed to create provider for issuer URL %q: %v", issuerURL, err)
 		return nil, false
@@ -286,6 +433,7 @@ func (fc *FulcioConfig) ToIssuers() []*fulciogrpc.OIDCIssuer {
 			IssuerType:            cfgIss.Type.String(),
 			SubjectDomain:         cfgIss.SubjectDomain,
 			SkipEmailVerification: cfgIss.SkipEmailVerification,
+			RequireChallenge:      cfgIss.RequireChallenge,
 		}
 		issuers = append(issuers, issuer)
 	}
@@ -299,6 +447,7 @@ func (fc *FulcioConfig) ToIssuers() []*fulciogrpc.OIDCIssuer {
 			IssuerType:            cfgIss.Type.String(),
 			SubjectDomain:         cfgIss.SubjectDomain,
 			SkipEmailVerification: cfgIss.SkipEmailVerification,
+			RequireChallenge:      cfgIss.RequireChallenge,
 		}
 		issuers = append(issuers, issuer)
 	}
@@ -375,6 +524,27 @@ func (fc *FulcioConfig) prepare() error {
 		return fmt.Errorf("lru: %w", err)
 	}
 	fc.lru = cache
+
+	fc.policies = make(map[string]*policy.Policy)
+	for url, iss := range fc.OIDCIssuers {
+		if fc.policies[url], err = iss.Policy(); err != nil {
+			return fmt.Errorf("issuer %s: %w", url, err)
+		}
+	}
+	for meta, iss := range fc.MetaIssuers {
+		if fc.policies[meta], err = iss.Policy(); err != nil {
+			return fmt.Errorf("meta issuer %s: %w", meta, err)
+		}
+	}
+
+	fc.metaRegexes = make(map[string]*regexp.Regexp, len(fc.MetaIssuers))
+	for meta := range fc.MetaIssuers {
+		re, err := MetaRegex(meta)
+		if err != nil {
+			return fmt.Errorf("meta issuer %s: %w", meta, err)
+		}
+		fc.metaRegexes[meta] = re
+	}
 	return nil
 }
 
@@ -534,9 +704,17 @@ func validateConfig(conf *FulcioConfig) error {
 		if issuerToChallengeClaim(issuer.Type, issuer.ChallengeClaim) == "" {
 			return errors.New("issuer missing challenge claim")
 		}
+
+		if err := validateCertificateLifetimes(issuer); err != nil {
+			return fmt.Errorf("issuer %s: %w", issuer.IssuerURL, err)
+		}
+
+		if _, err := issuer.Policy(); err != nil {
+			return fmt.Errorf("issuer %s: %w", issuer.IssuerURL, err)
+		}
 	}
 
-	for _, metaIssuer := range conf.MetaIssuers {
+	for metaURL, metaIssuer := range conf.MetaIssuers {
 		if metaIssuer.CACert != "" {
 			rootCAs := x509.NewCertPool()
 			if ok := rootCAs.AppendCertsFromPEM([]byte(metaIssuer.CACert)); !ok {
@@ -553,11 +731,59 @@ func validateConfig(conf *FulcioConfig) error {
 		if issuerToChallengeClaim(metaIssuer.Type, metaIssuer.ChallengeClaim) == "" {
 			return errors.New("issuer missing challenge claim")
 		}
+
+		if err := validateCertificateLifetimes(metaIssuer); err != nil {
+			return fmt.Errorf("meta issuer %s: %w", metaURL, err)
+		}
+
+		if _, err := metaIssuer.Policy(); err != nil {
+			return fmt.Errorf("meta issuer %s: %w", metaURL, err)
+		}
 	}
 
 	return validateCIIssuerMetadata(conf)
 }
 
+// validateCertificateLifetimes checks that the certificate lifetime settings
+// of an issuer parse and are consistent with each other.
+func validateCertificateLifetimes(iss OIDCIssuer) error {
+	parse := func(name, value string) (time.Duration, error) {
+		if value == "" {
+			return 0, nil
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q: %w", name, value, err)
+		}
+		if d < 0 {
+			return 0, fmt.Errorf("%s must not be negative", name)
+		}
+		return d, nil
+	}
+	def, err := parse("default certificate lifetime", iss.DefaultCertificateLifetime)
+	if err != nil {
+		return err
+	}
+	maxLifetime, err := parse("max certificate lifetime", iss.MaxCertificateLifetime)
+	if err != nil {
+		return err
+	}
+	if _, err := parse("not before skew", iss.NotBeforeSkew); err != nil {
+		return err
+	}
+	if iss.DefaultCertificateLifetime != "" && def == 0 {
+		return errors.New("default certificate lifetime must be positive")
+	}
+	if iss.MaxCertificateLifetime != "" && maxLifetime == 0 {
+		return errors.New("max certificate lifetime must be positive")
+	}
+	// Without an explicit default, the default is clamped to the max
+	if maxLifetime != 0 && def > maxLifetime {
+		return fmt.Errorf("default certificate lifetime %s exceeds max certificate lifetime %s", def, maxLifetime)
+	}
+	return nil
+}
+
 var DefaultConfig = &FulcioConfig{
 	OIDCIssuers: map[string]OIDCIssuer{
 		"https://oauth2.sigstore.dev/auth": {
@@ -41,6 +41,7 @@ import (
 	"github.com/coreos/go-oidc/v3/oidc"
 	lru "github.com/hashicorp/golang-lru/v2"
 	"github.com/sigstore/fulcio/pkg/generated/protobuf"
+	"github.com/sigstore/fulcio/pkg/policy"
 )
 
 var validYamlCfg = `
@@ -209,6 +210,65 @@ func TestMetaURLs(t *testing.T) {
 	}
 }
 
+func TestMatchMetaIssuer(t *testing.T) {
+	cfg, err := Read([]byte(`{
+		"MetaIssuers": {
+			"https://oidc.eks.*.amazonaws.com/id/*": {
+				"ClientID": "sigstore",
+				"Type": "kubernetes"
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Read() = %v", err)
+	}
+	if len(cfg.metaRegexes) != 1 {
+		t.Fatalf("expected the meta issuer pattern to be compiled, got %v", cfg.metaRegexes)
+	}
+
+	meta, iss, ok := cfg.MatchMetaIssuer("https://oidc.eks.us-west-2.amazonaws.com/id/B02C93B6A2D30341AD01E1B6D48164CB")
+	if !ok {
+		t.Fatal("expected meta issuer to match")
+	}
+	if meta != "https://oidc.eks.*.amazonaws.com/id/*" || iss.Type != IssuerTypeKubernetes {
+		t.Errorf("unexpected match %q: %+v", meta, iss)
+	}
+	if _, _, ok := cfg.MatchMetaIssuer("https://oidc.eks.us.west.2.amazonaws.com/id/B02C93B6A2D30341AD01E1B6D48164CB"); ok {
+		t.Error("expected meta issuer not to match")
+	}
+}
+
+func TestIssuerPolicy(t *testing.T) {
+	cfg, err := Read([]byte(`{
+		"MetaIssuers": {
+			"https://oidc.eks.*.amazonaws.com/id/*": {
+				"ClientID": "sigstore",
+				"Type": "kubernetes",
+				"IssuancePolicy": [{"Name": "sigstore", "Expression": "subject == 'sigstore'"}]
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Read() = %v", err)
+	}
+
+	issuerURL := "https://oidc.eks.us-west-2.amazonaws.com/id/B02C93B6A2D30341AD01E1B6D48164CB"
+	p, err := cfg.IssuerPolicy(issuerURL)
+	if err != nil {
+		t.Fatalf("IssuerPolicy() = %v", err)
+	}
+	// Policies are compiled once, when the config is loaded
+	if again, _ := cfg.IssuerPolicy(issuerURL); again != p {
+		t.Error("expected the compiled policy to be reused")
+	}
+	if err := p.Evaluate(policy.Input{Subject: "sigstore"}); err != nil {
+		t.Errorf("Evaluate() = %v", err)
+	}
+	if err := p.Evaluate(policy.Input{Subject: "other"}); err == nil {
+		t.Error("expected the policy to deny other subjects")
+	}
+}
+
 func TestValidateConfig(t *testing.T) {
 	tests := map[string]struct {
 		Config    *FulcioConfig
@@ -435,6 +495,117 @@ func TestValidateConfig(t *testing.T) {
 			Config:    nil,
 			WantError: true,
 		},
+		"valid certificate lifetimes": {
+			Config: &FulcioConfig{
+				OIDCIssuers: map[string]OIDCIssuer{
+					"https://issuer.example.com": {
+						IssuerURL:                  "https://issuer.example.com",
+						ClientID:                   "sigstore",
+						Type:                       IssuerTypeEmail,
+						DefaultCertificateLifetime: "5m",
+						MaxCertificateLifetime:     "1h",
+						NotBeforeSkew:              "30s",
+					},
+				},
+			},
+			WantError: false,
+		},
+		"valid issuance policy": {
+			Config: &FulcioConfig{
+				OIDCIssuers: map[string]OIDCIssuer{
+					"https://issuer.example.com": {
+						IssuerURL: "https://issuer.example.com",
+						ClientID:  "sigstore",
+						Type:      IssuerTypeEmail,
+						IssuancePolicy: []IssuancePolicyRule{
+							{Name: "domain", Expression: `subject.endsWith("@example.com")`},
+						},
+					},
+				},
+			},
+			WantError: false,
+		},
+		"invalid issuance policy expression": {
+			Config: &FulcioConfig{
+				MetaIssuers: map[string]OIDCIssuer{
+					"https://*.example.com": {
+						ClientID: "sigstore",
+						Type:     IssuerTypeEmail,
+						IssuancePolicy: []IssuancePolicyRule{
+							{Name: "domain", Expression: `subject.endsWith(`},
+						},
+					},
+				},
+			},
+			WantError: true,
+		},
+		"unnamed issuance policy rule": {
+			Config: &FulcioConfig{
+				OIDCIssuers: map[string]OIDCIssuer{
+					"https://issuer.example.com": {
+						IssuerURL: "https://issuer.example.com",
+						ClientID:  "sigstore",
+						Type:      IssuerTypeEmail,
+						IssuancePolicy: []IssuancePolicyRule{
+							{Expression: `true`},
+						},
+					},
+				},
+			},
+			WantError: true,
+		},
+		"unparseable certificate lifetime": {
+			Config: &FulcioConfig{
+				OIDCIssuers: map[string]OIDCIssuer{
+					"https://issuer.example.com": {
+						IssuerURL:              "https://issuer.example.com",
+						ClientID:               "sigstore",
+						Type:                   IssuerTypeEmail,
+						MaxCertificateLifetime: "forever",
+					},
+				},
+			},
+			WantError: true,
+		},
+		"max certificate lifetime below the implicit default": {
+			Config: &FulcioConfig{
+				OIDCIssuers: map[string]OIDCIssuer{
+					"https://issuer.example.com": {
+						IssuerURL:              "https://issuer.example.com",
+						ClientID:               "sigstore",
+						Type:                   IssuerTypeEmail,
+						MaxCertificateLifetime: "5m",
+					},
+				},
+			},
+			WantError: false,
+		},
+		"default certificate lifetime above max": {
+			Config: &FulcioConfig{
+				MetaIssuers: map[string]OIDCIssuer{
+					"https://*.example.com": {
+						ClientID:                   "sigstore",
+						Type:                       IssuerTypeEmail,
+						DefaultCertificateLifetime: "1h",
+						MaxCertificateLifetime:     "30m",
+					},
+				},
+			},
+			WantError: true,
+		},
+		"negative not before skew": {
+			Config: &FulcioConfig{
+				OIDCIssuers: map[string]OIDCIssuer{
+					"https://issuer.example.com": {
+						IssuerURL:     "https://issuer.example.com",
+						ClientID:      "sigstore",
+						Type:          IssuerTypeEmail,
+						NotBeforeSkew: "-1m",
+					},
+				},
+			},
+			WantError: true,
+		},
 	}
 
 	for name, test := range tests {
@@ -620,6 +791,91 @@ oidc-issuers:
 	}
 }
 
+func TestCertificateLifetime(t *testing.T) {
+	tests := map[string]struct {
+		Issuer    OIDCIssuer
+		Requested time.Duration
+		Want      time.Duration
+	}{
+		"unset uses ten minutes": {
+			Issuer: OIDCIssuer{},
+			Want:   10 * time.Minute,
+		},
+		"requests above the default are clamped without a max": {
+			Issuer:    OIDCIssuer{},
+			Requested: time.Hour,
+			Want:      10 * time.Minute,
+		},
+		"issuer default": {
+			Issuer: OIDCIssuer{DefaultCertificateLifetime: "5m"},
+			Want:   5 * time.Minute,
+		},
+		"requested below max": {
+			Issuer:    OIDCIssuer{MaxCertificateLifetime: "1h"},
+			Requested: 20 * time.Minute,
+			Want:      20 * time.Minute,
+		},
+		"requested above max": {
+			Issuer:    OIDCIssuer{MaxCertificateLifetime: "1h"},
+			Requested: 2 * time.Hour,
+			Want:      time.Hour,
+		},
+		"max below the implicit default": {
+			Issuer: OIDCIssuer{MaxCertificateLifetime: "5m"},
+			Want:   5 * time.Minute,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.Issuer.CertificateLifetime(test.Requested); got != test.Want {
+				t.Errorf("expected %v, got %v", test.Want, got)
+			}
+		})
+	}
+}
+
+func TestCertificateLifetimeMetaIssuer(t *testing.T) {
+	cfg := &FulcioConfig{
+		MetaIssuers: map[string]OIDCIssuer{
+			"https://oidc.eks.*.amazonaws.com/id/*": {
+				ClientID:               "sigstore",
+				Type:                   IssuerTypeKubernetes,
+				MaxCertificateLifetime: "20m",
+				NotBeforeSkew:          "1m",
+			},
+		},
+	}
+	iss, ok := cfg.GetIssuer("https://oidc.eks.us-west-2.amazonaws.com/id/B02C93B6A2D30341AD01E1B6D48164CB")
+	if !ok {
+		t.Fatal("expected meta issuer to match")
+	}
+	if got := iss.CertificateLifetime(time.Hour); got != 20*time.Minute {
+		t.Errorf("expected lifetime clamped to 20m, got %v", got)
+	}
+	if got := iss.CertificateNotBeforeSkew(); got != time.Minute {
+		t.Errorf("expected 1m skew, got %v", got)
+	}
+}
+
+func TestCAMetaIssuer(t *testing.T) {
+	cfg := &FulcioConfig{
+		MetaIssuers: map[string]OIDCIssuer{
+			"https://oidc.eks.*.amazonaws.com/id/*": {
+				ClientID: "sigstore",
+				Type:     IssuerTypeKubernetes,
+				CA:       "eks",
+			},
+		},
+	}
+	iss, ok := cfg.GetIssuer("https://oidc.eks.us-west-2.amazonaws.com/id/B02C93B6A2D30341AD01E1B6D48164CB")
+	if !ok {
+		t.Fatal("expected meta issuer to match")
+	}
+	if iss.CA != "eks" {
+		t.Errorf("expected CA backend eks, got %q", iss.CA)
+	}
+}
+
 func Test_issuerToChallengeClaim(t *testing.T) {
 	if claim := issuerToChallengeClaim(IssuerTypeEmail, ""); claim != "email" {
 		t.Fatalf("expected email subject claim for email issuer, got %s", claim)
@@ -750,6 +1006,29 @@ func TestToIssuers(t *testing.T) {
 				},
 			},
 		},
+		{
+			config: &FulcioConfig{
+				OIDCIssuers: map[string]OIDCIssuer{
+					"challenge.example.com": {
+						IssuerURL:        "challenge.example.com",
+						ClientID:         "sigstore",
+						Type:             IssuerTypeEmail,
+						RequireChallenge: true,
+					},
+				},
+			},
+			want: []*protobuf.OIDCIssuer{
+				{
+					Audience:       "sigstore",
+					ChallengeClaim: "email",
+					Issuer: &protobuf.OIDCIssuer_IssuerUrl{
+						IssuerUrl: "challenge.example.com",
+					},
+					IssuerType:       IssuerTypeEmail,
+					RequireChallenge: true,
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,282 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ctl
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/tls"
+	ctx509 "github.com/google/certificate-transparency-go/x509"
+	"github.com/transparency-dev/merkle/compact"
+	"github.com/transparency-dev/merkle/rfc6962"
+)
+
+// maxRequestSize limits the submissions read by MemoryLog.Handler.
+const maxRequestSize = 1 << 20
+
+// MemoryLogEntry is a certificate or precertificate logged by a MemoryLog.
+type MemoryLogEntry struct {
+	// Index is the position of the entry in the log.
+	Index uint64
+	// Leaf is the Merkle tree leaf of the entry.
+	Leaf ct.MerkleTreeLeaf
+	// Chain is the submitted chain, leaf first.
+	Chain []ct.ASN1Cert
+	// SCT is the SCT the log returned for the entry.
+	SCT *ct.SignedCertificateTimestamp
+}
+
+// MemoryLog is a CT log that keeps its entries in memory, for tests and
+// development. It returns SCTs signed with its key for any chain, without
+// checking that the chain ends in a trusted root, and incorporates entries
+// into its tree immediately. Its Handler serves the RFC 6962 submission and
+// tree head endpoints.
+type MemoryLog struct {
+	signer crypto.Signer
+	sigAlg tls.SignatureAlgorithm
+	logID  [sha256.Size]byte
+
+	mu      sync.Mutex
+	entries []MemoryLogEntry
+	tree    *compact.Range
+}
+
+var _ Log = (*MemoryLog)(nil)
+
+// NewMemoryLog returns an empty log that signs with signer, which must hold
+// an ECDSA or RSA key.
+func NewMemoryLog(signer crypto.Signer) (*MemoryLog, error) {
+	var sigAlg tls.SignatureAlgorithm
+	switch signer.Public().(type) {
+	case *ecdsa.PublicKey:
+		sigAlg = tls.ECDSA
+	case *rsa.PublicKey:
+		sigAlg = tls.RSA
+	default:
+		return nil, fmt.Errorf("unsupported CT log key type %T", signer.Public())
+	}
+	der, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return nil, fmt.Errorf("marshaling CT log public key: %w", err)
+	}
+	rf := &compact.RangeFactory{Hash: rfc6962.DefaultHasher.HashChildren}
+	return &MemoryLog{
+		signer: signer,
+		sigAlg: sigAlg,
+		logID:  sha256.Sum256(der),
+		tree:   rf.NewEmptyRange(0),
+	}, nil
+}
+
+// PublicKey returns the key that SCTs and tree heads are signed with.
+func (l *MemoryLog) PublicKey() crypto.PublicKey {
+	return l.signer.Public()
+}
+
+// LogID returns the ID of the log, the SHA-256 hash of its public key.
+func (l *MemoryLog) LogID() [sha256.Size]byte {
+	return l.logID
+}
+
+// AddChain logs a certificate chain.
+func (l *MemoryLog) AddChain(_ context.Context, chain []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	return l.add(ct.X509LogEntryType, chain)
+}
+
+// AddPreChain logs a precertificate chain.
+func (l *MemoryLog) AddPreChain(_ context.Context, chain []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	return l.add(ct.PrecertLogEntryType, chain)
+}
+
+// Ping implements the health check of the log, which is always reachable.
+func (l *MemoryLog) Ping(context.Context) error {
+	return nil
+}
+
+func (l *MemoryLog) add(etype ct.LogEntryType, chain []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	if len(chain) == 0 {
+		return nil, errors.New("empty chain")
+	}
+	certs := make([]*ctx509.Certificate, 0, len(chain))
+	for _, c := range chain {
+		cert, err := ctx509.ParseCertificate(c.Data)
+		if ctx509.IsFatal(err) {
+			return nil, fmt.Errorf("parsing submitted certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	switch isPrecert := certs[0].IsPrecertificate(); {
+	case isPrecert && etype != ct.PrecertLogEntryType:
+		return nil, errors.New("submitted certificate is a precertificate")
+	case !isPrecert && etype == ct.PrecertLogEntryType:
+		return nil, errors.New("submitted certificate is not a precertificate")
+	}
+
+	sct := &ct.SignedCertificateTimestamp{
+		SCTVersion: ct.V1,
+		LogID:      ct.LogID{KeyID: l.logID},
+		Timestamp:  uint64(time.Now().UnixMilli()),
+	}
+	leaf, err := ct.MerkleTreeLeafFromChain(certs, etype, sct.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ct.SerializeSCTSignatureInput(*sct, ct.LogEntry{Leaf: *leaf})
+	if err != nil {
+		return nil, err
+	}
+	if sct.Signature, err = l.sign(data); err != nil {
+		return nil, err
+	}
+	hash, err := ct.LeafHashForLeaf(leaf)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.tree.Append(hash[:], nil); err != nil {
+		return nil, err
+	}
+	l.entries = append(l.entries, MemoryLogEntry{
+		Index: uint64(len(l.entries)),
+		Leaf:  *leaf,
+		Chain: chain,
+		SCT:   sct,
+	})
+	return sct, nil
+}
+
+// Entries returns the entries of the log, in the order they were logged.
+func (l *MemoryLog) Entries() []MemoryLogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]MemoryLogEntry(nil), l.entries...)
+}
+
+// GetSTH returns a tree head for all entries logged so far.
+func (l *MemoryLog) GetSTH(context.Context) (*ct.SignedTreeHead, error) {
+	l.mu.Lock()
+	size := l.tree.End()
+	root, err := l.tree.GetRootHash(nil)
+	l.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		root = rfc6962.DefaultHasher.EmptyRoot()
+	}
+	sth := &ct.SignedTreeHead{
+		Version:   ct.V1,
+		TreeSize:  size,
+		Timestamp: uint64(time.Now().UnixMilli()),
+		LogID:     l.logID,
+	}
+	copy(sth.SHA256RootHash[:], root)
+	data, err := ct.SerializeSTHSignatureInput(*sth)
+	if err != nil {
+		return nil, err
+	}
+	if sth.TreeHeadSignature, err = l.sign(data); err != nil {
+		return nil, err
+	}
+	return sth, nil
+}
+
+func (l *MemoryLog) sign(data []byte) (ct.DigitallySigned, error) {
+	digest := sha256.Sum256(data)
+	sig, err := l.signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return ct.DigitallySigned{}, fmt.Errorf("signing: %w", err)
+	}
+	return ct.DigitallySigned{
+		Algorithm: tls.SignatureAndHashAlgorithm{Hash: tls.SHA256, Signature: l.sigAlg},
+		Signature: sig,
+	}, nil
+}
+
+// Handler returns a handler serving the add-chain, add-pre-chain and get-sth
+// endpoints of the RFC 6962 API for the log, under /ct/v1/.
+func (l *MemoryLog) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+ct.AddChainPath, func(w http.ResponseWriter, r *http.Request) {
+		l.serveAdd(w, r, l.AddChain)
+	})
+	mux.HandleFunc("POST "+ct.AddPreChainPath, func(w http.ResponseWriter, r *http.Request) {
+		l.serveAdd(w, r, l.AddPreChain)
+	})
+	mux.HandleFunc("GET "+ct.GetSTHPath, func(w http.ResponseWriter, r *http.Request) {
+		sth, err := l.GetSTH(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		sig, err := tls.Marshal(sth.TreeHeadSignature)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, ct.GetSTHResponse{
+			TreeSize:          sth.TreeSize,
+			Timestamp:         sth.Timestamp,
+			SHA256RootHash:    sth.SHA256RootHash[:],
+			TreeHeadSignature: sig,
+		})
+	})
+	return mux
+}
+
+func (l *MemoryLog) serveAdd(w http.ResponseWriter, r *http.Request, add func(context.Context, []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error)) {
+	var req ct.AddChainRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestSize)).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("decoding request: %v", err), http.StatusBadRequest)
+		return
+	}
+	chain := make([]ct.ASN1Cert, 0, len(req.Chain))
+	for _, c := range req.Chain {
+		chain = append(chain, ct.ASN1Cert{Data: c})
+	}
+	sct, err := add(r.Context(), chain)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := ToAddChainResponse(sct)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, resp)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
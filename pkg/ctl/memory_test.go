@@ -0,0 +1,143 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/client"
+	"github.com/google/certificate-transparency-go/jsonclient"
+	ctx509 "github.com/google/certificate-transparency-go/x509"
+	"github.com/transparency-dev/merkle/rfc6962"
+)
+
+func TestMemoryLog(t *testing.T) {
+	cert, precert := testChain(t)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, key := range map[string]crypto.Signer{"ecdsa": ecKey, "rsa": rsaKey} {
+		t.Run(name, func(t *testing.T) {
+			l, err := NewMemoryLog(key)
+			if err != nil {
+				t.Fatalf("NewMemoryLog() = %v", err)
+			}
+			srv := httptest.NewServer(l.Handler())
+			t.Cleanup(srv.Close)
+			// Submit through the RFC 6962 client that Fulcio uses
+			c, err := client.New(srv.URL, http.DefaultClient, jsonclient.Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			verifier, err := ct.NewSignatureVerifier(l.PublicKey())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sth, err := c.GetSTH(context.Background())
+			if err != nil {
+				t.Fatalf("GetSTH() = %v", err)
+			}
+			if sth.TreeSize != 0 || string(sth.SHA256RootHash[:]) != string(rfc6962.DefaultHasher.EmptyRoot()) {
+				t.Errorf("unexpected empty tree head %+v", sth)
+			}
+
+			for i, submit := range []struct {
+				add   func(context.Context, []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error)
+				chain []ct.ASN1Cert
+				etype ct.LogEntryType
+			}{
+				{c.AddChain, cert, ct.X509LogEntryType},
+				{c.AddPreChain, precert, ct.PrecertLogEntryType},
+			} {
+				sct, err := submit.add(context.Background(), submit.chain)
+				if err != nil {
+					t.Fatalf("submission %d = %v", i, err)
+				}
+				var certs []*ctx509.Certificate
+				for _, c := range submit.chain {
+					parsed, err := ctx509.ParseCertificate(c.Data)
+					if ctx509.IsFatal(err) {
+						t.Fatal(err)
+					}
+					certs = append(certs, parsed)
+				}
+				leaf, err := ct.MerkleTreeLeafFromChain(certs, submit.etype, sct.Timestamp)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := verifier.VerifySCTSignature(*sct, ct.LogEntry{Leaf: *leaf}); err != nil {
+					t.Errorf("VerifySCTSignature() = %v", err)
+				}
+				if sct.LogID.KeyID != l.LogID() {
+					t.Errorf("unexpected log ID %x", sct.LogID.KeyID)
+				}
+			}
+
+			entries := l.Entries()
+			if len(entries) != 2 || entries[1].Index != 1 || entries[1].Leaf.TimestampedEntry.EntryType != ct.PrecertLogEntryType {
+				t.Errorf("unexpected entries %+v", entries)
+			}
+			sth, err = c.GetSTH(context.Background())
+			if err != nil {
+				t.Fatalf("GetSTH() = %v", err)
+			}
+			if err := verifier.VerifySTHSignature(*sth); err != nil {
+				t.Errorf("VerifySTHSignature() = %v", err)
+			}
+			h0, _ := ct.LeafHashForLeaf(&entries[0].Leaf)
+			h1, _ := ct.LeafHashForLeaf(&entries[1].Leaf)
+			root := rfc6962.DefaultHasher.HashChildren(h0[:], h1[:])
+			if sth.TreeSize != 2 || string(sth.SHA256RootHash[:]) != string(root) {
+				t.Errorf("unexpected tree head %+v", sth)
+			}
+
+			// Entries must be submitted to the endpoint of their type
+			if _, err := c.AddChain(context.Background(), precert); err == nil {
+				t.Error("expected a precertificate to be rejected by add-chain")
+			}
+			if _, err := c.AddPreChain(context.Background(), cert); err == nil {
+				t.Error("expected a certificate to be rejected by add-pre-chain")
+			}
+			if _, err := l.AddChain(context.Background(), nil); err == nil {
+				t.Error("expected an empty chain to be rejected")
+			}
+		})
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewMemoryLog(key); err == nil {
+		t.Error("expected an Ed25519 key to be rejected")
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/sigstore/fulcio/pkg/log"
+)
+
+// DefaultDeadline is how long MultiLog waits for SCTs beyond its quorum.
+const DefaultDeadline = 5 * time.Second
+
+// Log is a CT log that certificates can be submitted to. It is implemented
+// by *client.LogClient.
+type Log interface {
+	AddChain(ctx context.Context, chain []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error)
+	AddPreChain(ctx context.Context, chain []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error)
+}
+
+// MultiLog submits certificates to several CT logs concurrently. A
+// submission succeeds once a quorum of logs has returned an SCT. The logs
+// that have not responded by the deadline are then abandoned, so that one
+// slow log does not delay issuance.
+type MultiLog struct {
+	logs     []Log
+	quorum   int
+	deadline time.Duration
+}
+
+// MultiLogOption configures the MultiLog returned by NewMultiLog.
+type MultiLogOption func(*MultiLog)
+
+// WithQuorum sets the number of SCTs a submission requires. Defaults to 1.
+func WithQuorum(n int) MultiLogOption {
+	return func(m *MultiLog) {
+		m.quorum = n
+	}
+}
+
+// WithDeadline sets how long after submission to wait for SCTs beyond the
+// quorum. Defaults to DefaultDeadline.
+func WithDeadline(d time.Duration) MultiLogOption {
+	return func(m *MultiLog) {
+		m.deadline = d
+	}
+}
+
+// NewMultiLog returns a MultiLog that submits to logs.
+func NewMultiLog(logs []Log, opts ...MultiLogOption) (*MultiLog, error) {
+	if len(logs) == 0 {
+		return nil, errors.New("at least one CT log is required")
+	}
+	m := &MultiLog{
+		logs:     logs,
+		quorum:   1,
+		deadline: DefaultDeadline,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.quorum < 1 || m.quorum > len(logs) {
+		return nil, fmt.Errorf("CT log quorum must be between 1 and the number of logs (%d), got %d", len(logs), m.quorum)
+	}
+	if m.deadline < 0 {
+		return nil, fmt.Errorf("CT log deadline must not be negative, got %v", m.deadline)
+	}
+	return m, nil
+}
+
+// AddChain submits a certificate chain to the logs, and returns the SCTs in
+// the order the logs were configured in.
+func (m *MultiLog) AddChain(ctx context.Context, chain []ct.ASN1Cert) ([]*ct.SignedCertificateTimestamp, error) {
+	return m.submit(ctx, func(ctx context.Context, l Log) (*ct.SignedCertificateTimestamp, error) {
+		return l.AddChain(ctx, chain)
+	})
+}
+
+// AddPreChain submits a precertificate chain to the logs, and returns the
+// SCTs in the order the logs were configured in.
+func (m *MultiLog) AddPreChain(ctx context.Context, chain []ct.ASN1Cert) ([]*ct.SignedCertificateTimestamp, error) {
+	return m.submit(ctx, func(ctx context.Context, l Log) (*ct.SignedCertificateTimestamp, error) {
+		return l.AddPreChain(ctx, chain)
+	})
+}
+
+func (m *MultiLog) submit(ctx context.Context, add func(context.Context, Log) (*ct.SignedCertificateTimestamp, error)) ([]*ct.SignedCertificateTimestamp, error) {
+	// Abandoned submissions are canceled on return
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		i   int
+		sct *ct.SignedCertificateTimestamp
+		err error
+	}
+	results := make(chan result, len(m.logs))
+	for i, l := range m.logs {
+		go func() {
+			sct, err := add(ctx, l)
+			results <- result{i: i, sct: sct, err: err}
+		}()
+	}
+
+	deadline := time.NewTimer(m.deadline)
+	defer deadline.Stop()
+	expired := false
+
+	scts := make([]*ct.SignedCertificateTimestamp, len(m.logs))
+	var errs []error
+	received := 0
+	for received+len(errs) < len(m.logs) && (received < m.quorum || !expired) {
+		select {
+		case r := <-results:
+			if r.err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", logName(m.logs[r.i], r.i), r.err))
+				if len(m.logs)-len(errs) < m.quorum {
+					return nil, fmt.Errorf("fewer than %d of %d CT logs returned an SCT: %w", m.quorum, len(m.logs), errors.Join(errs...))
+				}
+				continue
+			}
+			scts[r.i] = r.sct
+			received++
+		case <-deadline.C:
+			expired = true
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if received < len(m.logs) {
+		log.ContextLogger(ctx).Warnf("continuing with SCTs from %d of %d CT logs, errors: %v", received, len(m.logs), errors.Join(errs...))
+	}
+	list := make([]*ct.SignedCertificateTimestamp, 0, received)
+	for _, sct := range scts {
+		if sct != nil {
+			list = append(list, sct)
+		}
+	}
+	return list, nil
+}
+
+// Ping checks that a quorum of the logs is reachable, without submitting
+// to them. Logs that cannot be checked without submitting are assumed to be
+// reachable.
+func (m *MultiLog) Ping(ctx context.Context) error {
+	errs := make([]error, len(m.logs))
+	var wg sync.WaitGroup
+	for i, l := range m.logs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := Ping(ctx, l); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", logName(l, i), err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var failed []error
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	if len(m.logs)-len(failed) < m.quorum {
+		return fmt.Errorf("fewer than %d of %d CT logs are reachable: %w", m.quorum, len(m.logs), errors.Join(failed...))
+	}
+	return nil
+}
+
+// Ping checks that l is reachable by fetching its latest tree head. It
+// returns nil for logs that cannot be checked without submitting to them.
+func Ping(ctx context.Context, l Log) error {
+	switch l := l.(type) {
+	case interface{ Ping(context.Context) error }:
+		return l.Ping(ctx)
+	case interface {
+		GetSTH(context.Context) (*ct.SignedTreeHead, error)
+	}:
+		_, err := l.GetSTH(ctx)
+		return err
+	}
+	return nil
+}
+
+// logName identifies a log in errors.
+func logName(l Log, i int) string {
+	if u, ok := l.(interface{ BaseURI() string }); ok {
+		return u.BaseURI()
+	}
+	return fmt.Sprintf("CT log %d", i)
+}
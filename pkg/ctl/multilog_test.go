@@ -0,0 +1,209 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+)
+
+// fakeLog returns an SCT with its timestamp after a delay, or fails.
+type fakeLog struct {
+	timestamp uint64
+	delay     time.Duration
+	err       error
+}
+
+func (f *fakeLog) add(ctx context.Context) (*ct.SignedCertificateTimestamp, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(f.delay):
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &ct.SignedCertificateTimestamp{Timestamp: f.timestamp}, nil
+}
+
+func (f *fakeLog) AddChain(ctx context.Context, _ []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	return f.add(ctx)
+}
+
+func (f *fakeLog) AddPreChain(ctx context.Context, _ []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	return f.add(ctx)
+}
+
+func TestNewMultiLog(t *testing.T) {
+	logs := []Log{&fakeLog{}, &fakeLog{}}
+	tests := map[string]struct {
+		logs    []Log
+		opts    []MultiLogOption
+		wantErr bool
+	}{
+		"defaults":           {logs: logs},
+		"quorum of all logs": {logs: logs, opts: []MultiLogOption{WithQuorum(2)}},
+		"no logs":            {wantErr: true},
+		"zero quorum":        {logs: logs, opts: []MultiLogOption{WithQuorum(0)}, wantErr: true},
+		"quorum beyond logs": {logs: logs, opts: []MultiLogOption{WithQuorum(3)}, wantErr: true},
+		"negative deadline":  {logs: logs, opts: []MultiLogOption{WithDeadline(-time.Second)}, wantErr: true},
+		"zero deadline":      {logs: logs, opts: []MultiLogOption{WithDeadline(0)}},
+		"single log":         {logs: logs[:1]},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewMultiLog(test.logs, test.opts...)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("NewMultiLog() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestMultiLogSubmit(t *testing.T) {
+	failure := errors.New("log unavailable")
+	tests := map[string]struct {
+		logs    []Log
+		quorum  int
+		want    []uint64
+		wantErr bool
+	}{
+		"all logs respond, in log order": {
+			logs:   []Log{&fakeLog{timestamp: 1, delay: 20 * time.Millisecond}, &fakeLog{timestamp: 2}, &fakeLog{timestamp: 3}},
+			quorum: 1,
+			want:   []uint64{1, 2, 3},
+		},
+		"failed log is skipped": {
+			logs:   []Log{&fakeLog{timestamp: 1}, &fakeLog{err: failure}, &fakeLog{timestamp: 3}},
+			quorum: 2,
+			want:   []uint64{1, 3},
+		},
+		"quorum not met": {
+			logs:    []Log{&fakeLog{timestamp: 1}, &fakeLog{err: failure}, &fakeLog{err: failure}},
+			quorum:  2,
+			wantErr: true,
+		},
+		"slow log is abandoned after the deadline": {
+			logs:   []Log{&fakeLog{timestamp: 1}, &fakeLog{timestamp: 2, delay: time.Minute}},
+			quorum: 1,
+			want:   []uint64{1},
+		},
+		"quorum waits past the deadline": {
+			logs:   []Log{&fakeLog{timestamp: 1}, &fakeLog{timestamp: 2, delay: 200 * time.Millisecond}},
+			quorum: 2,
+			want:   []uint64{1, 2},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m, err := NewMultiLog(test.logs, WithQuorum(test.quorum), WithDeadline(100*time.Millisecond))
+			if err != nil {
+				t.Fatalf("NewMultiLog() = %v", err)
+			}
+			for op, submit := range map[string]func(context.Context, []ct.ASN1Cert) ([]*ct.SignedCertificateTimestamp, error){
+				"AddChain":    m.AddChain,
+				"AddPreChain": m.AddPreChain,
+			} {
+				scts, err := submit(context.Background(), nil)
+				if test.wantErr {
+					if err == nil {
+						t.Fatalf("%s: expected error", op)
+					}
+					if !errors.Is(err, failure) {
+						t.Errorf("%s: expected log error to be wrapped, got %v", op, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("%s() = %v", op, err)
+				}
+				var got []uint64
+				for _, sct := range scts {
+					got = append(got, sct.Timestamp)
+				}
+				if len(got) != len(test.want) {
+					t.Fatalf("%s: expected SCTs %v, got %v", op, test.want, got)
+				}
+				for i := range got {
+					if got[i] != test.want[i] {
+						t.Fatalf("%s: expected SCTs %v, got %v", op, test.want, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMultiLogCanceled(t *testing.T) {
+	m, err := NewMultiLog([]Log{&fakeLog{delay: time.Minute}})
+	if err != nil {
+		t.Fatalf("NewMultiLog() = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := m.AddPreChain(ctx, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+// pingLog is a fakeLog that can be pinged.
+type pingLog struct {
+	fakeLog
+	pingErr error
+}
+
+func (p *pingLog) Ping(context.Context) error {
+	return p.pingErr
+}
+
+func TestMultiLogPing(t *testing.T) {
+	down := &pingLog{pingErr: errors.New("connection refused")}
+	up := &pingLog{}
+	tests := map[string]struct {
+		logs    []Log
+		quorum  int
+		wantErr bool
+	}{
+		"all reachable":      {logs: []Log{up, up}, quorum: 2},
+		"quorum reachable":   {logs: []Log{up, down}, quorum: 1},
+		"quorum unreachable": {logs: []Log{up, down}, quorum: 2, wantErr: true},
+		"cannot be pinged":   {logs: []Log{&fakeLog{err: errors.New("unused")}}, quorum: 1},
+		"wrapped":            {logs: []Log{mustResilientLog(t, down)}, quorum: 1, wantErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m, err := NewMultiLog(test.logs, WithQuorum(test.quorum))
+			if err != nil {
+				t.Fatalf("NewMultiLog() = %v", err)
+			}
+			if err := m.Ping(context.Background()); (err != nil) != test.wantErr {
+				t.Errorf("Ping() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func mustResilientLog(t *testing.T, l Log) *ResilientLog {
+	t.Helper()
+	r, err := NewResilientLog(l)
+	if err != nil {
+		t.Fatalf("NewResilientLog() = %v", err)
+	}
+	return r
+}
@@ -0,0 +1,234 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ctl
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sigstore/fulcio/pkg/log"
+)
+
+// DefaultQueueInterval is how often queued certificates are submitted.
+const DefaultQueueInterval = time.Minute
+
+// queueFileSuffix is the suffix of queued entries. Entries are written to a
+// temporary file first, so that a crash never leaves a partial entry.
+const queueFileSuffix = ".json"
+
+var (
+	metricQueueLength = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "fulcio_ct_queue_length",
+		Help: "The number of certificates waiting to be submitted to CT logs",
+	})
+
+	metricQueueSubmitted = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "fulcio_ct_queue_submitted_total",
+		Help: "The total number of queued certificates that were submitted to CT logs",
+	})
+)
+
+// queueEntry is a certificate chain waiting to be submitted.
+type queueEntry struct {
+	Chain  [][]byte  `json:"chain"`
+	Queued time.Time `json:"queued"`
+}
+
+// Queue holds certificate chains that could not be submitted to CT logs,
+// and submits them in the background until the logs accept them. Entries
+// are stored as files in a directory, so that they survive restarts.
+type Queue struct {
+	dir      string
+	logs     *MultiLog
+	interval time.Duration
+
+	// mu serializes draining the queue
+	mu sync.Mutex
+}
+
+// QueueOption configures the Queue returned by NewQueue.
+type QueueOption func(*Queue)
+
+// WithQueueInterval sets how often queued certificates are submitted.
+// Defaults to DefaultQueueInterval.
+func WithQueueInterval(d time.Duration) QueueOption {
+	return func(q *Queue) {
+		q.interval = d
+	}
+}
+
+// NewQueue returns a Queue that stores entries in dir, creating it if
+// needed, and submits them to logs.
+func NewQueue(dir string, logs *MultiLog, opts ...QueueOption) (*Queue, error) {
+	if logs == nil {
+		return nil, errors.New("a CT log is required to queue certificates")
+	}
+	q := &Queue{
+		dir:      dir,
+		logs:     logs,
+		interval: DefaultQueueInterval,
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	if q.interval <= 0 {
+		return nil, fmt.Errorf("CT queue interval must be positive, got %v", q.interval)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating CT queue directory: %w", err)
+	}
+	if _, err := q.entries(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Enqueue stores a certificate chain for submission with AddChain.
+func (q *Queue) Enqueue(chain []ct.ASN1Cert) error {
+	entry := queueEntry{Queued: time.Now().UTC()}
+	for _, c := range chain {
+		entry.Chain = append(entry.Chain, c.Data)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	// Names sort in the order entries were queued
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%020d-%s", entry.Queued.UnixNano(), hex.EncodeToString(suffix))
+
+	tmp, err := os.CreateTemp(q.dir, ".tmp-"+name)
+	if err != nil {
+		return fmt.Errorf("queueing certificate: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("queueing certificate: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("queueing certificate: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("queueing certificate: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(q.dir, name+queueFileSuffix)); err != nil {
+		return fmt.Errorf("queueing certificate: %w", err)
+	}
+	metricQueueLength.Inc()
+	return nil
+}
+
+// Len returns the number of queued certificates.
+func (q *Queue) Len() (int, error) {
+	names, err := q.entries()
+	return len(names), err
+}
+
+// Run submits queued certificates every interval until ctx is done.
+func (q *Queue) Run(ctx context.Context) {
+	ticker := time.NewTicker(q.interval)
+	defer ticker.Stop()
+	for {
+		if err := q.Drain(ctx); err != nil && ctx.Err() == nil {
+			log.Logger.Warnf("CT logs still unavailable, will retry queued certificates in %v: %v", q.interval, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Drain submits queued certificates in the order they were queued, removing
+// each once it is accepted. It stops at the first failed submission, since
+// the logs are most likely still unavailable.
+func (q *Queue) Drain(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	names, err := q.entries()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		path := filepath.Join(q.dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var entry queueEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			// Retrying a corrupt entry will never succeed
+			log.Logger.Errorf("Discarding corrupt CT queue entry %s: %v", path, err)
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			metricQueueLength.Dec()
+			continue
+		}
+		chain := make([]ct.ASN1Cert, 0, len(entry.Chain))
+		for _, der := range entry.Chain {
+			chain = append(chain, ct.ASN1Cert{Data: der})
+		}
+		if _, err := q.logs.AddChain(ctx, chain); err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		metricQueueLength.Dec()
+		metricQueueSubmitted.Inc()
+		log.Logger.Infof("Submitted certificate queued at %v to CT logs", entry.Queued)
+	}
+	return nil
+}
+
+// entries returns the names of queued entries in the order they were queued,
+// and updates the queue length metric.
+func (q *Queue) entries() ([]string, error) {
+	files, err := os.ReadDir(q.dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading CT queue directory: %w", err)
+	}
+	var names []string
+	for _, f := range files {
+		if f.Type().IsRegular() && !strings.HasPrefix(f.Name(), ".") && strings.HasSuffix(f.Name(), queueFileSuffix) {
+			names = append(names, f.Name())
+		}
+	}
+	slices.Sort(names)
+	metricQueueLength.Set(float64(len(names)))
+	return names, nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+)
+
+func TestQueue(t *testing.T) {
+	dir := t.TempDir()
+	l := &flakyLog{failures: 1, err: errors.New("unavailable")}
+	logs, err := NewMultiLog([]Log{l})
+	if err != nil {
+		t.Fatalf("NewMultiLog() = %v", err)
+	}
+	q, err := NewQueue(dir, logs)
+	if err != nil {
+		t.Fatalf("NewQueue() = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := q.Enqueue([]ct.ASN1Cert{{Data: []byte{byte(i)}}}); err != nil {
+			t.Fatalf("Enqueue() = %v", err)
+		}
+	}
+	// A corrupt entry is discarded, and temporary files are ignored
+	if err := os.WriteFile(filepath.Join(dir, "00000000000000000000-corrupt.json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".tmp-partial.json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := q.Len(); err != nil || n != 4 {
+		t.Fatalf("Len() = %d, %v, want 4", n, err)
+	}
+
+	// The log is still unavailable, so draining stops at the first entry
+	if err := q.Drain(context.Background()); err == nil {
+		t.Fatal("expected error while the log is unavailable")
+	}
+	if n, _ := q.Len(); n != 3 {
+		t.Fatalf("expected 3 queued entries, got %d", n)
+	}
+
+	// Entries survive a restart
+	q, err = NewQueue(dir, logs)
+	if err != nil {
+		t.Fatalf("NewQueue() = %v", err)
+	}
+	if err := q.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain() = %v", err)
+	}
+	if n, _ := q.Len(); n != 0 {
+		t.Fatalf("expected empty queue, got %d entries", n)
+	}
+	if got := l.callCount(); got != 4 {
+		t.Errorf("expected 4 submissions, got %d", got)
+	}
+}
+
+func TestQueueRun(t *testing.T) {
+	logs, err := NewMultiLog([]Log{&flakyLog{}})
+	if err != nil {
+		t.Fatalf("NewMultiLog() = %v", err)
+	}
+	q, err := NewQueue(t.TempDir(), logs, WithQueueInterval(5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewQueue() = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		q.Run(ctx)
+		close(done)
+	}()
+	if err := q.Enqueue([]ct.ASN1Cert{{Data: []byte{1}}}); err != nil {
+		t.Fatalf("Enqueue() = %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for n, _ := q.Len(); n != 0; n, _ = q.Len() {
+		if time.Now().After(deadline) {
+			t.Fatal("queued certificate was not submitted")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+}
+
+func TestNewQueue(t *testing.T) {
+	logs, err := NewMultiLog([]Log{&flakyLog{}})
+	if err != nil {
+		t.Fatalf("NewMultiLog() = %v", err)
+	}
+	if _, err := NewQueue(t.TempDir(), nil); err == nil {
+		t.Error("expected error without CT logs")
+	}
+	if _, err := NewQueue(t.TempDir(), logs, WithQueueInterval(0)); err == nil {
+		t.Error("expected error for zero interval")
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/jsonclient"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sigstore/fulcio/pkg/backoff"
+	"github.com/sigstore/fulcio/pkg/log"
+	"github.com/sigstore/fulcio/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// DefaultRetries is the number of times a failed submission is retried.
+	DefaultRetries = 2
+	// DefaultInitialBackoff is the delay before the first retry.
+	DefaultInitialBackoff = 200 * time.Millisecond
+	// DefaultMaxBackoff caps the delay between retries.
+	DefaultMaxBackoff = 2 * time.Second
+	// DefaultAttemptTimeout bounds each submission attempt.
+	DefaultAttemptTimeout = 10 * time.Second
+	// DefaultCircuitBreakerCooldown is how long a log is skipped once its
+	// circuit breaker opens.
+	DefaultCircuitBreakerCooldown = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned without contacting the log while its circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("CT log circuit breaker is open")
+
+var (
+	metricSubmissionLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fulcio_ct_log_submission_duration_seconds",
+		Help:    "Latency of CT log submission attempts, by log, operation and result",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"log", "operation", "result"})
+
+	metricSubmissionRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulcio_ct_log_submission_retries_total",
+		Help: "The total number of CT log submission attempts that were retried",
+	}, []string{"log"})
+
+	metricLogHealthy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "fulcio_ct_log_healthy",
+		Help: "Whether a CT log is in use (1) or skipped by its circuit breaker after repeated failures (0)",
+	}, []string{"log"})
+)
+
+// ResilientLog wraps a Log, retrying failed submissions with jittered
+// exponential backoff and bounding each attempt with a timeout. With a
+// circuit breaker enabled, a log whose submissions fail repeatedly is skipped
+// for a cooldown period, after which submissions are let through again and
+// close the circuit if they succeed.
+type ResilientLog struct {
+	log            Log
+	name           string
+	backoff        backoff.Backoff
+	attemptTimeout time.Duration
+	// threshold is 0 if the circuit breaker is disabled
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+var _ Log = (*ResilientLog)(nil)
+
+// ResilientLogOption configures the ResilientLog returned by NewResilientLog.
+type ResilientLogOption func(*ResilientLog)
+
+// WithRetries sets the number of times a failed submission is retried.
+// Defaults to DefaultRetries.
+func WithRetries(n int) ResilientLogOption {
+	return func(r *ResilientLog) {
+		r.backoff.Retries = n
+	}
+}
+
+// WithBackoff sets the delay before the first retry, which doubles with each
+// retry up to maxBackoff. Each delay is randomly shortened by up to half to
+// spread out retries. Defaults to DefaultInitialBackoff and
+// DefaultMaxBackoff.
+func WithBackoff(initial, maxBackoff time.Duration) ResilientLogOption {
+	return func(r *ResilientLog) {
+		r.backoff.Initial = initial
+		r.backoff.Max = maxBackoff
+	}
+}
+
+// WithAttemptTimeout sets how long each submission attempt may take, or
+// disables the timeout if 0. Defaults to DefaultAttemptTimeout.
+func WithAttemptTimeout(d time.Duration) ResilientLogOption {
+	return func(r *ResilientLog) {
+		r.attemptTimeout = d
+	}
+}
+
+// WithCircuitBreaker skips the log for cooldown after threshold consecutive
+// failed submissions. The circuit breaker is disabled by default.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) ResilientLogOption {
+	return func(r *ResilientLog) {
+		r.threshold = threshold
+		r.cooldown = cooldown
+	}
+}
+
+// NewResilientLog returns a ResilientLog that submits to l.
+func NewResilientLog(l Log, opts ...ResilientLogOption) (*ResilientLog, error) {
+	r := &ResilientLog{
+		log:  l,
+		name: logName(l, 0),
+		backoff: backoff.Backoff{
+			Retries: DefaultRetries,
+			Initial: DefaultInitialBackoff,
+			Max:     DefaultMaxBackoff,
+		},
+		attemptTimeout: DefaultAttemptTimeout,
+		now:            time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if err := r.backoff.Validate("CT log"); err != nil {
+		return nil, err
+	}
+	if r.attemptTimeout < 0 {
+		return nil, fmt.Errorf("CT log attempt timeout must not be negative, got %v", r.attemptTimeout)
+	}
+	if r.threshold < 0 {
+		return nil, fmt.Errorf("CT log circuit breaker threshold must not be negative, got %d", r.threshold)
+	}
+	if r.threshold > 0 && r.cooldown <= 0 {
+		return nil, fmt.Errorf("CT log circuit breaker cooldown must be positive, got %v", r.cooldown)
+	}
+	metricLogHealthy.WithLabelValues(r.name).Set(1)
+	return r, nil
+}
+
+// BaseURI returns the URI of the wrapped log.
+func (r *ResilientLog) BaseURI() string {
+	return r.name
+}
+
+// AddChain submits a certificate chain to the log.
+func (r *ResilientLog) AddChain(ctx context.Context, chain []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	return r.submit(ctx, "add-chain", func(ctx context.Context) (*ct.SignedCertificateTimestamp, error) {
+		return r.log.AddChain(ctx, chain)
+	})
+}
+
+// AddPreChain submits a precertificate chain to the log.
+func (r *ResilientLog) AddPreChain(ctx context.Context, chain []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	return r.submit(ctx, "add-pre-chain", func(ctx context.Context) (*ct.SignedCertificateTimestamp, error) {
+		return r.log.AddPreChain(ctx, chain)
+	})
+}
+
+// Ping checks that the wrapped log is reachable, without retrying or
+// affecting the circuit breaker.
+func (r *ResilientLog) Ping(ctx context.Context) error {
+	return Ping(ctx, r.log)
+}
+
+func (r *ResilientLog) submit(ctx context.Context, operation string, add func(context.Context) (*ct.SignedCertificateTimestamp, error)) (*ct.SignedCertificateTimestamp, error) {
+	r.mu.Lock()
+	open := r.now().Before(r.openUntil)
+	r.mu.Unlock()
+	if open {
+		return nil, ErrCircuitOpen
+	}
+
+	var sct *ct.SignedCertificateTimestamp
+	err := r.backoff.Retry(ctx, func(attempt int) (err error) {
+		sct, err = r.attempt(ctx, operation, attempt, add)
+		return
+	}, func(err error) bool {
+		if !retriable(err) {
+			return false
+		}
+		metricSubmissionRetries.WithLabelValues(r.name).Inc()
+		log.ContextLogger(ctx).Warnf("CT log %s submission failed, retrying: %v", r.name, err)
+		return true
+	})
+	switch {
+	case err == nil:
+		r.succeeded()
+		return sct, nil
+	case ctx.Err() != nil:
+		// The caller gave up, which says nothing about the log
+		return nil, err
+	}
+	r.failed()
+	return nil, err
+}
+
+// attempt makes a single submission attempt, numbered from 0.
+func (r *ResilientLog) attempt(ctx context.Context, operation string, n int, add func(context.Context) (*ct.SignedCertificateTimestamp, error)) (*ct.SignedCertificateTimestamp, error) {
+	if r.attemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.attemptTimeout)
+		defer cancel()
+	}
+	ctx, span := tracing.Start(ctx, "ct."+operation,
+		attribute.String("ct.log", r.name),
+		attribute.Int("ct.attempt", n))
+	start := time.Now()
+	sct, err := add(ctx)
+	tracing.End(span, err)
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	metricSubmissionLatency.WithLabelValues(r.name, operation, result).Observe(time.Since(start).Seconds())
+	return sct, err
+}
+
+func (r *ResilientLog) succeeded() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures = 0
+	r.openUntil = time.Time{}
+	metricLogHealthy.WithLabelValues(r.name).Set(1)
+}
+
+func (r *ResilientLog) failed() {
+	if r.threshold == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures++
+	if r.failures >= r.threshold {
+		r.openUntil = r.now().Add(r.cooldown)
+		metricLogHealthy.WithLabelValues(r.name).Set(0)
+	}
+}
+
+// retriable reports whether a failed submission may succeed if retried.
+// Submissions that the log rejected as invalid are not retried.
+func retriable(err error) bool {
+	statusCode := 0
+	var rspErr jsonclient.RspError
+	var httpErr *httpError
+	switch {
+	case errors.As(err, &rspErr):
+		statusCode = rspErr.StatusCode
+	case errors.As(err, &httpErr):
+		statusCode = httpErr.StatusCode
+	default:
+		return true
+	}
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
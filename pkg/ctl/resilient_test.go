@@ -0,0 +1,192 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/jsonclient"
+)
+
+// flakyLog fails its first failures submissions with err.
+type flakyLog struct {
+	mu       sync.Mutex
+	calls    int
+	failures int
+	err      error
+	// hang blocks submissions until they are canceled
+	hang bool
+}
+
+func (f *flakyLog) add(ctx context.Context) (*ct.SignedCertificateTimestamp, error) {
+	f.mu.Lock()
+	f.calls++
+	fail := f.calls <= f.failures
+	f.mu.Unlock()
+	if f.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if fail {
+		return nil, f.err
+	}
+	return &ct.SignedCertificateTimestamp{Timestamp: 1}, nil
+}
+
+func (f *flakyLog) AddChain(ctx context.Context, _ []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	return f.add(ctx)
+}
+
+func (f *flakyLog) AddPreChain(ctx context.Context, _ []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	return f.add(ctx)
+}
+
+func (f *flakyLog) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func TestResilientLogRetries(t *testing.T) {
+	unavailable := jsonclient.RspError{StatusCode: http.StatusServiceUnavailable, Err: errors.New("unavailable")}
+	tests := map[string]struct {
+		log       *flakyLog
+		wantErr   bool
+		wantCalls int
+	}{
+		"succeeds first time": {
+			log:       &flakyLog{},
+			wantCalls: 1,
+		},
+		"succeeds after retries": {
+			log:       &flakyLog{failures: 2, err: unavailable},
+			wantCalls: 3,
+		},
+		"retries exhausted": {
+			log:       &flakyLog{failures: 3, err: unavailable},
+			wantErr:   true,
+			wantCalls: 3,
+		},
+		"network errors are retried": {
+			log:       &flakyLog{failures: 1, err: errors.New("connection refused")},
+			wantCalls: 2,
+		},
+		"rejected submissions are not retried": {
+			log:       &flakyLog{failures: 1, err: jsonclient.RspError{StatusCode: http.StatusBadRequest, Err: errors.New("bad chain")}},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		"static-ct errors are classified": {
+			log:       &flakyLog{failures: 1, err: &httpError{StatusCode: http.StatusTooManyRequests, Err: errors.New("slow down")}},
+			wantCalls: 2,
+		},
+		"timed out attempts are retried": {
+			log:       &flakyLog{hang: true},
+			wantErr:   true,
+			wantCalls: 3,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, err := NewResilientLog(test.log,
+				WithBackoff(time.Millisecond, 5*time.Millisecond),
+				WithAttemptTimeout(20*time.Millisecond))
+			if err != nil {
+				t.Fatalf("NewResilientLog() = %v", err)
+			}
+			_, err = r.AddPreChain(context.Background(), nil)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("AddPreChain() = %v, wantErr %v", err, test.wantErr)
+			}
+			if got := test.log.callCount(); got != test.wantCalls {
+				t.Errorf("expected %d attempts, got %d", test.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestResilientLogCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	l := &flakyLog{failures: 2, err: errors.New("unavailable")}
+	r, err := NewResilientLog(l, WithRetries(0), WithCircuitBreaker(2, time.Minute))
+	if err != nil {
+		t.Fatalf("NewResilientLog() = %v", err)
+	}
+	r.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if _, err := r.AddChain(context.Background(), nil); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+	// The circuit is open, so the log is not contacted
+	if _, err := r.AddChain(context.Background(), nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if got := l.callCount(); got != 2 {
+		t.Errorf("expected 2 attempts, got %d", got)
+	}
+
+	// After the cooldown, a successful submission closes the circuit
+	now = now.Add(time.Minute)
+	if _, err := r.AddChain(context.Background(), nil); err != nil {
+		t.Fatalf("AddChain() = %v", err)
+	}
+	if _, err := r.AddChain(context.Background(), nil); err != nil {
+		t.Fatalf("AddChain() = %v", err)
+	}
+}
+
+func TestResilientLogCanceled(t *testing.T) {
+	l := &flakyLog{failures: 10, err: errors.New("unavailable")}
+	r, err := NewResilientLog(l, WithBackoff(time.Minute, time.Minute), WithCircuitBreaker(1, time.Minute))
+	if err != nil {
+		t.Fatalf("NewResilientLog() = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := r.AddChain(ctx, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	// Giving up says nothing about the log, so the circuit stays closed
+	l.mu.Lock()
+	l.failures = 0
+	l.mu.Unlock()
+	if _, err := r.AddChain(context.Background(), nil); err != nil {
+		t.Fatalf("AddChain() = %v", err)
+	}
+}
+
+func TestNewResilientLog(t *testing.T) {
+	for name, opt := range map[string]ResilientLogOption{
+		"negative retries":       WithRetries(-1),
+		"backoff beyond maximum": WithBackoff(time.Second, time.Millisecond),
+		"negative timeout":       WithAttemptTimeout(-time.Second),
+		"negative threshold":     WithCircuitBreaker(-1, time.Second),
+		"zero cooldown":          WithCircuitBreaker(1, 0),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewResilientLog(&flakyLog{}, opt); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
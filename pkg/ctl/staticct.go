@@ -0,0 +1,358 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ctl
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/tls"
+	ctx509 "github.com/google/certificate-transparency-go/x509"
+)
+
+// Types of CT log API.
+const (
+	// LogTypeRFC6962 is a log implementing the RFC 6962 API.
+	LogTypeRFC6962 = "rfc6962"
+	// LogTypeStaticCT is a log implementing the static-ct-api
+	// (https://c2sp.org/static-ct-api), such as TesseraCT.
+	LogTypeStaticCT = "static-ct"
+)
+
+const (
+	// leafIndexExtension is the type of the SCT extension holding the index
+	// of the entry in a static-ct-api log.
+	leafIndexExtension = 0
+	// rfc6962NoteSignature is the signature type of static-ct-api
+	// checkpoint signatures.
+	rfc6962NoteSignature = 0x05
+	// maxResponseSize limits the responses read from a log.
+	maxResponseSize = 1 << 20
+)
+
+// StaticCTLog submits certificates to a static-ct-api log. Unlike the RFC 6962
+// client, it always validates what the log returns: SCTs must be signed by
+// the log and carry the entry's leaf index, and the log's checkpoint, which
+// is checked by Ping, must be signed by the log and never shrink.
+type StaticCTLog struct {
+	submissionURL string
+	monitoringURL string
+	origin        string
+	logID         [sha256.Size]byte
+	verifier      *ct.SignatureVerifier
+	httpClient    *http.Client
+
+	mu       sync.Mutex
+	treeSize uint64
+}
+
+var _ Log = (*StaticCTLog)(nil)
+
+// StaticCTLogOption configures the StaticCTLog returned by NewStaticCTLog.
+type StaticCTLogOption func(*StaticCTLog)
+
+// WithMonitoringURL sets the prefix that the log's checkpoint and tiles are
+// served from. Defaults to the submission URL.
+func WithMonitoringURL(monitoringURL string) StaticCTLogOption {
+	return func(l *StaticCTLog) {
+		l.monitoringURL = monitoringURL
+	}
+}
+
+// WithOrigin sets the origin line that the log's checkpoints must have. By
+// default any origin is accepted.
+func WithOrigin(origin string) StaticCTLogOption {
+	return func(l *StaticCTLog) {
+		l.origin = origin
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to talk to the log. Defaults to
+// http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) StaticCTLogOption {
+	return func(l *StaticCTLog) {
+		l.httpClient = httpClient
+	}
+}
+
+// NewStaticCTLog returns a client for the static-ct-api log with submission
+// prefix submissionURL, whose SCTs and checkpoints are signed by publicKey.
+func NewStaticCTLog(submissionURL string, publicKey crypto.PublicKey, opts ...StaticCTLogOption) (*StaticCTLog, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling CT log public key: %w", err)
+	}
+	verifier, err := ct.NewSignatureVerifier(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	l := &StaticCTLog{
+		submissionURL: submissionURL,
+		logID:         sha256.Sum256(der),
+		verifier:      verifier,
+		httpClient:    http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.monitoringURL == "" {
+		l.monitoringURL = l.submissionURL
+	}
+	l.submissionURL = strings.TrimSuffix(l.submissionURL, "/")
+	l.monitoringURL = strings.TrimSuffix(l.monitoringURL, "/")
+	return l, nil
+}
+
+// BaseURI returns the submission prefix of the log.
+func (l *StaticCTLog) BaseURI() string {
+	return l.submissionURL
+}
+
+// AddChain submits a certificate chain to the log.
+func (l *StaticCTLog) AddChain(ctx context.Context, chain []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	return l.add(ctx, ct.AddChainPath, ct.X509LogEntryType, chain)
+}
+
+// AddPreChain submits a precertificate chain to the log.
+func (l *StaticCTLog) AddPreChain(ctx context.Context, chain []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	return l.add(ctx, ct.AddPreChainPath, ct.PrecertLogEntryType, chain)
+}
+
+func (l *StaticCTLog) add(ctx context.Context, path string, etype ct.LogEntryType, chain []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	req := ct.AddChainRequest{}
+	for _, c := range chain {
+		req.Chain = append(req.Chain, c.Data)
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := l.do(ctx, http.MethodPost, l.submissionURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	var resp ct.AddChainResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("decoding %s response: %w", path, err)
+	}
+	sct, err := resp.ToSignedCertificateTimestamp()
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s response: %w", path, err)
+	}
+	// The checkpoint is not checked here: the SCT is already a promise of
+	// inclusion, and failing the submission over the monitoring URL would
+	// only have it resubmitted as a duplicate entry
+	if err := l.verifySCT(sct, etype, chain); err != nil {
+		return nil, fmt.Errorf("invalid SCT: %w", err)
+	}
+	return sct, nil
+}
+
+// verifySCT checks that sct was issued by the log for chain.
+func (l *StaticCTLog) verifySCT(sct *ct.SignedCertificateTimestamp, etype ct.LogEntryType, chain []ct.ASN1Cert) error {
+	if sct.SCTVersion != ct.V1 {
+		return fmt.Errorf("unsupported SCT version %d", sct.SCTVersion)
+	}
+	if sct.LogID.KeyID != l.logID {
+		return errors.New("SCT was issued by a different log")
+	}
+	if _, err := LeafIndex(sct); err != nil {
+		return err
+	}
+	certs := make([]*ctx509.Certificate, 0, len(chain))
+	for _, c := range chain {
+		cert, err := ctx509.ParseCertificate(c.Data)
+		if ctx509.IsFatal(err) {
+			return fmt.Errorf("parsing submitted certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	leaf, err := ct.MerkleTreeLeafFromChain(certs, etype, sct.Timestamp)
+	if err != nil {
+		return err
+	}
+	return l.verifier.VerifySCTSignature(*sct, ct.LogEntry{Leaf: *leaf})
+}
+
+// LeafIndex returns the index of the log entry that a static-ct-api SCT was
+// issued for.
+func LeafIndex(sct *ct.SignedCertificateTimestamp) (uint64, error) {
+	ext := sct.Extensions
+	for len(ext) > 0 {
+		if len(ext) < 3 {
+			return 0, errors.New("malformed SCT extensions")
+		}
+		typ, n := ext[0], int(binary.BigEndian.Uint16(ext[1:3]))
+		if len(ext) < 3+n {
+			return 0, errors.New("malformed SCT extensions")
+		}
+		data := ext[3 : 3+n]
+		ext = ext[3+n:]
+		if typ != leafIndexExtension {
+			continue
+		}
+		// The index is a 40-bit big-endian integer
+		if len(data) != 5 {
+			return 0, errors.New("malformed leaf_index SCT extension")
+		}
+		return uint64(data[0])<<32 | uint64(binary.BigEndian.Uint32(data[1:])), nil
+	}
+	return 0, errors.New("SCT has no leaf_index extension")
+}
+
+// Checkpoint fetches and verifies the log's checkpoint, and returns its tree
+// size. It is an error for the tree size to be smaller than that of a
+// checkpoint verified before.
+func (l *StaticCTLog) Checkpoint(ctx context.Context) (uint64, error) {
+	data, err := l.do(ctx, http.MethodGet, l.monitoringURL+"/checkpoint", nil)
+	if err != nil {
+		return 0, err
+	}
+	size, err := l.verifyCheckpoint(data)
+	if err != nil {
+		return 0, fmt.Errorf("invalid checkpoint: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if size < l.treeSize {
+		return 0, fmt.Errorf("invalid checkpoint: tree size %d is smaller than previously seen %d", size, l.treeSize)
+	}
+	l.treeSize = size
+	return size, nil
+}
+
+// Ping checks that the log is reachable and consistent by fetching and
+// verifying its checkpoint.
+func (l *StaticCTLog) Ping(ctx context.Context) error {
+	_, err := l.Checkpoint(ctx)
+	return err
+}
+
+// verifyCheckpoint checks that the signed note data is a checkpoint signed by
+// the log, and returns its tree size.
+func (l *StaticCTLog) verifyCheckpoint(data []byte) (uint64, error) {
+	// A note is its text, a blank line, and signature lines
+	text, sigs, ok := strings.Cut(string(data), "\n\n")
+	if !ok {
+		return 0, errors.New("malformed note")
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) < 3 {
+		return 0, errors.New("malformed checkpoint")
+	}
+	origin := lines[0]
+	if l.origin != "" && origin != l.origin {
+		return 0, fmt.Errorf("unexpected origin %q", origin)
+	}
+	size, err := strconv.ParseUint(lines[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed tree size: %w", err)
+	}
+	rootHash, err := base64.StdEncoding.DecodeString(lines[2])
+	if err != nil || len(rootHash) != sha256.Size {
+		return 0, errors.New("malformed root hash")
+	}
+
+	keyHash := noteKeyHash(origin, l.logID)
+	for _, line := range strings.Split(strings.TrimSuffix(sigs, "\n"), "\n") {
+		line, ok := strings.CutPrefix(line, "— ")
+		if !ok {
+			return 0, errors.New("malformed note signature")
+		}
+		name, sig, ok := strings.Cut(line, " ")
+		if !ok || name != origin {
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(sig)
+		if err != nil || len(raw) < 12 || !bytes.Equal(raw[:4], keyHash[:]) {
+			continue
+		}
+		// The signature is a timestamp followed by a TreeHeadSignature
+		sth := ct.SignedTreeHead{
+			Version:   ct.V1,
+			TreeSize:  size,
+			Timestamp: binary.BigEndian.Uint64(raw[4:12]),
+		}
+		copy(sth.SHA256RootHash[:], rootHash)
+		if rest, err := tls.Unmarshal(raw[12:], &sth.TreeHeadSignature); err != nil || len(rest) > 0 {
+			return 0, errors.New("malformed checkpoint signature")
+		}
+		if err := l.verifier.VerifySTHSignature(sth); err != nil {
+			return 0, err
+		}
+		return size, nil
+	}
+	return 0, errors.New("checkpoint is not signed by the log")
+}
+
+// noteKeyHash returns the key hash identifying checkpoint signatures of the
+// log with ID logID.
+func noteKeyHash(origin string, logID [sha256.Size]byte) [4]byte {
+	h := sha256.Sum256(append([]byte(origin+"\n"), append([]byte{rfc6962NoteSignature}, logID[:]...)...))
+	return [4]byte(h[:4])
+}
+
+// do sends a request to the log and returns the response body.
+func (l *StaticCTLog) do(ctx context.Context, method, url string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &httpError{
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("%s %s: %s: %s", method, url, resp.Status, bytes.TrimSpace(data)),
+		}
+	}
+	return data, nil
+}
+
+// httpError is returned when a log responds with an HTTP error.
+type httpError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *httpError) Error() string {
+	return e.Err.Error()
+}
@@ -0,0 +1,381 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctl
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/tls"
+	ctx509 "github.com/google/certificate-transparency-go/x509"
+)
+
+// fakeStaticLog is an in-process stand-in for a static-ct-api log. Its
+// fields can be changed to make it misbehave.
+type fakeStaticLog struct {
+	*httptest.Server
+	key    *ecdsa.PrivateKey
+	origin string
+
+	mu sync.Mutex
+	// entries is the size of the log
+	entries uint64
+	// omitLeafIndex omits the leaf_index extension from SCTs
+	omitLeafIndex bool
+	// signingKey signs SCTs and checkpoints instead of key, if set
+	signingKey *ecdsa.PrivateKey
+	// shrink publishes the checkpoint of an empty log
+	shrink bool
+}
+
+func newFakeStaticLog(t *testing.T) *fakeStaticLog {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := &fakeStaticLog{key: key, origin: "example.com/log"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+ct.AddChainPath, func(w http.ResponseWriter, r *http.Request) {
+		l.add(w, r, ct.X509LogEntryType)
+	})
+	mux.HandleFunc("POST "+ct.AddPreChainPath, func(w http.ResponseWriter, r *http.Request) {
+		l.add(w, r, ct.PrecertLogEntryType)
+	})
+	mux.HandleFunc("GET /checkpoint", l.checkpoint)
+	l.Server = httptest.NewServer(mux)
+	t.Cleanup(l.Close)
+	return l
+}
+
+func (l *fakeStaticLog) signer() (*ecdsa.PrivateKey, [sha256.Size]byte) {
+	key := l.key
+	if l.signingKey != nil {
+		key = l.signingKey
+	}
+	der, _ := x509.MarshalPKIXPublicKey(&l.key.PublicKey)
+	return key, sha256.Sum256(der)
+}
+
+func (l *fakeStaticLog) add(w http.ResponseWriter, r *http.Request, etype ct.LogEntryType) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var req ct.AddChainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var chain []*ctx509.Certificate
+	for _, der := range req.Chain {
+		cert, err := ctx509.ParseCertificate(der)
+		if ctx509.IsFatal(err) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		chain = append(chain, cert)
+	}
+
+	key, logID := l.signer()
+	sct := ct.SignedCertificateTimestamp{
+		SCTVersion: ct.V1,
+		LogID:      ct.LogID{KeyID: logID},
+		Timestamp:  uint64(time.Now().UnixMilli()),
+	}
+	if !l.omitLeafIndex {
+		// extension_type leaf_index, 5 bytes of extension_data
+		sct.Extensions = ct.CTExtensions{leafIndexExtension, 0, 5, byte(l.entries >> 32), 0, 0, 0, 0}
+		binary.BigEndian.PutUint32(sct.Extensions[4:], uint32(l.entries))
+	}
+	leaf, err := ct.MerkleTreeLeafFromChain(chain, etype, sct.Timestamp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	data, err := ct.SerializeSCTSignatureInput(sct, ct.LogEntry{Leaf: *leaf})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sig, err := tls.CreateSignature(*key, tls.SHA256, data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sct.Signature = ct.DigitallySigned(sig)
+	l.entries++
+
+	resp, _ := ToAddChainResponse(&sct)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (l *fakeStaticLog) checkpoint(w http.ResponseWriter, _ *http.Request) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key, logID := l.signer()
+	sth := ct.SignedTreeHead{
+		Version:   ct.V1,
+		TreeSize:  l.entries,
+		Timestamp: uint64(time.Now().UnixMilli()),
+	}
+	if l.shrink {
+		sth.TreeSize = 0
+	}
+	// The root hash is not checked by the client
+	sth.SHA256RootHash = sha256.Sum256([]byte(fmt.Sprint(sth.TreeSize)))
+	data, err := ct.SerializeSTHSignatureInput(sth)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ds, err := tls.CreateSignature(*key, tls.SHA256, data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	dsBytes, _ := tls.Marshal(ds)
+	keyHash := noteKeyHash(l.origin, logID)
+	sig := append(keyHash[:], binary.BigEndian.AppendUint64(nil, sth.Timestamp)...)
+	sig = append(sig, dsBytes...)
+
+	fmt.Fprintf(w, "%s\n%d\n%s\n\n— %s %s\n", l.origin, sth.TreeSize,
+		base64.StdEncoding.EncodeToString(sth.SHA256RootHash[:]),
+		l.origin, base64.StdEncoding.EncodeToString(sig))
+}
+
+// testChain returns a certificate chain and precertificate chain issued by a
+// test CA.
+func testChain(t *testing.T) (cert, precert []ct.ASN1Cert) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	caCert, _ := x509.ParseCertificate(caDER)
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTmpl, caCert, &leafKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafTmpl.ExtraExtensions = []pkix.Extension{{
+		Id:       asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 3},
+		Critical: true,
+		Value:    []byte{0x05, 0x00},
+	}}
+	precertDER, err := x509.CreateCertificate(rand.Reader, leafTmpl, caCert, &leafKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []ct.ASN1Cert{{Data: leafDER}, {Data: caDER}}, []ct.ASN1Cert{{Data: precertDER}, {Data: caDER}}
+}
+
+func TestStaticCTLog(t *testing.T) {
+	cert, precert := testChain(t)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		misbehave func(*fakeStaticLog)
+		opts      []StaticCTLogOption
+		wantErr   string
+	}{
+		"valid": {},
+		"expected origin": {
+			opts: []StaticCTLogOption{WithOrigin("example.com/log")},
+		},
+		"unexpected origin": {
+			opts:    []StaticCTLogOption{WithOrigin("example.com/other")},
+			wantErr: "unexpected origin",
+		},
+		"missing leaf index": {
+			misbehave: func(l *fakeStaticLog) { l.omitLeafIndex = true },
+			wantErr:   "no leaf_index extension",
+		},
+		"wrong signing key": {
+			misbehave: func(l *fakeStaticLog) { l.signingKey = otherKey },
+			wantErr:   "invalid SCT",
+		},
+		"shrinking checkpoint": {
+			misbehave: func(l *fakeStaticLog) { l.shrink = true },
+			wantErr:   "smaller than previously seen",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fake := newFakeStaticLog(t)
+			l, err := NewStaticCTLog(fake.URL, &fake.key.PublicKey, test.opts...)
+			if err != nil {
+				t.Fatalf("NewStaticCTLog() = %v", err)
+			}
+
+			sct, err := l.AddPreChain(context.Background(), precert)
+			if err != nil {
+				t.Fatalf("AddPreChain() = %v", err)
+			}
+			if idx, err := LeafIndex(sct); err != nil || idx != 0 {
+				t.Errorf("LeafIndex() = %d, %v, want 0", idx, err)
+			}
+			// The checkpoint includes the first entry
+			err = l.Ping(context.Background())
+			if err != nil {
+				if test.wantErr != "" && strings.Contains(err.Error(), test.wantErr) {
+					return
+				}
+				t.Fatalf("Ping() = %v", err)
+			}
+
+			if test.misbehave != nil {
+				fake.mu.Lock()
+				test.misbehave(fake)
+				fake.mu.Unlock()
+			}
+			sct, err = l.AddChain(context.Background(), cert)
+			if err == nil {
+				if idx, err := LeafIndex(sct); err != nil || idx != 1 {
+					t.Errorf("LeafIndex() = %d, %v, want 1", idx, err)
+				}
+				err = l.Ping(context.Background())
+			}
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("got %v, want error containing %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestStaticCTLogCheckpoint(t *testing.T) {
+	fake := newFakeStaticLog(t)
+	// Checkpoints may be served separately from the submission endpoints
+	l, err := NewStaticCTLog("http://unused.example.com/", &fake.key.PublicKey, WithMonitoringURL(fake.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewStaticCTLog() = %v", err)
+	}
+	if l.BaseURI() != "http://unused.example.com" {
+		t.Errorf("unexpected BaseURI() %q", l.BaseURI())
+	}
+	fake.entries = 42
+	size, err := l.Checkpoint(context.Background())
+	if err != nil {
+		t.Fatalf("Checkpoint() = %v", err)
+	}
+	if size != 42 {
+		t.Errorf("expected tree size 42, got %d", size)
+	}
+
+	// A checkpoint signed with another key is rejected
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewStaticCTLog(fake.URL, crypto.PublicKey(&otherKey.PublicKey))
+	if err != nil {
+		t.Fatalf("NewStaticCTLog() = %v", err)
+	}
+	if _, err := other.Checkpoint(context.Background()); err == nil {
+		t.Error("expected error for checkpoint not signed by the log")
+	}
+}
+
+func TestStaticCTLogUnreachableCheckpoint(t *testing.T) {
+	fake := newFakeStaticLog(t)
+	l, err := NewStaticCTLog(fake.URL, &fake.key.PublicKey, WithMonitoringURL("http://127.0.0.1:0"))
+	if err != nil {
+		t.Fatalf("NewStaticCTLog() = %v", err)
+	}
+	// A verified SCT is returned even if the checkpoint cannot be fetched
+	_, cert := testChain(t)
+	if _, err := l.AddPreChain(context.Background(), cert); err != nil {
+		t.Fatalf("AddPreChain() = %v", err)
+	}
+	if err := l.Ping(context.Background()); err == nil {
+		t.Error("expected Ping() to fail when the checkpoint cannot be fetched")
+	}
+}
+
+func TestLeafIndex(t *testing.T) {
+	tests := map[string]struct {
+		ext     ct.CTExtensions
+		want    uint64
+		wantErr bool
+	}{
+		"leaf index":             {ext: ct.CTExtensions{0, 0, 5, 1, 0, 0, 0, 2}, want: 1<<32 | 2},
+		"after other extension":  {ext: ct.CTExtensions{7, 0, 1, 9, 0, 0, 5, 0, 0, 0, 0, 3}, want: 3},
+		"no extensions":          {wantErr: true},
+		"truncated":              {ext: ct.CTExtensions{0, 0, 5, 1}, wantErr: true},
+		"wrong leaf index size":  {ext: ct.CTExtensions{0, 0, 4, 0, 0, 0, 1}, wantErr: true},
+		"only another extension": {ext: ct.CTExtensions{7, 0, 0}, wantErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := LeafIndex(&ct.SignedCertificateTimestamp{Extensions: test.ext})
+			if (err != nil) != test.wantErr {
+				t.Fatalf("LeafIndex() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("LeafIndex() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
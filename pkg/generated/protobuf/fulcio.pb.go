@@ -26,6 +26,8 @@ import (
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -98,9 +100,13 @@ type CreateSigningCertificateRequest struct {
 	//
 	//	*CreateSigningCertificateRequest_PublicKeyRequest
 	//	*CreateSigningCertificateRequest_CertificateSigningRequest
-	Key           isCreateSigningCertificateRequest_Key `protobuf_oneof:"key"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	Key isCreateSigningCertificateRequest_Key `protobuf_oneof:"key"`
+	// Optional lifetime of the requested certificate. If unset, the issuer's
+	// default lifetime is used. Requests for a lifetime longer than the
+	// maximum configured for the issuer are clamped to that maximum.
+	RequestedValidity *durationpb.Duration `protobuf:"bytes,4,opt,name=requested_validity,json=requestedValidity,proto3" json:"requested_validity,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *CreateSigningCertificateRequest) Reset() {
@@ -165,6 +171,13 @@ func (x *CreateSigningCertificateRequest) GetCertificateSigningRequest() []byte
 	return nil
 }
 
+func (x *CreateSigningCertificateRequest) GetRequestedValidity() *durationpb.Duration {
+	if x != nil {
+		return x.RequestedValidity
+	}
+	return nil
+}
+
 type isCreateSigningCertificateRequest_Key interface {
 	isCreateSigningCertificateRequest_Key()
 }
@@ -265,10 +278,14 @@ type PublicKeyRequest struct {
 	PublicKey *PublicKey `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
 	// Proof that the client possesses the private key; must be verifiable by provided public key
 	//
-	// This is a currently a signature over the `sub` claim from the OIDC identity token
+	// This is a signature over `challenge` when set, otherwise over the `sub` claim
+	// from the OIDC identity token
 	ProofOfPossession []byte `protobuf:"bytes,2,opt,name=proof_of_possession,json=proofOfPossession,proto3" json:"proof_of_possession,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	// A nonce returned by GetChallenge. If set, proof_of_possession must be a
+	// signature over this value. Required for issuers that mandate challenges.
+	Challenge     string `protobuf:"bytes,3,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PublicKeyRequest) Reset() {
@@ -315,6 +332,13 @@ func (x *PublicKeyRequest) GetProofOfPossession() []byte {
 	return nil
 }
 
+func (x *PublicKeyRequest) GetChallenge() string {
+	if x != nil {
+		return x.Challenge
+	}
+	return ""
+}
+
 type PublicKey struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The cryptographic algorithm to use with the key material
@@ -468,9 +492,15 @@ type SigningCertificateDetachedSCT struct {
 	//
 	// The SCT format is an AddChainResponse struct, defined in
 	// https://github.com/google/certificate-transparency-go
+	//
+	// When the certificate is submitted to several CT logs, this is the SCT
+	// of the first log, and all of them are in signed_certificate_timestamps.
 	SignedCertificateTimestamp []byte `protobuf:"bytes,2,opt,name=signed_certificate_timestamp,json=signedCertificateTimestamp,proto3" json:"signed_certificate_timestamp,omitempty"`
-	unknownFields              protoimpl.UnknownFields
-	sizeCache                  protoimpl.SizeCache
+	// The SCTs of every CT log the certificate was submitted to, in the same
+	// format as signed_certificate_timestamp.
+	SignedCertificateTimestamps [][]byte `protobuf:"bytes,3,rep,name=signed_certificate_timestamps,json=signedCertificateTimestamps,proto3" json:"signed_certificate_timestamps,omitempty"`
+	unknownFields               protoimpl.UnknownFields
+	sizeCache                   protoimpl.SizeCache
 }
 
 func (x *SigningCertificateDetachedSCT) Reset() {
@@ -517,6 +547,13 @@ func (x *SigningCertificateDetachedSCT) GetSignedCertificateTimestamp() []byte {
 	return nil
 }
 
+func (x *SigningCertificateDetachedSCT) GetSignedCertificateTimestamps() [][]byte {
+	if x != nil {
+		return x.SignedCertificateTimestamps
+	}
+	return nil
+}
+
 type SigningCertificateEmbeddedSCT struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The certificate chain serialized with the leaf certificate first, followed
@@ -801,8 +838,10 @@ type OIDCIssuer struct {
 	// Whether to skip email verification for this issuer.
 	// Only applicable to email-type issuers from trusted internal identity providers.
 	SkipEmailVerification bool `protobuf:"varint,8,opt,name=skip_email_verification,json=skipEmailVerification,proto3" json:"skip_email_verification,omitempty"`
-	unknownFields         protoimpl.UnknownFields
-	sizeCache             protoimpl.SizeCache
+	// Whether proof of possession must sign a nonce from GetChallenge for this issuer.
+	RequireChallenge bool `protobuf:"varint,9,opt,name=require_challenge,json=requireChallenge,proto3" json:"require_challenge,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *OIDCIssuer) Reset() {
@@ -902,6 +941,13 @@ func (x *OIDCIssuer) GetSkipEmailVerification() bool {
 	return false
 }
 
+func (x *OIDCIssuer) GetRequireChallenge() bool {
+	if x != nil {
+		return x.RequireChallenge
+	}
+	return false
+}
+
 type isOIDCIssuer_Issuer interface {
 	isOIDCIssuer_Issuer()
 }
@@ -921,33 +967,462 @@ func (*OIDCIssuer_IssuerUrl) isOIDCIssuer_Issuer() {}
 
 func (*OIDCIssuer_WildcardIssuerUrl) isOIDCIssuer_Issuer() {}
 
+// This is created for forward compatibility in case we want to add fields in the future.
+type GetChallengeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChallengeRequest) Reset() {
+	*x = GetChallengeRequest{}
+	mi := &file_fulcio_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChallengeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChallengeRequest) ProtoMessage() {}
+
+func (x *GetChallengeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fulcio_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChallengeRequest.ProtoReflect.Descriptor instead.
+func (*GetChallengeRequest) Descriptor() ([]byte, []int) {
+	return file_fulcio_proto_rawDescGZIP(), []int{13}
+}
+
+// A server-issued nonce for proof of possession.
+type Challenge struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The nonce to be signed by the private key whose public key is being certified.
+	Nonce string `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// The time after which the nonce is no longer accepted.
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Challenge) Reset() {
+	*x = Challenge{}
+	mi := &file_fulcio_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Challenge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Challenge) ProtoMessage() {}
+
+func (x *Challenge) ProtoReflect() protoreflect.Message {
+	mi := &file_fulcio_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Challenge.ProtoReflect.Descriptor instead.
+func (*Challenge) Descriptor() ([]byte, []int) {
+	return file_fulcio_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Challenge) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *Challenge) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+// Selects certificates from the issuance ledger. Unset fields match all certificates.
+type ListIssuedCertificatesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The hexadecimal serial number of the certificate.
+	SerialNumber string `protobuf:"bytes,1,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	// The subject alternative name of the certificate, such as an email address or URI.
+	Identity string `protobuf:"bytes,2,opt,name=identity,proto3" json:"identity,omitempty"`
+	// The OIDC issuer of the identity token the certificate was issued for.
+	Issuer string `protobuf:"bytes,3,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	// Selects certificates issued at or after this time.
+	IssuedAfter *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=issued_after,json=issuedAfter,proto3" json:"issued_after,omitempty"`
+	// Selects certificates issued before this time.
+	IssuedBefore *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=issued_before,json=issuedBefore,proto3" json:"issued_before,omitempty"`
+	// The maximum number of certificates to return. Defaults to 100, and is capped at 1000.
+	PageSize int32 `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// The next_page_token of the previous response, to continue listing with the same request.
+	PageToken     string `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIssuedCertificatesRequest) Reset() {
+	*x = ListIssuedCertificatesRequest{}
+	mi := &file_fulcio_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIssuedCertificatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIssuedCertificatesRequest) ProtoMessage() {}
+
+func (x *ListIssuedCertificatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fulcio_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIssuedCertificatesRequest.ProtoReflect.Descriptor instead.
+func (*ListIssuedCertificatesRequest) Descriptor() ([]byte, []int) {
+	return file_fulcio_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListIssuedCertificatesRequest) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *ListIssuedCertificatesRequest) GetIdentity() string {
+	if x != nil {
+		return x.Identity
+	}
+	return ""
+}
+
+func (x *ListIssuedCertificatesRequest) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *ListIssuedCertificatesRequest) GetIssuedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssuedAfter
+	}
+	return nil
+}
+
+func (x *ListIssuedCertificatesRequest) GetIssuedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssuedBefore
+	}
+	return nil
+}
+
+func (x *ListIssuedCertificatesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListIssuedCertificatesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListIssuedCertificatesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The matching certificates, most recently issued first.
+	Certificates []*IssuedCertificate `protobuf:"bytes,1,rep,name=certificates,proto3" json:"certificates,omitempty"`
+	// A token to retrieve the next page of certificates, or empty if there are no more.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIssuedCertificatesResponse) Reset() {
+	*x = ListIssuedCertificatesResponse{}
+	mi := &file_fulcio_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIssuedCertificatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIssuedCertificatesResponse) ProtoMessage() {}
+
+func (x *ListIssuedCertificatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fulcio_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIssuedCertificatesResponse.ProtoReflect.Descriptor instead.
+func (*ListIssuedCertificatesResponse) Descriptor() ([]byte, []int) {
+	return file_fulcio_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListIssuedCertificatesResponse) GetCertificates() []*IssuedCertificate {
+	if x != nil {
+		return x.Certificates
+	}
+	return nil
+}
+
+func (x *ListIssuedCertificatesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetIssuedCertificateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The hexadecimal serial number of the certificate, optionally separated by colons.
+	SerialNumber  string `protobuf:"bytes,1,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIssuedCertificateRequest) Reset() {
+	*x = GetIssuedCertificateRequest{}
+	mi := &file_fulcio_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIssuedCertificateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIssuedCertificateRequest) ProtoMessage() {}
+
+func (x *GetIssuedCertificateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fulcio_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIssuedCertificateRequest.ProtoReflect.Descriptor instead.
+func (*GetIssuedCertificateRequest) Descriptor() ([]byte, []int) {
+	return file_fulcio_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetIssuedCertificateRequest) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+// A certificate recorded in the issuance ledger.
+type IssuedCertificate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The serial number of the certificate in lower case hexadecimal.
+	SerialNumber string `protobuf:"bytes,1,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	// The subject alternative name of the certificate.
+	Identity string `protobuf:"bytes,2,opt,name=identity,proto3" json:"identity,omitempty"`
+	// The OIDC issuer of the identity token the certificate was issued for.
+	Issuer string `protobuf:"bytes,3,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	// The hex-encoded subject key identifier of the certificate.
+	SubjectKeyId string `protobuf:"bytes,4,opt,name=subject_key_id,json=subjectKeyId,proto3" json:"subject_key_id,omitempty"`
+	// The hex-encoded SHA-256 digest of the certificate's DER-encoded SubjectPublicKeyInfo.
+	PublicKeySha256 string `protobuf:"bytes,5,opt,name=public_key_sha256,json=publicKeySha256,proto3" json:"public_key_sha256,omitempty"`
+	// The Fulcio extensions of the certificate, keyed by name, such as SourceRepositoryURI.
+	Extensions map[string]string `protobuf:"bytes,6,rep,name=extensions,proto3" json:"extensions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// The TLS-encoded Signed Certificate Timestamps returned by the CT logs, as defined in
+	// https://datatracker.ietf.org/doc/html/rfc6962#section-3.2
+	SignedCertificateTimestamps [][]byte `protobuf:"bytes,7,rep,name=signed_certificate_timestamps,json=signedCertificateTimestamps,proto3" json:"signed_certificate_timestamps,omitempty"`
+	// The time the certificate was issued.
+	IssuedAt  *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	NotBefore *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`
+	NotAfter  *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`
+	// The PEM-encoded certificate.
+	Certificate   string `protobuf:"bytes,11,opt,name=certificate,proto3" json:"certificate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssuedCertificate) Reset() {
+	*x = IssuedCertificate{}
+	mi := &file_fulcio_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssuedCertificate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssuedCertificate) ProtoMessage() {}
+
+func (x *IssuedCertificate) ProtoReflect() protoreflect.Message {
+	mi := &file_fulcio_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssuedCertificate.ProtoReflect.Descriptor instead.
+func (*IssuedCertificate) Descriptor() ([]byte, []int) {
+	return file_fulcio_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *IssuedCertificate) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *IssuedCertificate) GetIdentity() string {
+	if x != nil {
+		return x.Identity
+	}
+	return ""
+}
+
+func (x *IssuedCertificate) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *IssuedCertificate) GetSubjectKeyId() string {
+	if x != nil {
+		return x.SubjectKeyId
+	}
+	return ""
+}
+
+func (x *IssuedCertificate) GetPublicKeySha256() string {
+	if x != nil {
+		return x.PublicKeySha256
+	}
+	return ""
+}
+
+func (x *IssuedCertificate) GetExtensions() map[string]string {
+	if x != nil {
+		return x.Extensions
+	}
+	return nil
+}
+
+func (x *IssuedCertificate) GetSignedCertificateTimestamps() [][]byte {
+	if x != nil {
+		return x.SignedCertificateTimestamps
+	}
+	return nil
+}
+
+func (x *IssuedCertificate) GetIssuedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return nil
+}
+
+func (x *IssuedCertificate) GetNotBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotBefore
+	}
+	return nil
+}
+
+func (x *IssuedCertificate) GetNotAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotAfter
+	}
+	return nil
+}
+
+func (x *IssuedCertificate) GetCertificate() string {
+	if x != nil {
+		return x.Certificate
+	}
+	return ""
+}
+
 var File_fulcio_proto protoreflect.FileDescriptor
 
 const file_fulcio_proto_rawDesc = "" +
 	"\n" +
-	"\ffulcio.proto\x12\x16dev.sigstore.fulcio.v2\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"\x9d\x02\n" +
+	"\ffulcio.proto\x12\x16dev.sigstore.fulcio.v2\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/api/field_behavior.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"\xe7\x02\n" +
 	"\x1fCreateSigningCertificateRequest\x12K\n" +
 	"\vcredentials\x18\x01 \x01(\v2#.dev.sigstore.fulcio.v2.CredentialsB\x04\xe2A\x01\x02R\vcredentials\x12^\n" +
 	"\x12public_key_request\x18\x02 \x01(\v2(.dev.sigstore.fulcio.v2.PublicKeyRequestB\x04\xe2A\x01\x02H\x00R\x10publicKeyRequest\x12F\n" +
-	"\x1bcertificate_signing_request\x18\x03 \x01(\fB\x04\xe2A\x01\x02H\x00R\x19certificateSigningRequestB\x05\n" +
+	"\x1bcertificate_signing_request\x18\x03 \x01(\fB\x04\xe2A\x01\x02H\x00R\x19certificateSigningRequest\x12H\n" +
+	"\x12requested_validity\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x11requestedValidityB\x05\n" +
 	"\x03key\"N\n" +
 	"\vCredentials\x120\n" +
 	"\x13oidc_identity_token\x18\x01 \x01(\tH\x00R\x11oidcIdentityTokenB\r\n" +
-	"\vcredentials\"\x90\x01\n" +
+	"\vcredentials\"\xae\x01\n" +
 	"\x10PublicKeyRequest\x12F\n" +
 	"\n" +
 	"public_key\x18\x01 \x01(\v2!.dev.sigstore.fulcio.v2.PublicKeyB\x04\xe2A\x01\x02R\tpublicKey\x124\n" +
-	"\x13proof_of_possession\x18\x02 \x01(\fB\x04\xe2A\x01\x02R\x11proofOfPossession\"u\n" +
+	"\x13proof_of_possession\x18\x02 \x01(\fB\x04\xe2A\x01\x02R\x11proofOfPossession\x12\x1c\n" +
+	"\tchallenge\x18\x03 \x01(\tR\tchallenge\"u\n" +
 	"\tPublicKey\x12H\n" +
 	"\talgorithm\x18\x01 \x01(\x0e2*.dev.sigstore.fulcio.v2.PublicKeyAlgorithmR\talgorithm\x12\x1e\n" +
 	"\acontent\x18\x02 \x01(\tB\x04\xe2A\x01\x02R\acontent\"\xa3\x02\n" +
 	"\x12SigningCertificate\x12~\n" +
 	"\x1fsigned_certificate_detached_sct\x18\x01 \x01(\v25.dev.sigstore.fulcio.v2.SigningCertificateDetachedSCTH\x00R\x1csignedCertificateDetachedSct\x12~\n" +
 	"\x1fsigned_certificate_embedded_sct\x18\x02 \x01(\v25.dev.sigstore.fulcio.v2.SigningCertificateEmbeddedSCTH\x00R\x1csignedCertificateEmbeddedSctB\r\n" +
-	"\vcertificate\"\xa1\x01\n" +
+	"\vcertificate\"\xe5\x01\n" +
 	"\x1dSigningCertificateDetachedSCT\x12>\n" +
 	"\x05chain\x18\x01 \x01(\v2(.dev.sigstore.fulcio.v2.CertificateChainR\x05chain\x12@\n" +
-	"\x1csigned_certificate_timestamp\x18\x02 \x01(\fR\x1asignedCertificateTimestamp\"_\n" +
+	"\x1csigned_certificate_timestamp\x18\x02 \x01(\fR\x1asignedCertificateTimestamp\x12B\n" +
+	"\x1dsigned_certificate_timestamps\x18\x03 \x03(\fR\x1bsignedCertificateTimestamps\"_\n" +
 	"\x1dSigningCertificateEmbeddedSCT\x12>\n" +
 	"\x05chain\x18\x01 \x01(\v2(.dev.sigstore.fulcio.v2.CertificateChainR\x05chain\"\x17\n" +
 	"\x15GetTrustBundleRequest\"O\n" +
@@ -957,7 +1432,7 @@ const file_fulcio_proto_rawDesc = "" +
 	"\fcertificates\x18\x01 \x03(\tR\fcertificates\"\x19\n" +
 	"\x17GetConfigurationRequest\"M\n" +
 	"\rConfiguration\x12<\n" +
-	"\aissuers\x18\x01 \x03(\v2\".dev.sigstore.fulcio.v2.OIDCIssuerR\aissuers\"\xde\x02\n" +
+	"\aissuers\x18\x01 \x03(\v2\".dev.sigstore.fulcio.v2.OIDCIssuerR\aissuers\"\x8b\x03\n" +
 	"\n" +
 	"OIDCIssuer\x12\x1f\n" +
 	"\n" +
@@ -969,17 +1444,59 @@ const file_fulcio_proto_rawDesc = "" +
 	"\vissuer_type\x18\x06 \x01(\tR\n" +
 	"issuerType\x12%\n" +
 	"\x0esubject_domain\x18\a \x01(\tR\rsubjectDomain\x126\n" +
-	"\x17skip_email_verification\x18\b \x01(\bR\x15skipEmailVerificationB\b\n" +
-	"\x06issuer*_\n" +
+	"\x17skip_email_verification\x18\b \x01(\bR\x15skipEmailVerification\x12+\n" +
+	"\x11require_challenge\x18\t \x01(\bR\x10requireChallengeB\b\n" +
+	"\x06issuer\"\x15\n" +
+	"\x13GetChallengeRequest\"\\\n" +
+	"\tChallenge\x12\x14\n" +
+	"\x05nonce\x18\x01 \x01(\tR\x05nonce\x129\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\xb4\x02\n" +
+	"\x1dListIssuedCertificatesRequest\x12#\n" +
+	"\rserial_number\x18\x01 \x01(\tR\fserialNumber\x12\x1a\n" +
+	"\bidentity\x18\x02 \x01(\tR\bidentity\x12\x16\n" +
+	"\x06issuer\x18\x03 \x01(\tR\x06issuer\x12=\n" +
+	"\fissued_after\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vissuedAfter\x12?\n" +
+	"\rissued_before\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\fissuedBefore\x12\x1b\n" +
+	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\a \x01(\tR\tpageToken\"\x97\x01\n" +
+	"\x1eListIssuedCertificatesResponse\x12M\n" +
+	"\fcertificates\x18\x01 \x03(\v2).dev.sigstore.fulcio.v2.IssuedCertificateR\fcertificates\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"H\n" +
+	"\x1bGetIssuedCertificateRequest\x12)\n" +
+	"\rserial_number\x18\x01 \x01(\tB\x04\xe2A\x01\x02R\fserialNumber\"\xeb\x04\n" +
+	"\x11IssuedCertificate\x12#\n" +
+	"\rserial_number\x18\x01 \x01(\tR\fserialNumber\x12\x1a\n" +
+	"\bidentity\x18\x02 \x01(\tR\bidentity\x12\x16\n" +
+	"\x06issuer\x18\x03 \x01(\tR\x06issuer\x12$\n" +
+	"\x0esubject_key_id\x18\x04 \x01(\tR\fsubjectKeyId\x12*\n" +
+	"\x11public_key_sha256\x18\x05 \x01(\tR\x0fpublicKeySha256\x12Y\n" +
+	"\n" +
+	"extensions\x18\x06 \x03(\v29.dev.sigstore.fulcio.v2.IssuedCertificate.ExtensionsEntryR\n" +
+	"extensions\x12B\n" +
+	"\x1dsigned_certificate_timestamps\x18\a \x03(\fR\x1bsignedCertificateTimestamps\x127\n" +
+	"\tissued_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\bissuedAt\x129\n" +
+	"\n" +
+	"not_before\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tnotBefore\x127\n" +
+	"\tnot_after\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\bnotAfter\x12 \n" +
+	"\vcertificate\x18\v \x01(\tR\vcertificate\x1a=\n" +
+	"\x0fExtensionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*_\n" +
 	"\x12PublicKeyAlgorithm\x12$\n" +
 	" PUBLIC_KEY_ALGORITHM_UNSPECIFIED\x10\x00\x12\v\n" +
 	"\aRSA_PSS\x10\x01\x12\t\n" +
 	"\x05ECDSA\x10\x02\x12\v\n" +
-	"\aED25519\x10\x032\xb6\x03\n" +
+	"\aED25519\x10\x032\xfb\x06\n" +
 	"\x02CA\x12\x9f\x01\n" +
 	"\x18CreateSigningCertificate\x127.dev.sigstore.fulcio.v2.CreateSigningCertificateRequest\x1a*.dev.sigstore.fulcio.v2.SigningCertificate\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/api/v2/signingCert\x12\x81\x01\n" +
 	"\x0eGetTrustBundle\x12-.dev.sigstore.fulcio.v2.GetTrustBundleRequest\x1a#.dev.sigstore.fulcio.v2.TrustBundle\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/api/v2/trustBundle\x12\x89\x01\n" +
-	"\x10GetConfiguration\x12/.dev.sigstore.fulcio.v2.GetConfigurationRequest\x1a%.dev.sigstore.fulcio.v2.Configuration\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/api/v2/configurationB\x8f\x03\x92A\xb1\x02\x12\xb9\x01\n" +
+	"\x10GetConfiguration\x12/.dev.sigstore.fulcio.v2.GetConfigurationRequest\x1a%.dev.sigstore.fulcio.v2.Configuration\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/api/v2/configuration\x12y\n" +
+	"\fGetChallenge\x12+.dev.sigstore.fulcio.v2.GetChallengeRequest\x1a!.dev.sigstore.fulcio.v2.Challenge\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/api/v2/challenge\x12\xab\x01\n" +
+	"\x16ListIssuedCertificates\x125.dev.sigstore.fulcio.v2.ListIssuedCertificatesRequest\x1a6.dev.sigstore.fulcio.v2.ListIssuedCertificatesResponse\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/api/v2/issuedCertificates\x12\x99\x01\n" +
+	"\x14GetIssuedCertificate\x123.dev.sigstore.fulcio.v2.GetIssuedCertificateRequest\x1a).dev.sigstore.fulcio.v2.IssuedCertificate\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/api/v2/issuedCertificateB\x8f\x03\x92A\xb1\x02\x12\xb9\x01\n" +
 	"\x06Fulcio\"\\\n" +
 	"\x17sigstore Fulcio project\x12\"https://github.com/sigstore/fulcio\x1a\x1dsigstore-dev@googlegroups.com*J\n" +
 	"\x12Apache License 2.0\x124https://github.com/sigstore/fulcio/blob/main/LICENSE2\x052.0.0\x1a\x13fulcio.sigstore.dev*\x01\x012\x10application/json:\x10application/jsonr7\n" +
@@ -999,7 +1516,7 @@ func file_fulcio_proto_rawDescGZIP() []byte {
 }
 
 var file_fulcio_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_fulcio_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_fulcio_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_fulcio_proto_goTypes = []any{
 	(PublicKeyAlgorithm)(0),                 // 0: dev.sigstore.fulcio.v2.PublicKeyAlgorithm
 	(*CreateSigningCertificateRequest)(nil), // 1: dev.sigstore.fulcio.v2.CreateSigningCertificateRequest
@@ -1015,29 +1532,53 @@ var file_fulcio_proto_goTypes = []any{
 	(*GetConfigurationRequest)(nil),         // 11: dev.sigstore.fulcio.v2.GetConfigurationRequest
 	(*Configuration)(nil),                   // 12: dev.sigstore.fulcio.v2.Configuration
 	(*OIDCIssuer)(nil),                      // 13: dev.sigstore.fulcio.v2.OIDCIssuer
+	(*GetChallengeRequest)(nil),             // 14: dev.sigstore.fulcio.v2.GetChallengeRequest
+	(*Challenge)(nil),                       // 15: dev.sigstore.fulcio.v2.Challenge
+	(*ListIssuedCertificatesRequest)(nil),   // 16: dev.sigstore.fulcio.v2.ListIssuedCertificatesRequest
+	(*ListIssuedCertificatesResponse)(nil),  // 17: dev.sigstore.fulcio.v2.ListIssuedCertificatesResponse
+	(*GetIssuedCertificateRequest)(nil),     // 18: dev.sigstore.fulcio.v2.GetIssuedCertificateRequest
+	(*IssuedCertificate)(nil),               // 19: dev.sigstore.fulcio.v2.IssuedCertificate
+	nil,                                     // 20: dev.sigstore.fulcio.v2.IssuedCertificate.ExtensionsEntry
+	(*durationpb.Duration)(nil),             // 21: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),           // 22: google.protobuf.Timestamp
 }
 var file_fulcio_proto_depIdxs = []int32{
 	2,  // 0: dev.sigstore.fulcio.v2.CreateSigningCertificateRequest.credentials:type_name -> dev.sigstore.fulcio.v2.Credentials
 	3,  // 1: dev.sigstore.fulcio.v2.CreateSigningCertificateRequest.public_key_request:type_name -> dev.sigstore.fulcio.v2.PublicKeyRequest
-	4,  // 2: dev.sigstore.fulcio.v2.PublicKeyRequest.public_key:type_name -> dev.sigstore.fulcio.v2.PublicKey
-	0,  // 3: dev.sigstore.fulcio.v2.PublicKey.algorithm:type_name -> dev.sigstore.fulcio.v2.PublicKeyAlgorithm
-	6,  // 4: dev.sigstore.fulcio.v2.SigningCertificate.signed_certificate_detached_sct:type_name -> dev.sigstore.fulcio.v2.SigningCertificateDetachedSCT
-	7,  // 5: dev.sigstore.fulcio.v2.SigningCertificate.signed_certificate_embedded_sct:type_name -> dev.sigstore.fulcio.v2.SigningCertificateEmbeddedSCT
-	10, // 6: dev.sigstore.fulcio.v2.SigningCertificateDetachedSCT.chain:type_name -> dev.sigstore.fulcio.v2.CertificateChain
-	10, // 7: dev.sigstore.fulcio.v2.SigningCertificateEmbeddedSCT.chain:type_name -> dev.sigstore.fulcio.v2.CertificateChain
-	10, // 8: dev.sigstore.fulcio.v2.TrustBundle.chains:type_name -> dev.sigstore.fulcio.v2.CertificateChain
-	13, // 9: dev.sigstore.fulcio.v2.Configuration.issuers:type_name -> dev.sigstore.fulcio.v2.OIDCIssuer
-	1,  // 10: dev.sigstore.fulcio.v2.CA.CreateSigningCertificate:input_type -> dev.sigstore.fulcio.v2.CreateSigningCertificateRequest
-	8,  // 11: dev.sigstore.fulcio.v2.CA.GetTrustBundle:input_type -> dev.sigstore.fulcio.v2.GetTrustBundleRequest
-	11, // 12: dev.sigstore.fulcio.v2.CA.GetConfiguration:input_type -> dev.sigstore.fulcio.v2.GetConfigurationRequest
-	5,  // 13: dev.sigstore.fulcio.v2.CA.CreateSigningCertificate:output_type -> dev.sigstore.fulcio.v2.SigningCertificate
-	9,  // 14: dev.sigstore.fulcio.v2.CA.GetTrustBundle:output_type -> dev.sigstore.fulcio.v2.TrustBundle
-	12, // 15: dev.sigstore.fulcio.v2.CA.GetConfiguration:output_type -> dev.sigstore.fulcio.v2.Configuration
-	13, // [13:16] is the sub-list for method output_type
-	10, // [10:13] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	21, // 2: dev.sigstore.fulcio.v2.CreateSigningCertificateRequest.requested_validity:type_name -> google.protobuf.Duration
+	4,  // 3: dev.sigstore.fulcio.v2.PublicKeyRequest.public_key:type_name -> dev.sigstore.fulcio.v2.PublicKey
+	0,  // 4: dev.sigstore.fulcio.v2.PublicKey.algorithm:type_name -> dev.sigstore.fulcio.v2.PublicKeyAlgorithm
+	6,  // 5: dev.sigstore.fulcio.v2.SigningCertificate.signed_certificate_detached_sct:type_name -> dev.sigstore.fulcio.v2.SigningCertificateDetachedSCT
+	7,  // 6: dev.sigstore.fulcio.v2.SigningCertificate.signed_certificate_embedded_sct:type_name -> dev.sigstore.fulcio.v2.SigningCertificateEmbeddedSCT
+	10, // 7: dev.sigstore.fulcio.v2.SigningCertificateDetachedSCT.chain:type_name -> dev.sigstore.fulcio.v2.CertificateChain
+	10, // 8: dev.sigstore.fulcio.v2.SigningCertificateEmbeddedSCT.chain:type_name -> dev.sigstore.fulcio.v2.CertificateChain
+	10, // 9: dev.sigstore.fulcio.v2.TrustBundle.chains:type_name -> dev.sigstore.fulcio.v2.CertificateChain
+	13, // 10: dev.sigstore.fulcio.v2.Configuration.issuers:type_name -> dev.sigstore.fulcio.v2.OIDCIssuer
+	22, // 11: dev.sigstore.fulcio.v2.Challenge.expires_at:type_name -> google.protobuf.Timestamp
+	22, // 12: dev.sigstore.fulcio.v2.ListIssuedCertificatesRequest.issued_after:type_name -> google.protobuf.Timestamp
+	22, // 13: dev.sigstore.fulcio.v2.ListIssuedCertificatesRequest.issued_before:type_name -> google.protobuf.Timestamp
+	19, // 14: dev.sigstore.fulcio.v2.ListIssuedCertificatesResponse.certificates:type_name -> dev.sigstore.fulcio.v2.IssuedCertificate
+	20, // 15: dev.sigstore.fulcio.v2.IssuedCertificate.extensions:type_name -> dev.sigstore.fulcio.v2.IssuedCertificate.ExtensionsEntry
+	22, // 16: dev.sigstore.fulcio.v2.IssuedCertificate.issued_at:type_name -> google.protobuf.Timestamp
+	22, // 17: dev.sigstore.fulcio.v2.IssuedCertificate.not_before:type_name -> google.protobuf.Timestamp
+	22, // 18: dev.sigstore.fulcio.v2.IssuedCertificate.not_after:type_name -> google.protobuf.Timestamp
+	1,  // 19: dev.sigstore.fulcio.v2.CA.CreateSigningCertificate:input_type -> dev.sigstore.fulcio.v2.CreateSigningCertificateRequest
+	8,  // 20: dev.sigstore.fulcio.v2.CA.GetTrustBundle:input_type -> dev.sigstore.fulcio.v2.GetTrustBundleRequest
+	11, // 21: dev.sigstore.fulcio.v2.CA.GetConfiguration:input_type -> dev.sigstore.fulcio.v2.GetConfigurationRequest
+	14, // 22: dev.sigstore.fulcio.v2.CA.GetChallenge:input_type -> dev.sigstore.fulcio.v2.GetChallengeRequest
+	16, // 23: dev.sigstore.fulcio.v2.CA.ListIssuedCertificates:input_type -> dev.sigstore.fulcio.v2.ListIssuedCertificatesRequest
+	18, // 24: dev.sigstore.fulcio.v2.CA.GetIssuedCertificate:input_type -> dev.sigstore.fulcio.v2.GetIssuedCertificateRequest
+	5,  // 25: dev.sigstore.fulcio.v2.CA.CreateSigningCertificate:output_type -> dev.sigstore.fulcio.v2.SigningCertificate
+	9,  // 26: dev.sigstore.fulcio.v2.CA.GetTrustBundle:output_type -> dev.sigstore.fulcio.v2.TrustBundle
+	12, // 27: dev.sigstore.fulcio.v2.CA.GetConfiguration:output_type -> dev.sigstore.fulcio.v2.Configuration
+	15, // 28: dev.sigstore.fulcio.v2.CA.GetChallenge:output_type -> dev.sigstore.fulcio.v2.Challenge
+	17, // 29: dev.sigstore.fulcio.v2.CA.ListIssuedCertificates:output_type -> dev.sigstore.fulcio.v2.ListIssuedCertificatesResponse
+	19, // 30: dev.sigstore.fulcio.v2.CA.GetIssuedCertificate:output_type -> dev.sigstore.fulcio.v2.IssuedCertificate
+	25, // [25:31] is the sub-list for method output_type
+	19, // [19:25] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
 }
 
 func init() { file_fulcio_proto_init() }
@@ -1066,7 +1607,7 @@ func file_fulcio_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_fulcio_proto_rawDesc), len(file_fulcio_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   13,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -37,6 +37,9 @@ const (
 	CA_CreateSigningCertificate_FullMethodName = "/dev.sigstore.fulcio.v2.CA/CreateSigningCertificate"
 	CA_GetTrustBundle_FullMethodName           = "/dev.sigstore.fulcio.v2.CA/GetTrustBundle"
 	CA_GetConfiguration_FullMethodName         = "/dev.sigstore.fulcio.v2.CA/GetConfiguration"
+	CA_GetChallenge_FullMethodName             = "/dev.sigstore.fulcio.v2.CA/GetChallenge"
+	CA_ListIssuedCertificates_FullMethodName   = "/dev.sigstore.fulcio.v2.CA/ListIssuedCertificates"
+	CA_GetIssuedCertificate_FullMethodName     = "/dev.sigstore.fulcio.v2.CA/GetIssuedCertificate"
 )
 
 // CAClient is the client API for CA service.
@@ -52,6 +55,15 @@ type CAClient interface {
 	// *
 	// Returns the configuration of supported OIDC issuers, including the required challenge for each issuer.
 	GetConfiguration(ctx context.Context, in *GetConfigurationRequest, opts ...grpc.CallOption) (*Configuration, error)
+	// *
+	// Returns a short-lived, server-issued nonce to be signed as proof of possession of a private key
+	GetChallenge(ctx context.Context, in *GetChallengeRequest, opts ...grpc.CallOption) (*Challenge, error)
+	// *
+	// Returns the certificates recorded in the issuance ledger that match the request, most recently issued first. Requires an administrator's identity token in the Authorization header
+	ListIssuedCertificates(ctx context.Context, in *ListIssuedCertificatesRequest, opts ...grpc.CallOption) (*ListIssuedCertificatesResponse, error)
+	// *
+	// Returns the certificate with the given serial number from the issuance ledger. Requires an administrator's identity token in the Authorization header
+	GetIssuedCertificate(ctx context.Context, in *GetIssuedCertificateRequest, opts ...grpc.CallOption) (*IssuedCertificate, error)
 }
 
 type cAClient struct {
@@ -92,6 +104,36 @@ func (c *cAClient) GetConfiguration(ctx context.Context, in *GetConfigurationReq
 	return out, nil
 }
 
+func (c *cAClient) GetChallenge(ctx context.Context, in *GetChallengeRequest, opts ...grpc.CallOption) (*Challenge, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Challenge)
+	err := c.cc.Invoke(ctx, CA_GetChallenge_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cAClient) ListIssuedCertificates(ctx context.Context, in *ListIssuedCertificatesRequest, opts ...grpc.CallOption) (*ListIssuedCertificatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListIssuedCertificatesResponse)
+	err := c.cc.Invoke(ctx, CA_ListIssuedCertificates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cAClient) GetIssuedCertificate(ctx context.Context, in *GetIssuedCertificateRequest, opts ...grpc.CallOption) (*IssuedCertificate, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IssuedCertificate)
+	err := c.cc.Invoke(ctx, CA_GetIssuedCertificate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CAServer is the server API for CA service.
 // All implementations must embed UnimplementedCAServer
 // for forward compatibility.
@@ -105,6 +147,15 @@ type CAServer interface {
 	// *
 	// Returns the configuration of supported OIDC issuers, including the required challenge for each issuer.
 	GetConfiguration(context.Context, *GetConfigurationRequest) (*Configuration, error)
+	// *
+	// Returns a short-lived, server-issued nonce to be signed as proof of possession of a private key
+	GetChallenge(context.Context, *GetChallengeRequest) (*Challenge, error)
+	// *
+	// Returns the certificates recorded in the issuance ledger that match the request, most recently issued first. Requires an administrator's identity token in the Authorization header
+	ListIssuedCertificates(context.Context, *ListIssuedCertificatesRequest) (*ListIssuedCertificatesResponse, error)
+	// *
+	// Returns the certificate with the given serial number from the issuance ledger. Requires an administrator's identity token in the Authorization header
+	GetIssuedCertificate(context.Context, *GetIssuedCertificateRequest) (*IssuedCertificate, error)
 	mustEmbedUnimplementedCAServer()
 }
 
@@ -124,6 +175,15 @@ func (UnimplementedCAServer) GetTrustBundle(context.Context, *GetTrustBundleRequ
 func (UnimplementedCAServer) GetConfiguration(context.Context, *GetConfigurationRequest) (*Configuration, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetConfiguration not implemented")
 }
+func (UnimplementedCAServer) GetChallenge(context.Context, *GetChallengeRequest) (*Challenge, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetChallenge not implemented")
+}
+func (UnimplementedCAServer) ListIssuedCertificates(context.Context, *ListIssuedCertificatesRequest) (*ListIssuedCertificatesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListIssuedCertificates not implemented")
+}
+func (UnimplementedCAServer) GetIssuedCertificate(context.Context, *GetIssuedCertificateRequest) (*IssuedCertificate, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetIssuedCertificate not implemented")
+}
 func (UnimplementedCAServer) mustEmbedUnimplementedCAServer() {}
 func (UnimplementedCAServer) testEmbeddedByValue()            {}
 
@@ -199,6 +259,60 @@ func _CA_GetConfiguration_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CA_GetChallenge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChallengeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CAServer).GetChallenge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CA_GetChallenge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CAServer).GetChallenge(ctx, req.(*GetChallengeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CA_ListIssuedCertificates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListIssuedCertificatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CAServer).ListIssuedCertificates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CA_ListIssuedCertificates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CAServer).ListIssuedCertificates(ctx, req.(*ListIssuedCertificatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CA_GetIssuedCertificate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIssuedCertificateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CAServer).GetIssuedCertificate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CA_GetIssuedCertificate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CAServer).GetIssuedCertificate(ctx, req.(*GetIssuedCertificateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CA_ServiceDesc is the grpc.ServiceDesc for CA service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -218,6 +332,18 @@ var CA_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetConfiguration",
 			Handler:    _CA_GetConfiguration_Handler,
 		},
+		{
+			MethodName: "GetChallenge",
+			Handler:    _CA_GetChallenge_Handler,
+		},
+		{
+			MethodName: "ListIssuedCertificates",
+			Handler:    _CA_ListIssuedCertificates_Handler,
+		},
+		{
+			MethodName: "GetIssuedCertificate",
+			Handler:    _CA_GetIssuedCertificate_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "fulcio.proto",
@@ -0,0 +1,237 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package health periodically checks the components Fulcio depends on, such
+// as the CA signer and the CT logs, so that a replica that cannot issue
+// certificates is taken out of service.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sigstore/fulcio/pkg/log"
+)
+
+const (
+	// DefaultInterval is how often the checks are evaluated.
+	DefaultInterval = 30 * time.Second
+	// DefaultTimeout bounds each evaluation of a check.
+	DefaultTimeout = 10 * time.Second
+)
+
+var metricCheckHealthy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "fulcio_health_check_healthy",
+	Help: "Whether each health check passed when last evaluated",
+}, []string{"check"})
+
+// Check is a component that is checked periodically.
+type Check struct {
+	// Name identifies the check, and is the gRPC health service name of the
+	// component.
+	Name string
+	// Func returns an error if the component is unhealthy.
+	Func func(context.Context) error
+	// Optional checks are reported, but do not affect whether the monitor
+	// is healthy.
+	Optional bool
+}
+
+// Status is the result of the last evaluation of a check.
+type Status struct {
+	// Checked is when the check was last evaluated, or zero if it has not
+	// been evaluated yet.
+	Checked time.Time
+	// Err is the error returned by the check.
+	Err error
+}
+
+// Healthy reports whether the check has been evaluated and passed.
+func (s Status) Healthy() bool {
+	return !s.Checked.IsZero() && s.Err == nil
+}
+
+// Monitor evaluates checks in the background and reports their status.
+type Monitor struct {
+	checks   []Check
+	interval time.Duration
+	timeout  time.Duration
+
+	mu          sync.Mutex
+	statuses    map[string]Status
+	subscribers map[chan struct{}]struct{}
+}
+
+// MonitorOption configures the Monitor returned by NewMonitor.
+type MonitorOption func(*Monitor)
+
+// WithInterval sets how often the checks are evaluated. Defaults to
+// DefaultInterval.
+func WithInterval(d time.Duration) MonitorOption {
+	return func(m *Monitor) {
+		m.interval = d
+	}
+}
+
+// WithTimeout sets how long each evaluation of a check may take. Defaults
+// to DefaultTimeout.
+func WithTimeout(d time.Duration) MonitorOption {
+	return func(m *Monitor) {
+		m.timeout = d
+	}
+}
+
+// NewMonitor returns a Monitor of checks. Checks are not evaluated until
+// Run is called, and are unhealthy until then.
+func NewMonitor(checks []Check, opts ...MonitorOption) (*Monitor, error) {
+	m := &Monitor{
+		checks:      checks,
+		interval:    DefaultInterval,
+		timeout:     DefaultTimeout,
+		statuses:    make(map[string]Status, len(checks)),
+		subscribers: map[chan struct{}]struct{}{},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.interval <= 0 {
+		return nil, fmt.Errorf("health check interval must be positive, got %v", m.interval)
+	}
+	if m.timeout <= 0 {
+		return nil, fmt.Errorf("health check timeout must be positive, got %v", m.timeout)
+	}
+	for _, c := range checks {
+		if c.Name == "" || c.Func == nil {
+			return nil, errors.New("health checks require a name and a function")
+		}
+		if _, ok := m.statuses[c.Name]; ok {
+			return nil, fmt.Errorf("duplicate health check %q", c.Name)
+		}
+		m.statuses[c.Name] = Status{}
+		metricCheckHealthy.WithLabelValues(c.Name).Set(0)
+	}
+	return m, nil
+}
+
+// Run evaluates the checks immediately and then every interval, until ctx
+// is done.
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		m.Evaluate(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Evaluate evaluates every check concurrently and records their status.
+func (m *Monitor) Evaluate(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, c := range m.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, m.timeout)
+			defer cancel()
+			m.record(c.Name, Status{Checked: time.Now(), Err: c.Func(ctx)})
+		}()
+	}
+	wg.Wait()
+}
+
+// record stores the status of a check, notifying subscribers if whether it
+// is healthy changed.
+func (m *Monitor) record(name string, s Status) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	prev := m.statuses[name]
+	m.statuses[name] = s
+
+	if s.Healthy() {
+		metricCheckHealthy.WithLabelValues(name).Set(1)
+	} else {
+		metricCheckHealthy.WithLabelValues(name).Set(0)
+	}
+	if s.Healthy() == prev.Healthy() && !prev.Checked.IsZero() {
+		return
+	}
+	if s.Healthy() {
+		log.Logger.Infof("health check %s passed", name)
+	} else {
+		log.Logger.Warnf("health check %s failed: %v", name, s.Err)
+	}
+	for ch := range m.subscribers {
+		// Subscribers only need to know that something changed
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Names returns the names of the checks, sorted.
+func (m *Monitor) Names() []string {
+	names := make([]string, 0, len(m.checks))
+	for _, c := range m.checks {
+		names = append(names, c.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Status returns the status of the named check, and whether it exists.
+func (m *Monitor) Status(name string) (Status, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.statuses[name]
+	return s, ok
+}
+
+// Healthy reports whether every check that is not optional passed when
+// last evaluated.
+func (m *Monitor) Healthy() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, c := range m.checks {
+		if !c.Optional && !m.statuses[c.Name].Healthy() {
+			return false
+		}
+	}
+	return true
+}
+
+// Subscribe returns a channel that receives a value whenever a check
+// becomes healthy or unhealthy, and a function that stops the
+// subscription.
+func (m *Monitor) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscribers[ch] = struct{}{}
+	return ch, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		delete(m.subscribers, ch)
+	}
+}
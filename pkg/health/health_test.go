@@ -0,0 +1,128 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMonitor(t *testing.T) {
+	var signerErr atomic.Pointer[error]
+	m, err := NewMonitor([]Check{{
+		Name: "ca",
+		Func: func(context.Context) error {
+			if err := signerErr.Load(); err != nil {
+				return *err
+			}
+			return nil
+		},
+	}, {
+		Name:     "oidc-discovery",
+		Func:     func(context.Context) error { return errors.New("discovery failed") },
+		Optional: true,
+	}})
+	if err != nil {
+		t.Fatalf("NewMonitor() = %v", err)
+	}
+	if m.Healthy() {
+		t.Error("expected a monitor to be unhealthy before its checks are evaluated")
+	}
+	if got := m.Names(); len(got) != 2 || got[0] != "ca" || got[1] != "oidc-discovery" {
+		t.Errorf("unexpected names %v", got)
+	}
+
+	changes, stop := m.Subscribe()
+	defer stop()
+	m.Evaluate(context.Background())
+	if !m.Healthy() {
+		t.Error("expected a failed optional check not to make the monitor unhealthy")
+	}
+	if s, ok := m.Status("oidc-discovery"); !ok || s.Healthy() || s.Err == nil || s.Checked.IsZero() {
+		t.Errorf("unexpected status %+v", s)
+	}
+	select {
+	case <-changes:
+	default:
+		t.Error("expected a change on the first evaluation")
+	}
+
+	// Evaluations that change nothing are not notified
+	m.Evaluate(context.Background())
+	select {
+	case <-changes:
+		t.Error("unexpected change")
+	default:
+	}
+
+	failure := errors.New("HSM session closed")
+	signerErr.Store(&failure)
+	m.Evaluate(context.Background())
+	if m.Healthy() {
+		t.Error("expected a failed check to make the monitor unhealthy")
+	}
+	if s, _ := m.Status("ca"); !errors.Is(s.Err, failure) {
+		t.Errorf("unexpected status %+v", s)
+	}
+	select {
+	case <-changes:
+	default:
+		t.Error("expected a change when a check fails")
+	}
+	if _, ok := m.Status("unknown"); ok {
+		t.Error("expected no status for an unknown check")
+	}
+}
+
+func TestMonitorTimeout(t *testing.T) {
+	m, err := NewMonitor([]Check{{
+		Name: "ct-log",
+		Func: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}}, WithTimeout(time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewMonitor() = %v", err)
+	}
+	m.Evaluate(context.Background())
+	if s, _ := m.Status("ct-log"); !errors.Is(s.Err, context.DeadlineExceeded) {
+		t.Errorf("expected the check to time out, got %+v", s)
+	}
+}
+
+func TestNewMonitor(t *testing.T) {
+	check := func(context.Context) error { return nil }
+	tests := map[string]struct {
+		checks []Check
+		opts   []MonitorOption
+	}{
+		"no name":          {checks: []Check{{Func: check}}},
+		"no function":      {checks: []Check{{Name: "ca"}}},
+		"duplicate":        {checks: []Check{{Name: "ca", Func: check}, {Name: "ca", Func: check}}},
+		"zero interval":    {opts: []MonitorOption{WithInterval(0)}},
+		"negative timeout": {opts: []MonitorOption{WithTimeout(-time.Second)}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewMonitor(test.checks, test.opts...); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -21,21 +21,24 @@ import (
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/sigstore/fulcio/pkg/config"
+	"github.com/sigstore/fulcio/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // We do this to bypass needing actual OIDC tokens for unit testing.
 var Authorize = actualAuthorize
 
 func actualAuthorize(ctx context.Context, token string, opts ...config.InsecureOIDCConfigOption) (*oidc.IDToken, error) {
-	issuer, err := extractIssuerURL(token)
+	issuer, err := ExtractIssuerURL(token)
 	if err != nil {
 		return nil, err
 	}
 
-	verifier, ok := config.FromContext(ctx).GetVerifier(issuer, opts...)
+	verifier, ok := config.FromContext(ctx).GetVerifierContext(ctx, issuer, opts...)
 	if !ok {
 		return nil, fmt.Errorf("unsupported issuer: %s", issuer)
 	}
+	ctx, span := tracing.Start(ctx, "oidc.verify", attribute.String("oidc.issuer", issuer))
 	var idToken *oidc.IDToken
 	var verifyErr error
 	// RHTAS FIPS - DO NOT REMOVE
@@ -48,5 +51,6 @@ func actualAuthorize(ctx context.Context, token string, opts ...config.InsecureO
 		idToken, verifyErr = verifier.Verify(ctx, token)
 	})
 	// ========================================
+	tracing.End(span, verifyErr)
 	return idToken, verifyErr
 }
@@ -27,7 +27,7 @@ import (
 type IssuerPool []Issuer
 
 func (p IssuerPool) Authenticate(ctx context.Context, token string, opts ...config.InsecureOIDCConfigOption) (Principal, error) {
-	url, err := extractIssuerURL(token)
+	url, err := ExtractIssuerURL(token)
 	if err != nil {
 		return nil, err
 	}
@@ -40,15 +40,11 @@ func (p IssuerPool) Authenticate(ctx context.Context, token string, opts ...conf
 	return nil, fmt.Errorf("failed to match issuer URL %s from token with any configured providers", url)
 }
 
-func extractIssuerURL(token string) (string, error) {
-	if strings.Count(token, ".") != 2 {
-		return "", fmt.Errorf("oidc: malformed jwt, token must have 3 parts")
-	}
-
-	parts := strings.SplitN(token, ".", 3)
-	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+// ExtractIssuerURL returns the unverified issuer claim of an OIDC token.
+func ExtractIssuerURL(token string) (string, error) {
+	raw, err := tokenPayload(token)
 	if err != nil {
-		return "", fmt.Errorf("oidc: malformed jwt payload: %w", err)
+		return "", err
 	}
 
 	var payload struct {
@@ -59,3 +55,31 @@ func extractIssuerURL(token string) (string, error) {
 	}
 	return payload.Issuer, nil
 }
+
+// ExtractClaims returns the unverified claims of an OIDC token.
+func ExtractClaims(token string) (map[string]any, error) {
+	raw, err := tokenPayload(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return nil, fmt.Errorf("oidc: failed to unmarshal claims: %w", err)
+	}
+	return claims, nil
+}
+
+// tokenPayload returns the decoded payload of a JWT.
+func tokenPayload(token string) ([]byte, error) {
+	if strings.Count(token, ".") != 2 {
+		return nil, fmt.Errorf("oidc: malformed jwt, token must have 3 parts")
+	}
+
+	parts := strings.SplitN(token, ".", 3)
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("oidc: malformed jwt payload: %w", err)
+	}
+	return raw, nil
+}
@@ -0,0 +1,240 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ledger
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// bucketCertificates maps serial numbers to JSON-encoded records
+	bucketCertificates = []byte("certificates")
+	// The index buckets hold keys of the form
+	// [<value> 0x00] <big-endian issuance time in ns> <serial number>, so
+	// that the records of a value are ordered by issuance time
+	bucketByTime     = []byte("by-time")
+	bucketByIdentity = []byte("by-identity")
+	bucketByIssuer   = []byte("by-issuer")
+)
+
+// BoltStore is a Store in a local bbolt database file. The file can only be
+// opened by one process at a time, so replicas each need their own ledger.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+var _ Store = (*BoltStore)(nil)
+
+// NewBoltStore opens the ledger in the file at path, creating it if needed.
+func NewBoltStore(path string) (*BoltStore, error) {
+	// Fail rather than wait if another process holds the file
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("opening issuance ledger %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{bucketCertificates, bucketByTime, bucketByIdentity, bucketByIssuer} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("initializing issuance ledger %s: %w", path, err)
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Put records a newly issued certificate.
+func (s *BoltStore) Put(ctx context.Context, r *Record) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if r.SerialNumber == "" {
+		return errors.New("record has no serial number")
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		certs := tx.Bucket(bucketCertificates)
+		if certs.Get([]byte(r.SerialNumber)) != nil {
+			return fmt.Errorf("certificate %s is already recorded", r.SerialNumber)
+		}
+		if err := certs.Put([]byte(r.SerialNumber), data); err != nil {
+			return err
+		}
+		if err := tx.Bucket(bucketByTime).Put(indexKey(nil, r), nil); err != nil {
+			return err
+		}
+		if err := tx.Bucket(bucketByIdentity).Put(indexKey(indexPrefix(r.Identity), r), nil); err != nil {
+			return err
+		}
+		return tx.Bucket(bucketByIssuer).Put(indexKey(indexPrefix(r.Issuer), r), nil)
+	})
+}
+
+// Get returns the record of the certificate with the serial number.
+func (s *BoltStore) Get(ctx context.Context, serialNumber string) (*Record, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var r *Record
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		r, err = getRecord(tx, []byte(serialNumber))
+		return err
+	})
+	return r, err
+}
+
+// List returns the records selected by q, most recently issued first.
+func (s *BoltStore) List(ctx context.Context, q Query) ([]*Record, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+	if q.SerialNumber != "" {
+		// At most one record matches, so there is never a next page
+		if q.PageToken != "" {
+			return nil, "", nil
+		}
+		r, err := s.Get(ctx, q.SerialNumber)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			return nil, "", nil
+		case err != nil:
+			return nil, "", err
+		case !q.Matches(r):
+			return nil, "", nil
+		}
+		return []*Record{r}, "", nil
+	}
+
+	// Scan the most selective index, filtering by the rest of the query
+	bucket, prefix := bucketByTime, []byte(nil)
+	switch {
+	case q.Identity != "":
+		bucket, prefix = bucketByIdentity, indexPrefix(q.Identity)
+	case q.Issuer != "":
+		bucket, prefix = bucketByIssuer, indexPrefix(q.Issuer)
+	}
+
+	// The scan starts before limit and works backwards in time
+	var limit []byte
+	switch {
+	case q.PageToken != "":
+		token, err := base64.RawURLEncoding.DecodeString(q.PageToken)
+		if err != nil || !bytes.HasPrefix(token, prefix) || len(token) < len(prefix)+8 {
+			return nil, "", ErrInvalidPageToken
+		}
+		limit = token
+	case !q.IssuedBefore.IsZero():
+		limit = binary.BigEndian.AppendUint64(bytes.Clone(prefix), uint64(max(q.IssuedBefore.UnixNano(), 0)))
+	case prefix != nil:
+		// The first key after those with the prefix
+		limit = bytes.Clone(prefix)
+		limit[len(limit)-1]++
+	}
+	var after uint64
+	if !q.IssuedAfter.IsZero() {
+		after = uint64(max(q.IssuedAfter.UnixNano(), 0))
+	}
+
+	size := q.pageSize()
+	var records []*Record
+	var keys [][]byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucket).Cursor()
+		for k, _ := seekBefore(c, limit); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Prev() {
+			if len(k) < len(prefix)+8 || binary.BigEndian.Uint64(k[len(prefix):]) < after {
+				break
+			}
+			r, err := getRecord(tx, k[len(prefix)+8:])
+			if err != nil {
+				return err
+			}
+			if !q.Matches(r) {
+				continue
+			}
+			records = append(records, r)
+			keys = append(keys, bytes.Clone(k))
+			// Look for one more record to tell whether there is a next page
+			if len(records) > size {
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if len(records) > size {
+		return records[:size], base64.RawURLEncoding.EncodeToString(keys[size-1]), nil
+	}
+	return records, "", nil
+}
+
+// Close closes the database file.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+func getRecord(tx *bolt.Tx, serialNumber []byte) (*Record, error) {
+	data := tx.Bucket(bucketCertificates).Get(serialNumber)
+	if data == nil {
+		return nil, ErrNotFound
+	}
+	var r Record
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("decoding record of certificate %s: %w", serialNumber, err)
+	}
+	return &r, nil
+}
+
+// indexPrefix returns the prefix of the index keys of value.
+func indexPrefix(value string) []byte {
+	return append([]byte(value), 0)
+}
+
+// indexKey returns the key of r in an index, given the prefix of the
+// indexed value.
+func indexKey(prefix []byte, r *Record) []byte {
+	k := binary.BigEndian.AppendUint64(bytes.Clone(prefix), uint64(max(r.IssuedAt.UnixNano(), 0)))
+	return append(k, r.SerialNumber...)
+}
+
+// seekBefore moves c to the last key before limit, or to the last key if
+// limit is nil.
+func seekBefore(c *bolt.Cursor, limit []byte) ([]byte, []byte) {
+	if limit == nil {
+		return c.Last()
+	}
+	if k, _ := c.Seek(limit); k == nil {
+		return c.Last()
+	}
+	return c.Prev()
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBoltStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "ledger.db")
+	s, err := NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("NewBoltStore() = %v", err)
+	}
+
+	// Records 1 to 6 are issued a minute apart, alternating between two
+	// identities and issuers
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 1; i <= 6; i++ {
+		r := &Record{
+			SerialNumber: fmt.Sprintf("%x", i),
+			Identity:     []string{"alice@example.com", "bob@example.com"}[i%2],
+			Issuer:       []string{"https://a", "https://b"}[i%3%2],
+			IssuedAt:     start.Add(time.Duration(i) * time.Minute),
+		}
+		if err := s.Put(ctx, r); err != nil {
+			t.Fatalf("Put() = %v", err)
+		}
+	}
+	if err := s.Put(ctx, &Record{SerialNumber: "1"}); err == nil {
+		t.Error("expected error recording a certificate twice")
+	}
+
+	// Records survive reopening the file
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	s, err = NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("NewBoltStore() = %v", err)
+	}
+	defer s.Close()
+
+	r, err := s.Get(ctx, "3")
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if r.Identity != "bob@example.com" || !r.IssuedAt.Equal(start.Add(3*time.Minute)) {
+		t.Errorf("unexpected record %+v", r)
+	}
+	if _, err := s.Get(ctx, "7"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	for name, test := range map[string]struct {
+		q    Query
+		want []string
+	}{
+		"all":                   {Query{}, []string{"6", "5", "4", "3", "2", "1"}},
+		"serial number":         {Query{SerialNumber: "4"}, []string{"4"}},
+		"unknown serial number": {Query{SerialNumber: "7"}, nil},
+		"serial number and other identity": {
+			Query{SerialNumber: "4", Identity: "bob@example.com"}, nil,
+		},
+		"identity": {Query{Identity: "alice@example.com"}, []string{"6", "4", "2"}},
+		"identity sharing a prefix": {
+			Query{Identity: "alice@example.co"}, nil,
+		},
+		"issuer":              {Query{Issuer: "https://a"}, []string{"6", "5", "3", "2"}},
+		"identity and issuer": {Query{Identity: "alice@example.com", Issuer: "https://a"}, []string{"6", "2"}},
+		"time range": {
+			Query{IssuedAfter: start.Add(2 * time.Minute), IssuedBefore: start.Add(5 * time.Minute)},
+			[]string{"4", "3", "2"},
+		},
+		"identity and time range": {
+			Query{Identity: "bob@example.com", IssuedAfter: start.Add(2 * time.Minute), IssuedBefore: start.Add(5 * time.Minute)},
+			[]string{"3"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			records, next, err := s.List(ctx, test.q)
+			if err != nil {
+				t.Fatalf("List() = %v", err)
+			}
+			if next != "" {
+				t.Errorf("unexpected next page token %q", next)
+			}
+			if got := serialNumbers(records); !slices.Equal(got, test.want) {
+				t.Errorf("List() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestBoltStorePaging(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewBoltStore(filepath.Join(t.TempDir(), "ledger.db"))
+	if err != nil {
+		t.Fatalf("NewBoltStore() = %v", err)
+	}
+	defer s.Close()
+	start := time.Now()
+	for i := 1; i <= 5; i++ {
+		r := &Record{SerialNumber: fmt.Sprintf("%x", i), Identity: "alice@example.com", IssuedAt: start.Add(time.Duration(i) * time.Second)}
+		if err := s.Put(ctx, r); err != nil {
+			t.Fatalf("Put() = %v", err)
+		}
+	}
+
+	for _, q := range []Query{{}, {Identity: "alice@example.com"}} {
+		var got []string
+		q.PageSize = 2
+		for pages := 0; ; pages++ {
+			records, next, err := s.List(ctx, q)
+			if err != nil {
+				t.Fatalf("List() = %v", err)
+			}
+			got = append(got, serialNumbers(records)...)
+			if next == "" {
+				if pages != 2 {
+					t.Errorf("expected 3 pages, got %d", pages+1)
+				}
+				break
+			}
+			q.PageToken = next
+		}
+		if want := []string{"5", "4", "3", "2", "1"}; !slices.Equal(got, want) {
+			t.Errorf("List() = %v, want %v", got, want)
+		}
+	}
+
+	// A token for one index is not valid for another
+	_, next, err := s.List(ctx, Query{PageSize: 1})
+	if err != nil {
+		t.Fatalf("List() = %v", err)
+	}
+	if _, _, err := s.List(ctx, Query{Identity: "alice@example.com", PageToken: next}); !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("expected ErrInvalidPageToken, got %v", err)
+	}
+	if _, _, err := s.List(ctx, Query{PageToken: "!"}); !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("expected ErrInvalidPageToken, got %v", err)
+	}
+}
+
+func serialNumbers(records []*Record) []string {
+	var serials []string
+	for _, r := range records {
+		serials = append(serials, r.SerialNumber)
+	}
+	return serials
+}
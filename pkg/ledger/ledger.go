@@ -0,0 +1,172 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ledger records the certificates Fulcio issues, so that operators
+// can look up what was issued to an identity without searching the CT log.
+package ledger
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/sigstore/fulcio/pkg/certificate"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+const (
+	// DefaultPageSize is the number of records returned by List if the
+	// query does not set a page size.
+	DefaultPageSize = 100
+	// MaxPageSize is the largest number of records returned by List.
+	MaxPageSize = 1000
+)
+
+var (
+	// ErrNotFound is returned by Store.Get when no certificate with the
+	// serial number was recorded.
+	ErrNotFound = errors.New("certificate not found in issuance ledger")
+	// ErrInvalidPageToken is returned by Store.List when the page token was
+	// not returned by a previous List with the same query.
+	ErrInvalidPageToken = errors.New("invalid page token")
+)
+
+// Record describes an issued certificate.
+type Record struct {
+	// SerialNumber is the certificate's serial number in lower case
+	// hexadecimal, as returned by NormalizeSerialNumber.
+	SerialNumber string `json:"serialNumber"`
+	// Identity is the certificate's subject alternative name.
+	Identity string `json:"identity"`
+	// Issuer is the OIDC issuer of the identity token the certificate was
+	// issued for.
+	Issuer string `json:"issuer"`
+	// SubjectKeyID is the hex-encoded subject key identifier.
+	SubjectKeyID string `json:"subjectKeyId,omitempty"`
+	// PublicKeySHA256 is the hex-encoded SHA-256 digest of the certificate's
+	// DER-encoded SubjectPublicKeyInfo.
+	PublicKeySHA256 string `json:"publicKeySha256"`
+	// Extensions are the Fulcio extensions of the certificate.
+	Extensions certificate.Extensions `json:"extensions"`
+	// SignedCertificateTimestamps are the TLS-encoded SCTs returned by the
+	// CT logs, either embedded in the certificate or detached.
+	SignedCertificateTimestamps [][]byte `json:"signedCertificateTimestamps,omitempty"`
+	// IssuedAt is when the certificate was issued.
+	IssuedAt  time.Time `json:"issuedAt"`
+	NotBefore time.Time `json:"notBefore"`
+	NotAfter  time.Time `json:"notAfter"`
+	// Certificate is the DER-encoded certificate.
+	Certificate []byte `json:"certificate"`
+}
+
+// NewRecord returns the Record of cert, issued at issuedAt with scts.
+func NewRecord(cert *x509.Certificate, scts [][]byte, issuedAt time.Time) (*Record, error) {
+	exts, err := certificate.ParseExtensions(cert.Extensions)
+	if err != nil {
+		return nil, fmt.Errorf("parsing certificate extensions: %w", err)
+	}
+	var identity string
+	if sans := cryptoutils.GetSubjectAlternateNames(cert); len(sans) > 0 {
+		identity = sans[0]
+	}
+	keyDigest := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return &Record{
+		SerialNumber:                cert.SerialNumber.Text(16),
+		Identity:                    identity,
+		Issuer:                      exts.Issuer,
+		SubjectKeyID:                hex.EncodeToString(cert.SubjectKeyId),
+		PublicKeySHA256:             hex.EncodeToString(keyDigest[:]),
+		Extensions:                  exts,
+		SignedCertificateTimestamps: scts,
+		IssuedAt:                    issuedAt.UTC(),
+		NotBefore:                   cert.NotBefore.UTC(),
+		NotAfter:                    cert.NotAfter.UTC(),
+		Certificate:                 cert.Raw,
+	}, nil
+}
+
+// NormalizeSerialNumber returns a hexadecimal serial number, optionally
+// separated by colons as printed by OpenSSL, in the form of
+// Record.SerialNumber.
+func NormalizeSerialNumber(serial string) (string, error) {
+	s := strings.TrimPrefix(strings.ReplaceAll(serial, ":", ""), "0x")
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok || n.Sign() < 0 {
+		return "", fmt.Errorf("invalid serial number %q, must be hexadecimal", serial)
+	}
+	return n.Text(16), nil
+}
+
+// Query selects records from a Store. Unset fields match all records.
+type Query struct {
+	// SerialNumber selects the record of a certificate, in the form of
+	// Record.SerialNumber.
+	SerialNumber string
+	// Identity selects records by Record.Identity.
+	Identity string
+	// Issuer selects records by Record.Issuer.
+	Issuer string
+	// IssuedAfter selects records issued at or after the time.
+	IssuedAfter time.Time
+	// IssuedBefore selects records issued before the time.
+	IssuedBefore time.Time
+	// PageSize is the maximum number of records to return, defaulting to
+	// DefaultPageSize and capped at MaxPageSize.
+	PageSize int
+	// PageToken continues a previous List with the same query.
+	PageToken string
+}
+
+// Matches reports whether r is selected by the query, ignoring paging.
+func (q Query) Matches(r *Record) bool {
+	switch {
+	case q.SerialNumber != "" && r.SerialNumber != q.SerialNumber,
+		q.Identity != "" && r.Identity != q.Identity,
+		q.Issuer != "" && r.Issuer != q.Issuer,
+		!q.IssuedAfter.IsZero() && r.IssuedAt.Before(q.IssuedAfter),
+		!q.IssuedBefore.IsZero() && !r.IssuedAt.Before(q.IssuedBefore):
+		return false
+	}
+	return true
+}
+
+func (q Query) pageSize() int {
+	switch {
+	case q.PageSize <= 0:
+		return DefaultPageSize
+	case q.PageSize > MaxPageSize:
+		return MaxPageSize
+	}
+	return q.PageSize
+}
+
+// Store persists records. Implementations must be safe for concurrent use.
+type Store interface {
+	// Put records a newly issued certificate.
+	Put(ctx context.Context, r *Record) error
+	// Get returns the record of the certificate with the serial number, in
+	// the form of Record.SerialNumber, or ErrNotFound.
+	Get(ctx context.Context, serialNumber string) (*Record, error)
+	// List returns the records selected by q, most recently issued first,
+	// and a token for the next page if there are more records.
+	List(ctx context.Context, q Query) ([]*Record, string, error)
+	// Close releases the store's resources.
+	Close() error
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ledger
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/sigstore/fulcio/pkg/certificate"
+)
+
+// testCertificate returns a certificate for email issued by issuer.
+func testCertificate(t *testing.T, serial int64, email, issuer string) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exts, err := certificate.Extensions{Issuer: issuer}.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:    big.NewInt(serial),
+		EmailAddresses:  []string{email},
+		NotBefore:       time.Now(),
+		NotAfter:        time.Now().Add(10 * time.Minute),
+		SubjectKeyId:    []byte{1, 2, 3},
+		ExtraExtensions: exts,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestNewRecord(t *testing.T) {
+	cert := testCertificate(t, 0x1234, "alice@example.com", "https://accounts.example.com")
+	issuedAt := time.Now()
+	r, err := NewRecord(cert, [][]byte{{1}}, issuedAt)
+	if err != nil {
+		t.Fatalf("NewRecord() = %v", err)
+	}
+	digest := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	switch {
+	case r.SerialNumber != "1234":
+		t.Errorf("unexpected serial number %q", r.SerialNumber)
+	case r.Identity != "alice@example.com":
+		t.Errorf("unexpected identity %q", r.Identity)
+	case r.Issuer != "https://accounts.example.com" || r.Extensions.Issuer != r.Issuer:
+		t.Errorf("unexpected issuer %q", r.Issuer)
+	case r.SubjectKeyID != "010203":
+		t.Errorf("unexpected subject key ID %q", r.SubjectKeyID)
+	case r.PublicKeySHA256 != hex.EncodeToString(digest[:]):
+		t.Errorf("unexpected public key digest %q", r.PublicKeySHA256)
+	case len(r.SignedCertificateTimestamps) != 1:
+		t.Errorf("expected 1 SCT, got %d", len(r.SignedCertificateTimestamps))
+	case !r.IssuedAt.Equal(issuedAt) || !r.NotAfter.Equal(cert.NotAfter):
+		t.Errorf("unexpected times %v, %v", r.IssuedAt, r.NotAfter)
+	}
+}
+
+func TestNormalizeSerialNumber(t *testing.T) {
+	for in, want := range map[string]string{
+		"1234":        "1234",
+		"00:12:AB:cd": "12abcd",
+		"0x00ff":      "ff",
+		"":            "",
+		"xyz":         "",
+		"-1":          "",
+	} {
+		got, err := NormalizeSerialNumber(in)
+		if (err != nil) != (want == "") || got != want {
+			t.Errorf("NormalizeSerialNumber(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+}
+
+func TestQueryMatches(t *testing.T) {
+	now := time.Now()
+	r := &Record{SerialNumber: "1", Identity: "alice@example.com", Issuer: "https://issuer", IssuedAt: now}
+	for name, test := range map[string]struct {
+		q    Query
+		want bool
+	}{
+		"empty query":          {Query{}, true},
+		"serial number":        {Query{SerialNumber: "1"}, true},
+		"other serial number":  {Query{SerialNumber: "2"}, false},
+		"identity and issuer":  {Query{Identity: "alice@example.com", Issuer: "https://issuer"}, true},
+		"other identity":       {Query{Identity: "bob@example.com"}, false},
+		"other issuer":         {Query{Issuer: "https://other"}, false},
+		"issued after start":   {Query{IssuedAfter: now}, true},
+		"issued before start":  {Query{IssuedAfter: now.Add(time.Second)}, false},
+		"issued before end":    {Query{IssuedBefore: now.Add(time.Second)}, true},
+		"end is exclusive":     {Query{IssuedBefore: now}, false},
+		"within a time window": {Query{IssuedAfter: now.Add(-time.Hour), IssuedBefore: now.Add(time.Hour)}, true},
+	} {
+		if got := test.q.Matches(r); got != test.want {
+			t.Errorf("%s: Matches() = %v, want %v", name, got, test.want)
+		}
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"goa.design/goa/v3/grpc/middleware"
@@ -83,6 +84,9 @@ func ContextLogger(ctx context.Context) *zap.SugaredLogger {
 				proposedLogger = proposedLogger.With(zap.String("requestID", val[0]))
 			}
 		}
+		if fields := traceFields(ctx); fields != nil {
+			proposedLogger = proposedLogger.Desugar().With(fields...).Sugar()
+		}
 	}
 
 	return proposedLogger
@@ -103,7 +107,21 @@ func SetupGRPCLogging() (*zap.Logger, []grpc_zap.Option) {
 					requestID = zap.String("requestID", val[0])
 				}
 			}
-			ctxzap.Extract(ctx).Debug(msg, zap.Error(err), zap.String("grpc.code", code.String()), requestID, duration)
+			fields := append([]zap.Field{zap.Error(err), zap.String("grpc.code", code.String()), requestID, duration}, traceFields(ctx)...)
+			ctxzap.Extract(ctx).Debug(msg, fields...)
 		}))
 	return Logger.Desugar(), options
 }
+
+// traceFields returns the fields linking a log entry to the span in ctx,
+// or nil if there is none.
+func traceFields(ctx context.Context) []zap.Field {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []zap.Field{
+		zap.String("traceID", sc.TraceID().String()),
+		zap.String("spanID", sc.SpanID().String()),
+	}
+}
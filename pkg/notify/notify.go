@@ -0,0 +1,159 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notify posts a CloudEvent to webhook endpoints for each issued
+// certificate, so that issuance for sensitive identities can be alerted on
+// as it happens.
+package notify
+
+import (
+	"crypto/x509"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sigstore/fulcio/pkg/certificate"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+// EventType is the CloudEvents type of the events posted for issued
+// certificates.
+const EventType = "dev.sigstore.fulcio.certificate.issued"
+
+// Event describes an issued certificate. It is the data of the CloudEvent.
+type Event struct {
+	// SerialNumber is the certificate's serial number in lower case
+	// hexadecimal.
+	SerialNumber string `json:"serialNumber"`
+	// SubjectAlternativeName is the certificate's subject alternative name.
+	SubjectAlternativeName string `json:"subjectAlternativeName"`
+	// Issuer is the OIDC issuer of the identity token the certificate was
+	// issued for.
+	Issuer string `json:"issuer"`
+	// Extensions are the Fulcio extensions of the certificate.
+	Extensions certificate.Extensions `json:"extensions"`
+	NotBefore  time.Time              `json:"notBefore"`
+	NotAfter   time.Time              `json:"notAfter"`
+	// Claims are the claims of the identity token, which select the
+	// endpoints the event is posted to. They are not posted.
+	Claims map[string]any `json:"-"`
+}
+
+// NewEvent returns the Event of cert, issued for a token with claims.
+func NewEvent(cert *x509.Certificate, claims map[string]any) (*Event, error) {
+	exts, err := certificate.ParseExtensions(cert.Extensions)
+	if err != nil {
+		return nil, fmt.Errorf("parsing certificate extensions: %w", err)
+	}
+	var san string
+	if sans := cryptoutils.GetSubjectAlternateNames(cert); len(sans) > 0 {
+		san = sans[0]
+	}
+	return &Event{
+		SerialNumber:           cert.SerialNumber.Text(16),
+		SubjectAlternativeName: san,
+		Issuer:                 exts.Issuer,
+		Extensions:             exts,
+		NotBefore:              cert.NotBefore.UTC(),
+		NotAfter:               cert.NotAfter.UTC(),
+		Claims:                 claims,
+	}, nil
+}
+
+// Filter selects events by the claims of the identity token. An event
+// matches if each claim named in the filter has one of the listed values.
+// Claim names are matched case-insensitively, and values exactly.
+type Filter map[string][]string
+
+// Matches reports whether claims satisfy the filter.
+func (f Filter) Matches(claims map[string]any) bool {
+	for name, values := range f {
+		v, ok := claimValue(claims, name)
+		if !ok || !matchesAny(v, values) {
+			return false
+		}
+	}
+	return true
+}
+
+// claimValue looks up a claim case-insensitively, since filters read from
+// the server config have lower case names.
+func claimValue(claims map[string]any, name string) (any, bool) {
+	if v, ok := claims[name]; ok {
+		return v, true
+	}
+	for k, v := range claims {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// matchesAny reports whether claim, or one of its elements if it is a list
+// such as an audience, is one of values.
+func matchesAny(claim any, values []string) bool {
+	if list, ok := claim.([]any); ok {
+		for _, c := range list {
+			if matchesAny(c, values) {
+				return true
+			}
+		}
+		return false
+	}
+	var s string
+	switch claim := claim.(type) {
+	case float64:
+		// Claims are decoded from JSON, so numbers such as repository IDs
+		// are float64s, which fmt would print in exponent form
+		s = strconv.FormatFloat(claim, 'f', -1, 64)
+	default:
+		s = fmt.Sprint(claim)
+	}
+	for _, v := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Endpoint is a webhook that events are posted to.
+type Endpoint struct {
+	// Name identifies the endpoint in logs and metrics.
+	Name string
+	// URL is where events are posted.
+	URL string
+	// Secret, if set, is the key of the HMAC-SHA256 of each payload sent in
+	// the SignatureHeader header.
+	Secret []byte
+	// Filters select the events posted to the endpoint. An event is posted
+	// if any filter matches, or if there are no filters.
+	Filters []Filter
+}
+
+// Matches reports whether an event for a token with claims is posted to the
+// endpoint.
+func (e Endpoint) Matches(claims map[string]any) bool {
+	if len(e.Filters) == 0 {
+		return true
+	}
+	for _, f := range e.Filters {
+		if f.Matches(claims) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEndpointMatches(t *testing.T) {
+	releaseBot := map[string]any{
+		"iss":              "https://token.actions.githubusercontent.com",
+		"job_workflow_ref": "octo/app/.github/workflows/release.yml@refs/heads/main",
+		"aud":              []any{"sigstore", "other"},
+	}
+	admin := map[string]any{
+		"email":          "admin@example.com",
+		"email_verified": true,
+	}
+	other := map[string]any{
+		"email": "dev@example.com",
+	}
+	// Decoded as from a token, so that the ID is a float64
+	var repository map[string]any
+	if err := json.Unmarshal([]byte(`{"repository_id": 123456789}`), &repository); err != nil {
+		t.Fatal(err)
+	}
+
+	endpoint := Endpoint{
+		Filters: []Filter{
+			{"job_workflow_ref": {"octo/app/.github/workflows/release.yml@refs/heads/main"}},
+			{"email": {"admin@example.com", "root@example.com"}, "email_verified": {"true"}},
+		},
+	}
+	for name, test := range map[string]struct {
+		endpoint Endpoint
+		claims   map[string]any
+		want     bool
+	}{
+		"first filter":           {endpoint, releaseBot, true},
+		"second filter":          {endpoint, admin, true},
+		"no filter":              {endpoint, other, false},
+		"missing claim":          {endpoint, map[string]any{"email": "admin@example.com"}, false},
+		"no filters":             {Endpoint{}, other, true},
+		"list claim":             {Endpoint{Filters: []Filter{{"aud": {"other"}}}}, releaseBot, true},
+		"list claim no match":    {Endpoint{Filters: []Filter{{"aud": {"fulcio"}}}}, releaseBot, false},
+		"case-insensitive claim": {Endpoint{Filters: []Filter{{"email": {"dev@example.com"}}}}, map[string]any{"Email": "dev@example.com"}, true},
+		"case-sensitive value":   {Endpoint{Filters: []Filter{{"email": {"DEV@example.com"}}}}, other, false},
+		"numeric claim":          {Endpoint{Filters: []Filter{{"repository_id": {"123456789"}}}}, repository, true},
+		"numeric claim no match": {Endpoint{Filters: []Filter{{"repository_id": {"12345678"}}}}, repository, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := test.endpoint.Matches(test.claims); got != test.want {
+				t.Errorf("Matches() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,371 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sigstore/fulcio/pkg/backoff"
+	"github.com/sigstore/fulcio/pkg/log"
+)
+
+const (
+	// DefaultSource is the CloudEvents source of posted events.
+	DefaultSource = "fulcio"
+	// DefaultQueueSize is the number of events held for each endpoint
+	// before further events are dropped.
+	DefaultQueueSize = 1000
+	// DefaultRetries is the number of times a failed delivery is retried.
+	DefaultRetries = 3
+	// DefaultInitialBackoff is the delay before the first retry.
+	DefaultInitialBackoff = time.Second
+	// DefaultMaxBackoff caps the delay between retries.
+	DefaultMaxBackoff = 30 * time.Second
+	// DefaultAttemptTimeout bounds each delivery attempt.
+	DefaultAttemptTimeout = 10 * time.Second
+)
+
+// SignatureHeader holds "sha256=" followed by the hex-encoded HMAC-SHA256 of
+// the payload, keyed with the endpoint's secret.
+const SignatureHeader = "X-Fulcio-Signature-256"
+
+const (
+	dropQueueFull        = "queue_full"
+	dropRejected         = "rejected"
+	dropRetriesExhausted = "retries_exhausted"
+	dropShutdown         = "shutdown"
+)
+
+var (
+	metricDelivered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulcio_notify_delivered_total",
+		Help: "The total number of issuance events delivered to each webhook endpoint",
+	}, []string{"endpoint"})
+
+	metricDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulcio_notify_dropped_total",
+		Help: "The total number of issuance events that were not delivered to each webhook endpoint, by reason",
+	}, []string{"endpoint", "reason"})
+
+	metricDeliveryLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fulcio_notify_delivery_latency_seconds",
+		Help:    "Time from issuance to delivery of issuance events to each webhook endpoint, including time spent queued and retrying",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	}, []string{"endpoint"})
+
+	metricQueueLength = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "fulcio_notify_queue_length",
+		Help: "The number of issuance events waiting to be delivered to each webhook endpoint",
+	}, []string{"endpoint"})
+)
+
+// cloudEvent is a CloudEvents 1.0 event in structured JSON mode.
+type cloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	Type            string    `json:"type"`
+	Source          string    `json:"source"`
+	ID              string    `json:"id"`
+	Time            time.Time `json:"time"`
+	Subject         string    `json:"subject"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            *Event    `json:"data"`
+}
+
+// delivery is an event waiting to be posted.
+type delivery struct {
+	payload []byte
+	queued  time.Time
+}
+
+// endpointQueue holds the events waiting to be posted to an endpoint.
+type endpointQueue struct {
+	Endpoint
+	deliveries chan delivery
+}
+
+// Notifier posts events to webhook endpoints in the background. Each
+// endpoint has a bounded queue, so that a slow or unavailable endpoint
+// delays no other endpoint, and never delays issuance.
+type Notifier struct {
+	endpoints      []*endpointQueue
+	source         string
+	client         *http.Client
+	queueSize      int
+	backoff        backoff.Backoff
+	attemptTimeout time.Duration
+}
+
+// NotifierOption configures the Notifier returned by NewNotifier.
+type NotifierOption func(*Notifier)
+
+// WithSource sets the CloudEvents source of posted events. Defaults to
+// DefaultSource.
+func WithSource(source string) NotifierOption {
+	return func(n *Notifier) {
+		n.source = source
+	}
+}
+
+// WithQueueSize sets the number of events held for each endpoint before
+// further events are dropped. Defaults to DefaultQueueSize.
+func WithQueueSize(size int) NotifierOption {
+	return func(n *Notifier) {
+		n.queueSize = size
+	}
+}
+
+// WithRetries sets the number of times a failed delivery is retried.
+// Defaults to DefaultRetries.
+func WithRetries(retries int) NotifierOption {
+	return func(n *Notifier) {
+		n.backoff.Retries = retries
+	}
+}
+
+// WithBackoff sets the delay before the first retry, which doubles with each
+// retry up to maxBackoff. Each delay is randomly shortened by up to half to
+// spread out retries. Defaults to DefaultInitialBackoff and
+// DefaultMaxBackoff.
+func WithBackoff(initial, maxBackoff time.Duration) NotifierOption {
+	return func(n *Notifier) {
+		n.backoff.Initial = initial
+		n.backoff.Max = maxBackoff
+	}
+}
+
+// WithAttemptTimeout sets how long each delivery attempt may take. Defaults
+// to DefaultAttemptTimeout.
+func WithAttemptTimeout(d time.Duration) NotifierOption {
+	return func(n *Notifier) {
+		n.attemptTimeout = d
+	}
+}
+
+// WithHTTPClient sets the client events are posted with. Defaults to
+// http.DefaultClient.
+func WithHTTPClient(c *http.Client) NotifierOption {
+	return func(n *Notifier) {
+		n.client = c
+	}
+}
+
+// NewNotifier returns a Notifier that posts events to endpoints once Run is
+// called.
+func NewNotifier(endpoints []Endpoint, opts ...NotifierOption) (*Notifier, error) {
+	n := &Notifier{
+		source:    DefaultSource,
+		client:    http.DefaultClient,
+		queueSize: DefaultQueueSize,
+		backoff: backoff.Backoff{
+			Retries: DefaultRetries,
+			Initial: DefaultInitialBackoff,
+			Max:     DefaultMaxBackoff,
+		},
+		attemptTimeout: DefaultAttemptTimeout,
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	if err := n.backoff.Validate("notification"); err != nil {
+		return nil, err
+	}
+	switch {
+	case n.queueSize <= 0:
+		return nil, fmt.Errorf("notification queue size must be positive, got %d", n.queueSize)
+	case n.attemptTimeout <= 0:
+		return nil, fmt.Errorf("notification attempt timeout must be positive, got %v", n.attemptTimeout)
+	}
+
+	names := map[string]bool{}
+	for _, e := range endpoints {
+		if e.Name == "" || e.URL == "" {
+			return nil, errors.New("notification endpoints must have a name and a URL")
+		}
+		if names[e.Name] {
+			return nil, fmt.Errorf("duplicate notification endpoint %q", e.Name)
+		}
+		if u, err := url.Parse(e.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("notification endpoint %q must have an http or https URL", e.Name)
+		}
+		names[e.Name] = true
+		n.endpoints = append(n.endpoints, &endpointQueue{
+			Endpoint:   e,
+			deliveries: make(chan delivery, n.queueSize),
+		})
+		metricQueueLength.WithLabelValues(e.Name).Set(0)
+	}
+	return n, nil
+}
+
+// Notify queues e for delivery to the endpoints it matches. It never blocks:
+// if an endpoint's queue is full, the event is dropped for that endpoint.
+func (n *Notifier) Notify(ctx context.Context, e *Event) {
+	var payload []byte
+	for _, ep := range n.endpoints {
+		if !ep.Matches(e.Claims) {
+			continue
+		}
+		if payload == nil {
+			var err error
+			payload, err = n.marshal(e)
+			if err != nil {
+				log.ContextLogger(ctx).Errorf("error marshaling issuance event for certificate %s: %v", e.SerialNumber, err)
+				return
+			}
+		}
+		// Count the event before it is queued, so that the worker cannot
+		// dequeue it first and make the gauge go negative
+		queueLength := metricQueueLength.WithLabelValues(ep.Name)
+		queueLength.Inc()
+		select {
+		case ep.deliveries <- delivery{payload: payload, queued: time.Now()}:
+		default:
+			queueLength.Dec()
+			metricDropped.WithLabelValues(ep.Name, dropQueueFull).Inc()
+			log.ContextLogger(ctx).Warnf("notification queue for %s is full, dropping issuance event for certificate %s", ep.Name, e.SerialNumber)
+		}
+	}
+}
+
+// marshal returns the CloudEvent of e.
+func (n *Notifier) marshal(e *Event) ([]byte, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	return json.Marshal(cloudEvent{
+		SpecVersion:     "1.0",
+		Type:            EventType,
+		Source:          n.source,
+		ID:              hex.EncodeToString(id),
+		Time:            time.Now().UTC(),
+		Subject:         e.SerialNumber,
+		DataContentType: "application/json",
+		Data:            e,
+	})
+}
+
+// Run delivers queued events until ctx is canceled. Events still queued
+// then are dropped.
+func (n *Notifier) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, ep := range n.endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n.run(ctx, ep)
+		}()
+	}
+	wg.Wait()
+}
+
+func (n *Notifier) run(ctx context.Context, ep *endpointQueue) {
+	for {
+		select {
+		case <-ctx.Done():
+			metricDropped.WithLabelValues(ep.Name, dropShutdown).Add(float64(len(ep.deliveries)))
+			return
+		case d := <-ep.deliveries:
+			metricQueueLength.WithLabelValues(ep.Name).Dec()
+			if reason := n.deliver(ctx, ep, d.payload); reason != "" {
+				metricDropped.WithLabelValues(ep.Name, reason).Inc()
+				continue
+			}
+			metricDelivered.WithLabelValues(ep.Name).Inc()
+			metricDeliveryLatency.WithLabelValues(ep.Name).Observe(time.Since(d.queued).Seconds())
+		}
+	}
+}
+
+// deliver posts payload to ep, retrying with jittered exponential backoff.
+// If the payload was not delivered, the reason it was dropped is returned.
+func (n *Notifier) deliver(ctx context.Context, ep *endpointQueue, payload []byte) string {
+	err := n.backoff.Retry(ctx, func(int) error {
+		return n.attempt(ctx, ep, payload)
+	}, func(err error) bool {
+		return !errors.As(err, new(*rejectedError))
+	})
+	switch {
+	case err == nil:
+		return ""
+	case ctx.Err() != nil:
+		return dropShutdown
+	case errors.As(err, new(*rejectedError)):
+		log.Logger.Warnf("notification endpoint %s rejected issuance event: %v", ep.Name, err)
+		return dropRejected
+	}
+	log.Logger.Warnf("notification endpoint %s failed, dropping issuance event: %v", ep.Name, err)
+	return dropRetriesExhausted
+}
+
+// rejectedError is returned for responses that retrying will not change.
+type rejectedError struct {
+	status int
+}
+
+func (e *rejectedError) Error() string {
+	return fmt.Sprintf("unexpected status %d", e.status)
+}
+
+// attempt makes a single delivery attempt.
+func (n *Notifier) attempt(ctx context.Context, ep *endpointQueue, payload []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, n.attemptTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+	if len(ep.Secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(ep.Secret, payload))
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return &rejectedError{status: resp.StatusCode}
+}
+
+// Sign returns the value of SignatureHeader for payload.
+func Sign(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,191 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// received is a request received by a test endpoint.
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// newEndpoint returns the URL of a server that records requests in ch and
+// responds with the status returned by respond.
+func newEndpoint(t *testing.T, ch chan<- received, respond func() int) string {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ch <- received{header: r.Header, body: body}
+		w.WriteHeader(respond())
+	}))
+	t.Cleanup(s.Close)
+	return s.URL
+}
+
+func TestNotifier(t *testing.T) {
+	alerts := make(chan received, 10)
+	all := make(chan received, 10)
+	// The second endpoint fails the first attempt
+	var attempts atomic.Int32
+	n, err := NewNotifier([]Endpoint{{
+		Name:    "alerts",
+		URL:     newEndpoint(t, alerts, func() int { return http.StatusOK }),
+		Secret:  []byte("secret"),
+		Filters: []Filter{{"email": {"admin@example.com"}}},
+	}, {
+		Name: "all",
+		URL: newEndpoint(t, all, func() int {
+			if attempts.Add(1) == 1 {
+				return http.StatusServiceUnavailable
+			}
+			return http.StatusOK
+		}),
+	}}, WithSource("https://fulcio.example.com"), WithBackoff(time.Millisecond, time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewNotifier() = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go n.Run(ctx)
+
+	n.Notify(ctx, &Event{
+		SerialNumber:           "1",
+		SubjectAlternativeName: "admin@example.com",
+		Claims:                 map[string]any{"email": "admin@example.com"},
+	})
+	n.Notify(ctx, &Event{
+		SerialNumber: "2",
+		Claims:       map[string]any{"email": "dev@example.com"},
+	})
+
+	got := <-alerts
+	if got.header.Get("Content-Type") != "application/cloudevents+json" {
+		t.Errorf("unexpected content type %q", got.header.Get("Content-Type"))
+	}
+	if sig := got.header.Get(SignatureHeader); sig != Sign([]byte("secret"), got.body) {
+		t.Errorf("unexpected signature %q", sig)
+	}
+	var event struct {
+		SpecVersion string `json:"specversion"`
+		Type        string `json:"type"`
+		Source      string `json:"source"`
+		ID          string `json:"id"`
+		Subject     string `json:"subject"`
+		Data        Event  `json:"data"`
+	}
+	if err := json.Unmarshal(got.body, &event); err != nil {
+		t.Fatalf("json.Unmarshal() = %v", err)
+	}
+	switch {
+	case event.SpecVersion != "1.0" || event.Type != EventType || event.ID == "":
+		t.Errorf("invalid CloudEvent %+v", event)
+	case event.Source != "https://fulcio.example.com":
+		t.Errorf("unexpected source %q", event.Source)
+	case event.Subject != "1" || event.Data.SerialNumber != "1" || event.Data.SubjectAlternativeName != "admin@example.com":
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	// Both events are posted to the endpoint without filters, after a retry
+	var serials []string
+	for len(serials) < 2 {
+		select {
+		case got := <-all:
+			if got.header.Get(SignatureHeader) != "" {
+				t.Error("expected no signature without a secret")
+			}
+			if err := json.Unmarshal(got.body, &event); err != nil {
+				t.Fatalf("json.Unmarshal() = %v", err)
+			}
+			serials = append(serials, event.Subject)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for events, got %v", serials)
+		}
+	}
+	if serials[0] != "1" || serials[1] != "1" {
+		t.Errorf("expected the first event to be retried, got %v", serials)
+	}
+	got = <-all
+	if err := json.Unmarshal(got.body, &event); err != nil || event.Subject != "2" {
+		t.Errorf("expected the second event, got %s", got.body)
+	}
+	select {
+	case got := <-alerts:
+		t.Errorf("unexpected event %s", got.body)
+	default:
+	}
+}
+
+func TestNotifierDrops(t *testing.T) {
+	requests := make(chan received, 10)
+	n, err := NewNotifier([]Endpoint{{
+		Name: "drops",
+		URL:  newEndpoint(t, requests, func() int { return http.StatusBadRequest }),
+	}}, WithQueueSize(1), WithBackoff(time.Millisecond, time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewNotifier() = %v", err)
+	}
+
+	// Notify never blocks, dropping events once the queue is full
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n.Notify(ctx, &Event{SerialNumber: "1"})
+	n.Notify(ctx, &Event{SerialNumber: "2"})
+	if got := testutil.ToFloat64(metricDropped.WithLabelValues("drops", dropQueueFull)); got != 1 {
+		t.Errorf("expected 1 event dropped with a full queue, got %v", got)
+	}
+
+	// Events rejected by the endpoint are not retried
+	go n.Run(ctx)
+	<-requests
+	deadline := time.Now().Add(10 * time.Second)
+	for testutil.ToFloat64(metricDropped.WithLabelValues("drops", dropRejected)) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the event to be rejected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case <-requests:
+		t.Error("expected a rejected event not to be retried")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestNewNotifier(t *testing.T) {
+	for name, endpoints := range map[string][]Endpoint{
+		"no name":    {{URL: "https://example.com"}},
+		"no URL":     {{Name: "a"}},
+		"bad scheme": {{Name: "a", URL: "ftp://example.com"}},
+		"duplicate":  {{Name: "a", URL: "https://example.com"}, {Name: "a", URL: "https://example.org"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewNotifier(endpoints); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauthflow
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/pkg/browser"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// DefaultScopes are the scopes requested by the flows, unless they set
+// their own.
+var DefaultScopes = []string{oidc.ScopeOpenID, "email"}
+
+// CallbackPath is the path of the loopback redirect URI of AuthCodeFlow.
+const CallbackPath = "/auth/callback"
+
+// OIDCIDToken is an ID token obtained by GetIDToken.
+type OIDCIDToken struct {
+	// RawString is the encoded token, to present to Fulcio.
+	RawString string
+	// Subject is the email address of the token if it has one, and its
+	// subject otherwise.
+	Subject string
+}
+
+// Flow obtains an ID token from an OIDC issuer.
+type Flow interface {
+	// IDToken obtains an ID token for the client configured by cfg, whose
+	// endpoints are those of the issuer, and verifies it with verifier.
+	IDToken(ctx context.Context, cfg *oauth2.Config, verifier *oidc.IDTokenVerifier) (*oidc.IDToken, string, error)
+}
+
+var (
+	_ Flow = (*AuthCodeFlow)(nil)
+	_ Flow = (*DeviceFlow)(nil)
+	_ Flow = (*ClientCredentialsFlow)(nil)
+)
+
+// GetIDToken discovers the OIDC issuer at issuer and obtains an ID token for
+// clientID from it with flow.
+func GetIDToken(ctx context.Context, issuer, clientID string, flow Flow) (*OIDCIDToken, error) {
+	provider, err := oidc.NewProvider(ctx, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("discovering OIDC issuer %s: %w", issuer, err)
+	}
+	cfg := &oauth2.Config{
+		ClientID: clientID,
+		Endpoint: provider.Endpoint(),
+		Scopes:   DefaultScopes,
+	}
+	token, raw, err := flow.IDToken(ctx, cfg, provider.Verifier(&oidc.Config{ClientID: clientID}))
+	if err != nil {
+		return nil, err
+	}
+	subject := token.Subject
+	if email, _, err := EmailFromIDToken(token); err == nil {
+		subject = email
+	}
+	return &OIDCIDToken{RawString: raw, Subject: subject}, nil
+}
+
+// verifyIDToken returns the ID token of an OAuth 2.0 token response,
+// verified with verifier. If the response has no ID token, its access
+// token is verified as an ID token, as issued for workload identities.
+func verifyIDToken(ctx context.Context, token *oauth2.Token, verifier *oidc.IDTokenVerifier) (*oidc.IDToken, string, error) {
+	raw, _ := token.Extra("id_token").(string)
+	if raw == "" {
+		raw = token.AccessToken
+	}
+	if raw == "" {
+		return nil, "", errors.New("token response has no ID token")
+	}
+	idToken, err := verifier.Verify(ctx, raw)
+	if err != nil {
+		return nil, "", fmt.Errorf("verifying ID token: %w", err)
+	}
+	return idToken, raw, nil
+}
+
+// randomString returns a random URL-safe string, for states and nonces.
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// AuthCodeFlow obtains an ID token interactively with the authorization
+// code flow and PKCE, redirecting the user's browser to a server on the
+// loopback interface, as described in RFC 8252.
+type AuthCodeFlow struct {
+	// ClientSecret is the secret of a confidential client, or empty for a
+	// public client.
+	ClientSecret string
+	// Scopes are the scopes requested, or DefaultScopes if empty.
+	Scopes []string
+	// RedirectPort is the port of the loopback redirect URI, or 0 for any
+	// free port.
+	RedirectPort int
+	// OpenURL opens the authorization URL in the user's browser. Defaults to
+	// the system browser.
+	OpenURL func(url string) error
+	// Output receives instructions for the user. Defaults to os.Stderr.
+	Output io.Writer
+}
+
+// callbackResult is the result of the redirect to the loopback server.
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// IDToken implements Flow.
+func (f *AuthCodeFlow) IDToken(ctx context.Context, cfg *oauth2.Config, verifier *oidc.IDTokenVerifier) (*oidc.IDToken, string, error) {
+	output := f.Output
+	if output == nil {
+		output = os.Stderr
+	}
+	openURL := f.OpenURL
+	if openURL == nil {
+		openURL = browser.OpenURL
+	}
+	state, err := randomString()
+	if err != nil {
+		return nil, "", err
+	}
+	nonce, err := randomString()
+	if err != nil {
+		return nil, "", err
+	}
+	pkce := oauth2.GenerateVerifier()
+
+	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", f.RedirectPort))
+	if err != nil {
+		return nil, "", fmt.Errorf("listening for redirect: %w", err)
+	}
+	c := *cfg
+	c.ClientSecret = f.ClientSecret
+	if len(f.Scopes) > 0 {
+		c.Scopes = f.Scopes
+	}
+	c.RedirectURL = fmt.Sprintf("http://localhost:%d%s", lis.Addr().(*net.TCPAddr).Port, CallbackPath)
+
+	results := make(chan callbackResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(CallbackPath, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var result callbackResult
+		switch {
+		case q.Get("state") != state:
+			result.err = errors.New("redirect has an invalid state")
+		case q.Get("error") != "":
+			result.err = fmt.Errorf("authorization failed: %s %s", q.Get("error"), q.Get("error_description"))
+		case q.Get("code") == "":
+			result.err = errors.New("redirect has no authorization code")
+		default:
+			result.code = q.Get("code")
+		}
+		if result.err != nil {
+			http.Error(w, html.EscapeString(result.err.Error()), http.StatusBadRequest)
+		} else {
+			fmt.Fprint(w, "<html><body>Authentication complete. You may close this window.</body></html>")
+		}
+		select {
+		case results <- result:
+		default:
+		}
+	})
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Close()
+
+	authURL := c.AuthCodeURL(state, oauth2.S256ChallengeOption(pkce), oidc.Nonce(nonce))
+	fmt.Fprintf(output, "Your browser will now be opened to:\n%s\n", authURL)
+	if err := openURL(authURL); err != nil {
+		fmt.Fprintf(output, "Could not open the browser, open the URL above to continue: %v\n", err)
+	}
+
+	var result callbackResult
+	select {
+	case <-ctx.Done():
+		return nil, "", ctx.Err()
+	case result = <-results:
+	}
+	if result.err != nil {
+		return nil, "", result.err
+	}
+	token, err := c.Exchange(ctx, result.code, oauth2.VerifierOption(pkce))
+	if err != nil {
+		return nil, "", fmt.Errorf("exchanging authorization code: %w", err)
+	}
+	idToken, raw, err := verifyIDToken(ctx, token, verifier)
+	if err != nil {
+		return nil, "", err
+	}
+	if idToken.Nonce != nonce {
+		return nil, "", errors.New("ID token has an invalid nonce")
+	}
+	return idToken, raw, nil
+}
+
+// DeviceFlow obtains an ID token with the device authorization grant of
+// RFC 8628, for users on a device without a browser.
+type DeviceFlow struct {
+	// ClientSecret is the secret of a confidential client, or empty for a
+	// public client.
+	ClientSecret string
+	// Scopes are the scopes requested, or DefaultScopes if empty.
+	Scopes []string
+	// Output receives the code the user enters and where to enter it.
+	// Defaults to os.Stderr.
+	Output io.Writer
+}
+
+// IDToken implements Flow.
+func (f *DeviceFlow) IDToken(ctx context.Context, cfg *oauth2.Config, verifier *oidc.IDTokenVerifier) (*oidc.IDToken, string, error) {
+	if cfg.Endpoint.DeviceAuthURL == "" {
+		return nil, "", errors.New("issuer does not support the device authorization grant")
+	}
+	output := f.Output
+	if output == nil {
+		output = os.Stderr
+	}
+	c := *cfg
+	c.ClientSecret = f.ClientSecret
+	if len(f.Scopes) > 0 {
+		c.Scopes = f.Scopes
+	}
+
+	resp, err := c.DeviceAuth(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("requesting device authorization: %w", err)
+	}
+	if resp.VerificationURIComplete != "" {
+		fmt.Fprintf(output, "To continue, visit:\n%s\n", resp.VerificationURIComplete)
+	} else {
+		fmt.Fprintf(output, "To continue, visit %s and enter the code: %s\n", resp.VerificationURI, resp.UserCode)
+	}
+	token, err := c.DeviceAccessToken(ctx, resp)
+	if err != nil {
+		return nil, "", fmt.Errorf("waiting for device authorization: %w", err)
+	}
+	return verifyIDToken(ctx, token, verifier)
+}
+
+// ClientCredentialsFlow obtains an ID token without user interaction with
+// the client credentials grant, for workloads with a client secret.
+type ClientCredentialsFlow struct {
+	// ClientSecret is the secret of the client.
+	ClientSecret string
+	// Scopes are the scopes requested, or DefaultScopes if empty.
+	Scopes []string
+}
+
+// IDToken implements Flow.
+func (f *ClientCredentialsFlow) IDToken(ctx context.Context, cfg *oauth2.Config, verifier *oidc.IDTokenVerifier) (*oidc.IDToken, string, error) {
+	c := &clientcredentials.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: f.ClientSecret,
+		TokenURL:     cfg.Endpoint.TokenURL,
+		Scopes:       cfg.Scopes,
+		AuthStyle:    cfg.Endpoint.AuthStyle,
+	}
+	if len(f.Scopes) > 0 {
+		c.Scopes = f.Scopes
+	}
+	token, err := c.Token(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("requesting client credentials token: %w", err)
+	}
+	return verifyIDToken(ctx, token, verifier)
+}
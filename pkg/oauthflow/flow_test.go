@@ -0,0 +1,295 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauthflow
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"golang.org/x/oauth2"
+)
+
+const (
+	testClientID     = "sigstore"
+	testClientSecret = "secret"
+	testEmail        = "foo@example.com"
+)
+
+// mockIdP is an in-process OIDC issuer supporting the authorization code
+// flow with PKCE, the device authorization grant and the client credentials
+// grant. Every authorization is granted to testEmail.
+type mockIdP struct {
+	server *httptest.Server
+	signer jose.Signer
+	jwk    jose.JSONWebKey
+
+	mu sync.Mutex
+	// codes are the PKCE challenges and nonces of authorization codes
+	codes map[string]authorization
+	// pending are the device codes not yet approved
+	pending map[string]bool
+	// deny makes the authorization endpoint deny access
+	deny bool
+}
+
+type authorization struct {
+	challenge string
+	nonce     string
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: key}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mockIdP{
+		signer:  signer,
+		jwk:     jose.JSONWebKey{Key: key.Public(), Algorithm: string(jose.ES256), Use: "sig"},
+		codes:   map[string]authorization{},
+		pending: map[string]bool{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", m.discovery)
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{m.jwk}})
+	})
+	mux.HandleFunc("/authorize", m.authorize)
+	mux.HandleFunc("/device", m.device)
+	mux.HandleFunc("/token", m.token)
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockIdP) discovery(w http.ResponseWriter, _ *http.Request) {
+	issuer := m.server.URL
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"issuer":                                issuer,
+		"authorization_endpoint":                issuer + "/authorize",
+		"device_authorization_endpoint":         issuer + "/device",
+		"token_endpoint":                        issuer + "/token",
+		"jwks_uri":                              issuer + "/keys",
+		"id_token_signing_alg_values_supported": []string{"ES256"},
+	})
+}
+
+// authorize redirects to the client with an authorization code, as if the
+// user logged in.
+func (m *mockIdP) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || redirect.Hostname() != "localhost" || q.Get("client_id") != testClientID {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	params := url.Values{"state": {q.Get("state")}}
+	m.mu.Lock()
+	if m.deny {
+		params.Set("error", "access_denied")
+	} else if q.Get("code_challenge_method") != "S256" {
+		params.Set("error", "invalid_request")
+	} else {
+		code := rand.Text()
+		m.codes[code] = authorization{challenge: q.Get("code_challenge"), nonce: q.Get("nonce")}
+		params.Set("code", code)
+	}
+	m.mu.Unlock()
+	redirect.RawQuery = params.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (m *mockIdP) device(w http.ResponseWriter, _ *http.Request) {
+	code := rand.Text()
+	m.mu.Lock()
+	m.pending[code] = true
+	m.mu.Unlock()
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"device_code":      code,
+		"user_code":        "ABCD-EFGH",
+		"verification_uri": m.server.URL + "/activate",
+		"expires_in":       60,
+		"interval":         1,
+	})
+}
+
+func (m *mockIdP) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tokenError := func(code string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": code})
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var nonce string
+	switch r.Form.Get("grant_type") {
+	case "authorization_code":
+		auth, ok := m.codes[r.Form.Get("code")]
+		delete(m.codes, r.Form.Get("code"))
+		if !ok || oauth2.S256ChallengeFromVerifier(r.Form.Get("code_verifier")) != auth.challenge {
+			tokenError("invalid_grant")
+			return
+		}
+		nonce = auth.nonce
+	case "urn:ietf:params:oauth:grant-type:device_code":
+		code := r.Form.Get("device_code")
+		pending, ok := m.pending[code]
+		if !ok {
+			tokenError("invalid_grant")
+			return
+		}
+		if pending {
+			// Approved by the user before the next poll
+			m.pending[code] = false
+			tokenError("authorization_pending")
+			return
+		}
+		delete(m.pending, code)
+	case "client_credentials":
+		id, secret, ok := r.BasicAuth()
+		if !ok {
+			id, secret = r.Form.Get("client_id"), r.Form.Get("client_secret")
+		}
+		if id != testClientID || secret != testClientSecret {
+			tokenError("invalid_client")
+			return
+		}
+	default:
+		tokenError("unsupported_grant_type")
+		return
+	}
+
+	claims := struct {
+		jwt.Claims
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Nonce         string `json:"nonce,omitempty"`
+	}{
+		Claims: jwt.Claims{
+			Issuer:   m.server.URL,
+			Subject:  "1234",
+			Audience: jwt.Audience{testClientID},
+			IssuedAt: jwt.NewNumericDate(time.Now()),
+			Expiry:   jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+		Email:         testEmail,
+		EmailVerified: true,
+		Nonce:         nonce,
+	}
+	idToken, err := jwt.Signed(m.signer).Claims(claims).Serialize()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     idToken,
+	})
+}
+
+// visit follows the authorization URL as the user's browser would.
+func visit(authURL string) error {
+	resp, err := http.Get(authURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s: %s", resp.Status, body)
+	}
+	return nil
+}
+
+func TestGetIDToken(t *testing.T) {
+	m := newMockIdP(t)
+	tests := map[string]Flow{
+		"auth code":          &AuthCodeFlow{OpenURL: visit, Output: io.Discard},
+		"device":             &DeviceFlow{Output: io.Discard},
+		"client credentials": &ClientCredentialsFlow{ClientSecret: testClientSecret},
+	}
+	for name, flow := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			token, err := GetIDToken(ctx, m.server.URL, testClientID, flow)
+			if err != nil {
+				t.Fatalf("GetIDToken() = %v", err)
+			}
+			if token.Subject != testEmail || strings.Count(token.RawString, ".") != 2 {
+				t.Errorf("unexpected token %+v", token)
+			}
+		})
+	}
+}
+
+func TestGetIDTokenErrors(t *testing.T) {
+	m := newMockIdP(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := GetIDToken(ctx, m.server.URL, testClientID, &ClientCredentialsFlow{ClientSecret: "wrong"}); err == nil {
+		t.Error("expected an invalid client secret to fail")
+	}
+
+	m.mu.Lock()
+	m.deny = true
+	m.mu.Unlock()
+	_, err := GetIDToken(ctx, m.server.URL, testClientID, &AuthCodeFlow{
+		// The browser shows the error page of the redirect
+		OpenURL: func(u string) error { _ = visit(u); return nil },
+		Output:  io.Discard,
+	})
+	if err == nil || !strings.Contains(err.Error(), "access_denied") {
+		t.Errorf("expected access to be denied, got %v", err)
+	}
+
+	// A user who never completes the flow
+	timeout, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, err = GetIDToken(timeout, m.server.URL, testClientID, &AuthCodeFlow{
+		OpenURL: func(string) error { return nil },
+		Output:  io.Discard,
+	})
+	if err == nil {
+		t.Error("expected the flow to time out")
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package policy evaluates issuance policies, which are CEL expressions that
+// must hold for a certificate to be issued for an identity.
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/sigstore/fulcio/pkg/certificate"
+)
+
+// Rule is a named CEL expression that must evaluate to true for a
+// certificate to be issued. Expressions can refer to the following
+// variables:
+//
+//   - claims: the claims of the OIDC token, e.g. claims.repository_owner
+//   - extensions: the Fulcio certificate extensions the certificate would
+//     carry, keyed by field name, e.g. extensions.SourceRepositoryOwnerURI
+//   - subject: the email or subject of the token, as signed in the proof of
+//     possession
+type Rule struct {
+	Name       string
+	Expression string
+}
+
+// Input is the identity a policy is evaluated against.
+type Input struct {
+	Claims     map[string]any
+	Extensions certificate.Extensions
+	Subject    string
+}
+
+// DeniedError is returned by Policy.Evaluate when a rule does not hold.
+type DeniedError struct {
+	// Rule is the name of the rule that denied issuance
+	Rule string
+	// Err is set if the rule could not be evaluated, for example because
+	// it refers to a claim that is not in the token
+	Err error
+}
+
+func (e *DeniedError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("issuance policy rule %q could not be evaluated: %v", e.Rule, e.Err)
+	}
+	return fmt.Sprintf("issuance policy rule %q denied the request", e.Rule)
+}
+
+func (e *DeniedError) Unwrap() error {
+	return e.Err
+}
+
+// Policy is a compiled set of rules, all of which must hold.
+type Policy struct {
+	rules    []Rule
+	programs []cel.Program
+}
+
+var (
+	envOnce sync.Once
+	env     *cel.Env
+	envErr  error
+)
+
+func celEnv() (*cel.Env, error) {
+	envOnce.Do(func() {
+		env, envErr = cel.NewEnv(
+			cel.Variable("claims", cel.MapType(cel.StringType, cel.DynType)),
+			cel.Variable("extensions", cel.MapType(cel.StringType, cel.StringType)),
+			cel.Variable("subject", cel.StringType),
+		)
+	})
+	return env, envErr
+}
+
+func compile(expression string) (cel.Program, error) {
+	e, err := celEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := e.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("expression must evaluate to a bool, not %s", ast.OutputType())
+	}
+	prg, err := e.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+	return prg, nil
+}
+
+// New compiles rules into a Policy. Rules must have unique, non-empty names.
+func New(rules []Rule) (*Policy, error) {
+	p := &Policy{}
+	names := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		if r.Name == "" {
+			return nil, errors.New("issuance policy rule must have a name")
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("duplicate issuance policy rule %q", r.Name)
+		}
+		names[r.Name] = true
+
+		prg, err := compile(r.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("issuance policy rule %q: %w", r.Name, err)
+		}
+		p.rules = append(p.rules, r)
+		p.programs = append(p.programs, prg)
+	}
+	return p, nil
+}
+
+// Evaluate returns a *DeniedError naming the first rule that does not hold
+// for in, or nil if all rules hold. Rules that fail to evaluate deny
+// issuance.
+func (p *Policy) Evaluate(in Input) error {
+	if len(p.rules) == 0 {
+		return nil
+	}
+	exts, err := extensionsMap(in.Extensions)
+	if err != nil {
+		return err
+	}
+	claims := in.Claims
+	if claims == nil {
+		claims = map[string]any{}
+	}
+	vars := map[string]any{
+		"claims":     claims,
+		"extensions": exts,
+		"subject":    in.Subject,
+	}
+	for i, prg := range p.programs {
+		out, _, err := prg.Eval(vars)
+		if err != nil {
+			return &DeniedError{Rule: p.rules[i].Name, Err: err}
+		}
+		if allowed, ok := out.Value().(bool); !ok || !allowed {
+			return &DeniedError{Rule: p.rules[i].Name}
+		}
+	}
+	return nil
+}
+
+// extensionsMap returns the set extensions keyed by their field name.
+func extensionsMap(exts certificate.Extensions) (map[string]string, error) {
+	b, err := json.Marshal(exts)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]string{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	for k, v := range m {
+		if v == "" {
+			delete(m, k)
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sigstore/fulcio/pkg/certificate"
+)
+
+func TestNew(t *testing.T) {
+	tests := map[string]struct {
+		Rules   []Rule
+		WantErr bool
+	}{
+		"no rules": {},
+		"valid rules": {
+			Rules: []Rule{
+				{Name: "org", Expression: `claims.repository_owner in ["sigstore"]`},
+				{Name: "domain", Expression: `subject.endsWith("@example.com")`},
+			},
+		},
+		"missing name": {
+			Rules:   []Rule{{Expression: `true`}},
+			WantErr: true,
+		},
+		"duplicate name": {
+			Rules:   []Rule{{Name: "a", Expression: `true`}, {Name: "a", Expression: `false`}},
+			WantErr: true,
+		},
+		"syntax error": {
+			Rules:   []Rule{{Name: "a", Expression: `claims.foo ==`}},
+			WantErr: true,
+		},
+		"unknown variable": {
+			Rules:   []Rule{{Name: "a", Expression: `token.foo == "bar"`}},
+			WantErr: true,
+		},
+		"not a bool": {
+			Rules:   []Rule{{Name: "a", Expression: `subject`}},
+			WantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(test.Rules)
+			if (err != nil) != test.WantErr {
+				t.Errorf("New() error = %v, WantErr %v", err, test.WantErr)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	p, err := New([]Rule{
+		{Name: "org", Expression: `claims.repository_owner in ["sigstore", "chainguard-dev"]`},
+		{Name: "owner-extension", Expression: `extensions.SourceRepositoryOwnerURI.startsWith("https://github.com/")`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		Input      Input
+		DeniedRule string
+		EvalErr    bool
+	}{
+		"allowed": {
+			Input: Input{
+				Claims:     map[string]any{"repository_owner": "sigstore"},
+				Extensions: certificate.Extensions{SourceRepositoryOwnerURI: "https://github.com/sigstore"},
+			},
+		},
+		"denied by claim": {
+			Input: Input{
+				Claims:     map[string]any{"repository_owner": "someone-else"},
+				Extensions: certificate.Extensions{SourceRepositoryOwnerURI: "https://github.com/someone-else"},
+			},
+			DeniedRule: "org",
+		},
+		"denied by extension": {
+			Input: Input{
+				Claims:     map[string]any{"repository_owner": "sigstore"},
+				Extensions: certificate.Extensions{SourceRepositoryOwnerURI: "https://gitlab.com/sigstore"},
+			},
+			DeniedRule: "owner-extension",
+		},
+		"missing claim": {
+			Input: Input{
+				Extensions: certificate.Extensions{SourceRepositoryOwnerURI: "https://github.com/sigstore"},
+			},
+			DeniedRule: "org",
+			EvalErr:    true,
+		},
+		"missing extension": {
+			Input: Input{
+				Claims: map[string]any{"repository_owner": "sigstore"},
+			},
+			DeniedRule: "owner-extension",
+			EvalErr:    true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := p.Evaluate(test.Input)
+			if test.DeniedRule == "" {
+				if err != nil {
+					t.Fatalf("Evaluate() = %v", err)
+				}
+				return
+			}
+			var denied *DeniedError
+			if !errors.As(err, &denied) {
+				t.Fatalf("expected DeniedError, got %v", err)
+			}
+			if denied.Rule != test.DeniedRule {
+				t.Errorf("expected rule %q to deny, got %q", test.DeniedRule, denied.Rule)
+			}
+			if (denied.Err != nil) != test.EvalErr {
+				t.Errorf("unexpected evaluation error %v", denied.Err)
+			}
+		})
+	}
+}
+
+func TestEvaluateSubject(t *testing.T) {
+	p, err := New([]Rule{
+		{Name: "domain", Expression: `subject.endsWith("@example.com") && claims.email_verified == true`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Evaluate(Input{Subject: "alice@example.com", Claims: map[string]any{"email_verified": true}}); err != nil {
+		t.Errorf("Evaluate() = %v", err)
+	}
+	if err := p.Evaluate(Input{Subject: "mallory@example.org", Claims: map[string]any{"email_verified": true}}); err == nil {
+		t.Error("expected subject outside of domain to be denied")
+	}
+}
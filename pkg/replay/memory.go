@@ -0,0 +1,82 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package replay
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often expired keys are purged from a MemoryCache.
+const sweepInterval = time.Minute
+
+// MemoryCache is a Cache held in process memory. It only detects replays
+// against a single replica.
+type MemoryCache struct {
+	mu        sync.Mutex
+	entries   map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+var _ Cache = (*MemoryCache)(nil)
+
+// NewMemoryCache returns an empty MemoryCache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries:   make(map[string]time.Time),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (c *MemoryCache) Insert(_ context.Context, key string, expiry time.Time) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if now.Sub(c.lastSweep) >= sweepInterval {
+		for k, exp := range c.entries {
+			if !exp.After(now) {
+				delete(c.entries, k)
+			}
+		}
+		c.lastSweep = now
+	}
+
+	if exp, ok := c.entries[key]; ok && exp.After(now) {
+		return false, nil
+	}
+	c.entries[key] = expiry
+	return true, nil
+}
+
+// Delete forgets key.
+func (c *MemoryCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	return nil
+}
+
+// Len returns the number of keys held, including expired keys that have not
+// yet been purged.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
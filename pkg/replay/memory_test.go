@@ -0,0 +1,57 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package replay
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := NewMemoryCache()
+	c.now = func() time.Time { return now }
+
+	fresh, err := c.Insert(ctx, "a", now.Add(time.Minute))
+	if err != nil || !fresh {
+		t.Fatalf("Insert() = %v, %v; want true, nil", fresh, err)
+	}
+	fresh, err = c.Insert(ctx, "a", now.Add(time.Minute))
+	if err != nil || fresh {
+		t.Fatalf("Insert() = %v, %v; want false, nil", fresh, err)
+	}
+
+	// Keys can be reused once they expire
+	now = now.Add(2 * time.Minute)
+	fresh, err = c.Insert(ctx, "a", now.Add(time.Minute))
+	if err != nil || !fresh {
+		t.Fatalf("Insert() after expiry = %v, %v; want true, nil", fresh, err)
+	}
+
+	// Expired keys are purged
+	if _, err := c.Insert(ctx, "b", now.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * time.Minute)
+	if _, err := c.Insert(ctx, "c", now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if c.Len() != 1 {
+		t.Fatalf("expected expired keys to be purged, have %d keys", c.Len())
+	}
+}
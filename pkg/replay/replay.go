@@ -0,0 +1,135 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package replay detects OIDC identity tokens that are presented more than
+// once, so that a stolen token cannot be used to mint several certificates.
+package replay
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultTTL is how long a token is remembered when it carries no
+// expiration claim.
+const DefaultTTL = time.Hour
+
+// ErrReplayed is returned by Guard.Check when a token has already been used.
+var ErrReplayed = errors.New("identity token has already been used")
+
+// Cache records keys until they expire. Implementations must be safe for
+// concurrent use, and implementations shared by several replicas must make
+// Insert atomic across them.
+type Cache interface {
+	// Insert records key until expiry. It returns false if key is already
+	// recorded and has not yet expired.
+	Insert(ctx context.Context, key string, expiry time.Time) (bool, error)
+	// Delete forgets key, so that it can be inserted again.
+	Delete(ctx context.Context, key string) error
+}
+
+// Guard rejects tokens that have already been checked.
+type Guard struct {
+	cache Cache
+	now   func() time.Time
+}
+
+// NewGuard returns a Guard that records tokens in cache.
+func NewGuard(cache Cache) *Guard {
+	return &Guard{cache: cache, now: time.Now}
+}
+
+// Check records the token and returns ErrReplayed if it was recorded
+// before. Tokens are identified by their issuer and jti claim, or by their
+// hash if they have no jti claim, and remembered until they expire. The
+// token must already have been authenticated.
+func (g *Guard) Check(ctx context.Context, token string) error {
+	key, expiry, err := tokenKey(token)
+	if err != nil {
+		return err
+	}
+	if expiry.IsZero() {
+		expiry = g.now().Add(DefaultTTL)
+	}
+	if !expiry.After(g.now()) {
+		// Expired tokens fail authentication, so there is nothing to record
+		return nil
+	}
+	fresh, err := g.cache.Insert(ctx, key, expiry)
+	if err != nil {
+		return fmt.Errorf("recording identity token: %w", err)
+	}
+	if !fresh {
+		return ErrReplayed
+	}
+	return nil
+}
+
+// Release forgets a token recorded by Check. It is called when the request
+// that presented the token fails after the check, so that the client can
+// retry with the same token.
+func (g *Guard) Release(ctx context.Context, token string) error {
+	key, _, err := tokenKey(token)
+	if err != nil {
+		return err
+	}
+	if err := g.cache.Delete(ctx, key); err != nil {
+		return fmt.Errorf("releasing identity token: %w", err)
+	}
+	return nil
+}
+
+// tokenKey returns the key identifying a token in a Cache, and the token's
+// expiry if it has one.
+func tokenKey(token string) (string, time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", time.Time{}, errors.New("malformed jwt, token must have 3 parts")
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("malformed jwt payload: %w", err)
+	}
+	var claims struct {
+		Issuer string      `json:"iss"`
+		JTI    string      `json:"jti"`
+		Expiry json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to unmarshal claims: %w", err)
+	}
+
+	var expiry time.Time
+	if claims.Expiry != "" {
+		exp, err := claims.Expiry.Float64()
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("invalid exp claim: %w", err)
+		}
+		expiry = time.Unix(int64(exp), 0)
+	}
+
+	if claims.JTI != "" {
+		return "jti:" + claims.Issuer + "#" + claims.JTI, expiry, nil
+	}
+	sum := sha256.Sum256([]byte(token))
+	return "sha256:" + hex.EncodeToString(sum[:]), expiry, nil
+}
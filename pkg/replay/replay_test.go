@@ -0,0 +1,122 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package replay
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func makeToken(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString(payload) + ".c2ln"
+}
+
+func TestTokenKey(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	withJTI := makeToken(t, map[string]any{"iss": "https://issuer.example.com", "jti": "abc", "exp": exp})
+	key, expiry, err := tokenKey(withJTI)
+	if err != nil {
+		t.Fatalf("tokenKey() = %v", err)
+	}
+	if key != "jti:https://issuer.example.com#abc" {
+		t.Errorf("unexpected key %q", key)
+	}
+	if expiry.Unix() != exp {
+		t.Errorf("expected expiry %d, got %d", exp, expiry.Unix())
+	}
+
+	withoutJTI := makeToken(t, map[string]any{"iss": "https://issuer.example.com"})
+	key, expiry, err = tokenKey(withoutJTI)
+	if err != nil {
+		t.Fatalf("tokenKey() = %v", err)
+	}
+	if !strings.HasPrefix(key, "sha256:") {
+		t.Errorf("expected token hash key, got %q", key)
+	}
+	if !expiry.IsZero() {
+		t.Errorf("expected no expiry, got %v", expiry)
+	}
+
+	if _, _, err := tokenKey("not-a-jwt"); err == nil {
+		t.Error("expected malformed token to fail")
+	}
+}
+
+func TestGuard(t *testing.T) {
+	ctx := context.Background()
+	guard := NewGuard(NewMemoryCache())
+	exp := time.Now().Add(time.Hour).Unix()
+
+	tok := makeToken(t, map[string]any{"iss": "https://issuer.example.com", "jti": "abc", "exp": exp})
+	if err := guard.Check(ctx, tok); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if err := guard.Check(ctx, tok); !errors.Is(err, ErrReplayed) {
+		t.Fatalf("expected replay to be rejected, got %v", err)
+	}
+
+	// The same jti from another issuer is a different token
+	other := makeToken(t, map[string]any{"iss": "https://other.example.com", "jti": "abc", "exp": exp})
+	if err := guard.Check(ctx, other); err != nil {
+		t.Fatalf("token from other issuer: %v", err)
+	}
+
+	// Tokens without jti are identified by their hash
+	noJTI := makeToken(t, map[string]any{"iss": "https://issuer.example.com", "sub": "foo", "exp": exp})
+	if err := guard.Check(ctx, noJTI); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if err := guard.Check(ctx, noJTI); !errors.Is(err, ErrReplayed) {
+		t.Fatalf("expected replay to be rejected, got %v", err)
+	}
+
+	// Released tokens can be used again
+	if err := guard.Release(ctx, noJTI); err != nil {
+		t.Fatalf("Release() = %v", err)
+	}
+	if err := guard.Check(ctx, noJTI); err != nil {
+		t.Fatalf("use after release: %v", err)
+	}
+}
+
+type failingCache struct{}
+
+func (failingCache) Insert(context.Context, string, time.Time) (bool, error) {
+	return false, errors.New("unavailable")
+}
+
+func (failingCache) Delete(context.Context, string) error {
+	return errors.New("unavailable")
+}
+
+func TestGuardCacheError(t *testing.T) {
+	guard := NewGuard(failingCache{})
+	tok := makeToken(t, map[string]any{"iss": "https://issuer.example.com", "jti": "abc"})
+	err := guard.Check(context.Background(), tok)
+	if err == nil || errors.Is(err, ErrReplayed) {
+		t.Fatalf("expected cache error, got %v", err)
+	}
+}
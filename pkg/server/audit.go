@@ -0,0 +1,83 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	"github.com/sigstore/fulcio/pkg/audit"
+	fulciogrpc "github.com/sigstore/fulcio/pkg/generated/protobuf"
+	"github.com/sigstore/fulcio/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type auditDecisionKey struct{}
+
+// withAuditDecision returns a context in which the handling of a request
+// records its details in d.
+func withAuditDecision(ctx context.Context, d *audit.Decision) context.Context {
+	return context.WithValue(ctx, auditDecisionKey{}, d)
+}
+
+// auditDecision returns the decision in which the details of the request
+// in ctx are recorded. If the request is not audited, a decision that is
+// discarded is returned.
+func auditDecision(ctx context.Context) *audit.Decision {
+	if d, ok := ctx.Value(auditDecisionKey{}).(*audit.Decision); ok {
+		return d
+	}
+	return &audit.Decision{}
+}
+
+// auditedCreateSigningCertificate handles a request with
+// createSigningCertificate and records the outcome in the audit sink.
+// Certificates are not returned if their issuance cannot be recorded. As the
+// certificate is signed, logged and notified by then, an attempt recorded
+// by recordAttempt is all that remains of such a certificate in the audit
+// log.
+func (g *grpcaCAServer) auditedCreateSigningCertificate(ctx context.Context, request *fulciogrpc.CreateSigningCertificateRequest) (*fulciogrpc.SigningCertificate, error) {
+	d := &audit.Decision{}
+	result, err := g.createSigningCertificate(withAuditDecision(ctx, d), request)
+
+	st := status.Convert(err)
+	d.Code = st.Code().String()
+	if err != nil {
+		d.Outcome = audit.OutcomeRejected
+		d.Reason = st.Message()
+	} else {
+		d.Outcome = audit.OutcomeAccepted
+	}
+	if auditErr := g.audit.Record(ctx, d); auditErr != nil {
+		if err != nil {
+			// The request already failed, so only its outcome is lost
+			log.ContextLogger(ctx).Errorf("error recording rejected request in audit log: %v", auditErr)
+			return nil, err
+		}
+		return nil, handleFulcioGRPCError(ctx, codes.Internal, auditErr, failedToAuditDecision)
+	}
+	return result, err
+}
+
+// recordAttempt records that the request in ctx is about to be signed, so
+// that no certificate is issued without an entry in the audit log.
+func (g *grpcaCAServer) recordAttempt(ctx context.Context) error {
+	if g.audit == nil {
+		return nil
+	}
+	d := *auditDecision(ctx)
+	d.Outcome = audit.OutcomeAttempted
+	return g.audit.Record(ctx, &d)
+}
@@ -0,0 +1,162 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/sigstore/fulcio/pkg/audit"
+	"github.com/sigstore/fulcio/pkg/config"
+	"github.com/sigstore/fulcio/pkg/generated/protobuf"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// recordingSink keeps decisions in memory, failing if err is set.
+type recordingSink struct {
+	mu        sync.Mutex
+	decisions []audit.Decision
+	err       error
+}
+
+func (s *recordingSink) Record(_ context.Context, d *audit.Decision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.decisions = append(s.decisions, *d)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	return nil
+}
+
+func TestAPIWithAuditSink(t *testing.T) {
+	emailSigner, emailIssuer := newOIDCIssuer(t)
+
+	cfg, err := config.Read([]byte(fmt.Sprintf(`{
+		"OIDCIssuers": {
+			%q: {
+				"IssuerURL": %q,
+				"ClientID": "sigstore",
+				"Type": "email"
+			}
+		}
+	}`, emailIssuer, emailIssuer)))
+	if err != nil {
+		t.Fatalf("config.Read() = %v", err)
+	}
+	ctLogs, eca := createCA(cfg, t)
+
+	sink := &recordingSink{}
+	server, conn := setupGRPCForTest(t, cfg, ctLogs, eca, WithAuditSink(sink))
+	defer func() {
+		server.Stop()
+		conn.Close()
+	}()
+	client := protobuf.NewCAClient(conn)
+
+	request := func(token, proofSubject string) (*protobuf.SigningCertificate, error) {
+		pubBytes, proof := generateKeyAndProof(proofSubject, t)
+		return client.CreateSigningCertificate(context.Background(), &protobuf.CreateSigningCertificateRequest{
+			Credentials: &protobuf.Credentials{
+				Credentials: &protobuf.Credentials_OidcIdentityToken{
+					OidcIdentityToken: token,
+				},
+			},
+			Key: &protobuf.CreateSigningCertificateRequest_PublicKeyRequest{
+				PublicKeyRequest: &protobuf.PublicKeyRequest{
+					PublicKey: &protobuf.PublicKey{
+						Content: pubBytes,
+					},
+					ProofOfPossession: proof,
+				},
+			},
+		})
+	}
+
+	token := emailToken(t, emailSigner, emailIssuer)
+	resp, err := request(token, "foo@example.com")
+	if err != nil {
+		t.Fatalf("SigningCert() = %v", err)
+	}
+	certs, err := cryptoutils.UnmarshalCertificatesFromPEM([]byte(resp.GetSignedCertificateEmbeddedSct().GetChain().GetCertificates()[0]))
+	if err != nil {
+		t.Fatalf("UnmarshalCertificatesFromPEM() = %v", err)
+	}
+
+	// A proof of possession over the wrong subject is rejected after the
+	// token was authenticated
+	if _, err := request(token, "bar@example.com"); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+	// An invalid token is rejected before it is authenticated
+	if _, err := request("invalid", "foo@example.com"); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+
+	want := []audit.Decision{{
+		Outcome: audit.OutcomeAttempted,
+		Issuer:  emailIssuer,
+		Subject: "foo@example.com",
+	}, {
+		Outcome:      audit.OutcomeAccepted,
+		Code:         codes.OK.String(),
+		Issuer:       emailIssuer,
+		Subject:      "foo@example.com",
+		SerialNumber: certs[0].SerialNumber.Text(16),
+	}, {
+		Outcome: audit.OutcomeRejected,
+		Code:    codes.InvalidArgument.String(),
+		Reason:  invalidSignature,
+		Issuer:  emailIssuer,
+		Subject: "foo@example.com",
+	}, {
+		Outcome: audit.OutcomeRejected,
+		Code:    codes.InvalidArgument.String(),
+		Reason:  invalidIdentityToken,
+	}}
+	if len(sink.decisions) != len(want) {
+		t.Fatalf("expected %d decisions, got %+v", len(want), sink.decisions)
+	}
+	for i, got := range sink.decisions {
+		if got.Outcome == audit.OutcomeRejected && got.Error == "" {
+			t.Errorf("decision %d: expected the cause of the rejection", i)
+		}
+		got.Error = ""
+		if got != want[i] {
+			t.Errorf("decision %d: expected %+v, got %+v", i, want[i], got)
+		}
+	}
+
+	// Certificates are not returned if their issuance cannot be recorded
+	sink.mu.Lock()
+	sink.err = errors.New("disk full")
+	sink.mu.Unlock()
+	// and nothing is signed if the attempt cannot be recorded
+	if _, err := request(token, "foo@example.com"); status.Code(err) != codes.Internal || status.Convert(err).Message() != failedToAuditDecision {
+		t.Errorf("expected Internal, got %v", err)
+	}
+	if _, err := request("invalid", "foo@example.com"); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
@@ -24,13 +24,18 @@ import (
 )
 
 const (
-	invalidSignature       = "The signature supplied in the request could not be verified"
-	invalidPublicKey       = "The public key supplied in the request could not be parsed"
-	invalidCSR             = "The certificate signing request could not be parsed"
-	failedToEnterCertInCTL = "Error entering certificate in CTL"
-	failedToMarshalSCT     = "Error marshaling signed certificate timestamp"
-	failedToMarshalCert    = "Error marshaling code signing certificate"
-	insecurePublicKey      = "The public key supplied in the request is insecure"
+	invalidSignature         = "The signature supplied in the request could not be verified"
+	invalidPublicKey         = "The public key supplied in the request could not be parsed"
+	invalidCSR               = "The certificate signing request could not be parsed"
+	failedToEnterCertInCTL   = "Error entering certificate in CTL"
+	failedToMarshalSCT       = "Error marshaling signed certificate timestamp"
+	failedToMarshalCert      = "Error marshaling code signing certificate"
+	insecurePublicKey        = "The public key supplied in the request is insecure"
+	invalidRequestedValidity = "The requested certificate validity is invalid"
+	invalidChallenge         = "The challenge supplied in the request is invalid or expired"
+	challengeRequired        = "The identity token's issuer requires proof of possession over a challenge from GetChallenge"
+	tokenReplayed            = "The identity token has already been used to request a certificate"
+	policyDenied             = "The request was denied by issuance policy rule %q"
 	// nolint:gosec // false positive G101
 	invalidCredentials = "There was an error processing the credentials for this request" //lint:ignore U1000 Used in past
 	// nolint:gosec // false positive G101
@@ -39,9 +44,22 @@ const (
 	retrieveTrustBundleCAError              = "error retrieving trust bundle from CA backend"
 	marshalingCertificateChainBundleCAError = "error marshaling the certificate chain of the bundle"
 	loadingFulcioConfigurationError         = "error loading fulcio configuration"
+	issueChallengeError                     = "error issuing challenge"
+	replayCheckError                        = "error checking identity token for reuse"
+	policyEvaluationError                   = "error evaluating issuance policy"
+	failedToRecordCert                      = "error recording certificate in issuance ledger"
+	ledgerNotEnabled                        = "the issuance ledger is not enabled"
+	queryingLedgerError                     = "error querying issuance ledger"
+	adminTokenRequired                      = "an administrator's identity token is required"
+	notAnAdmin                              = "the identity is not an administrator"
+	invalidSerialNumber                     = "the serial number is invalid"
+	invalidPageToken                        = "the page token is invalid"
+	certificateNotFound                     = "no certificate with the serial number was issued"
+	failedToAuditDecision                   = "error recording certificate in audit log"
 )
 
 func handleFulcioGRPCError(ctx context.Context, code codes.Code, err error, message string, fields ...interface{}) error {
+	auditDecision(ctx).Error = err.Error()
 	// Use log level "warning" for codes that are likely client errors, see https://grpc.github.io/grpc/core/md_doc_statuscodes.html
 	switch code {
 	case codes.InvalidArgument,
@@ -60,3 +78,10 @@ func handleFulcioGRPCError(ctx context.Context, code codes.Code, err error, mess
 	}
 	return status.Error(code, message)
 }
+
+// rejectRequest counts a certificate request rejected for reason, one of
+// the reject constants, and returns the error from handleFulcioGRPCError.
+func rejectRequest(ctx context.Context, reason string, code codes.Code, err error, message string) error {
+	metricRequestsRejected.WithLabelValues(reason).Inc()
+	return handleFulcioGRPCError(ctx, code, err, message)
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// ExtractOIDCTokenFromAuthHeader passes the bearer token of an HTTP request
+// to the gRPC server, for use with runtime.WithMetadata.
+func ExtractOIDCTokenFromAuthHeader(_ context.Context, req *http.Request) metadata.MD {
+	token := strings.Replace(req.Header.Get("Authorization"), "Bearer ", "", 1)
+	return metadata.Pairs(MetadataOIDCTokenKey, token)
+}
+
+// SetResponseCodeModifier sets the SCT header and status code of HTTP
+// responses from the gRPC metadata the server sets for them, and strips the
+// other gRPC headers, for use with runtime.WithForwardResponseOption.
+func SetResponseCodeModifier(ctx context.Context, w http.ResponseWriter, _ proto.Message) error {
+	md, ok := runtime.ServerMetadataFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	// set SCT if present ahead of modifying response code
+	if vals := md.HeaderMD.Get(SCTMetadataKey); len(vals) > 0 {
+		delete(md.HeaderMD, SCTMetadataKey)
+		delete(w.Header(), "Grpc-Metadata-sct")
+		w.Header().Set("SCT", vals[0])
+	}
+
+	// strip all GRPC response headers
+	for headerKey := range w.Header() {
+		if strings.HasPrefix(headerKey, "Grpc-") {
+			delete(w.Header(), headerKey)
+		}
+	}
+
+	// set http status code
+	if vals := md.HeaderMD.Get(HTTPResponseCodeMetadataKey); len(vals) > 0 {
+		code, err := strconv.Atoi(vals[0])
+		if err != nil {
+			return err
+		}
+		// delete the headers to not expose any grpc-metadata in http response
+		delete(md.HeaderMD, HTTPResponseCodeMetadataKey)
+		w.WriteHeader(code)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestExtractOIDCTokenFromAuthHeader(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/signingCert", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	md := ExtractOIDCTokenFromAuthHeader(context.Background(), req)
+	if got := md.Get(MetadataOIDCTokenKey); len(got) != 1 || got[0] != "token" {
+		t.Errorf("unexpected token metadata %v", got)
+	}
+}
+
+func TestSetResponseCodeModifier(t *testing.T) {
+	md := runtime.ServerMetadata{HeaderMD: metadata.Pairs(
+		SCTMetadataKey, "sct",
+		HTTPResponseCodeMetadataKey, "201",
+	)}
+	ctx := runtime.NewServerMetadataContext(context.Background(), md)
+	w := httptest.NewRecorder()
+	w.Header().Set("Grpc-Metadata-Content-Type", "application/grpc")
+	if err := SetResponseCodeModifier(ctx, w, nil); err != nil {
+		t.Fatalf("SetResponseCodeModifier() = %v", err)
+	}
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	if got := w.Header().Get("SCT"); got != "sct" {
+		t.Errorf("expected SCT header, got %q", got)
+	}
+	if got := w.Header().Get("Grpc-Metadata-Content-Type"); got != "" {
+		t.Errorf("expected gRPC headers to be stripped, got %q", got)
+	}
+}
@@ -23,24 +23,35 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
-	ctclient "github.com/google/certificate-transparency-go/client"
 	health "google.golang.org/grpc/health/grpc_health_v1"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
-	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/sigstore/fulcio/pkg/audit"
 	certauth "github.com/sigstore/fulcio/pkg/ca"
+	"github.com/sigstore/fulcio/pkg/certificate"
 	"github.com/sigstore/fulcio/pkg/challenges"
 	"github.com/sigstore/fulcio/pkg/config"
 	"github.com/sigstore/fulcio/pkg/ctl"
 	fulciogrpc "github.com/sigstore/fulcio/pkg/generated/protobuf"
+	healthmon "github.com/sigstore/fulcio/pkg/health"
 	"github.com/sigstore/fulcio/pkg/identity"
+	"github.com/sigstore/fulcio/pkg/ledger"
 	"github.com/sigstore/fulcio/pkg/log"
+	"github.com/sigstore/fulcio/pkg/notify"
+	"github.com/sigstore/fulcio/pkg/policy"
+	"github.com/sigstore/fulcio/pkg/replay"
+	"github.com/sigstore/fulcio/pkg/tracing"
 	"github.com/sigstore/sigstore/pkg/cryptoutils"
 	"github.com/sigstore/sigstore/pkg/cryptoutils/goodkey"
 	"github.com/sigstore/sigstore/pkg/signature"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type GRPCCAServer interface {
@@ -48,13 +59,103 @@ type GRPCCAServer interface {
 	health.HealthServer
 }
 
-func NewGRPCCAServer(ct *ctclient.LogClient, ca certauth.CertificateAuthority, algorithmRegistry *signature.AlgorithmRegistryConfig, ip identity.IssuerPool) GRPCCAServer {
-	return &grpcaCAServer{
+// GRPCCAServerOption configures optional behavior of the server returned by
+// NewGRPCCAServer.
+type GRPCCAServerOption func(*grpcaCAServer)
+
+// WithNonceIssuer sets the issuer of the nonces returned by GetChallenge. By
+// default nonces are signed with a random per-process key, so replicas behind
+// a load balancer must share a NonceIssuer key.
+func WithNonceIssuer(n *challenges.NonceIssuer) GRPCCAServerOption {
+	return func(g *grpcaCAServer) {
+		g.nonces = n
+	}
+}
+
+// WithReplayCache sets the cache in which tokens are recorded for issuers
+// with replay protection enabled. By default tokens are recorded in memory,
+// so replicas behind a load balancer must share a Cache to detect replays
+// across them.
+func WithReplayCache(c replay.Cache) GRPCCAServerOption {
+	return func(g *grpcaCAServer) {
+		g.replay = replay.NewGuard(c)
+	}
+}
+
+// WithConfigReloader authenticates tokens against the IssuerPool of the
+// config that r.With set on the request context, rather than the fixed
+// IssuerPool passed to NewGRPCCAServer.
+func WithConfigReloader(r *ConfigReloader) GRPCCAServerOption {
+	return func(g *grpcaCAServer) {
+		g.issuerPool = r.issuerPoolFor
+	}
+}
+
+// WithCTQueue issues certificates while the CT logs are unavailable, rather
+// than failing the request. Such certificates have no SCT, and are queued in
+// q for submission once the logs recover.
+func WithCTQueue(q *ctl.Queue) GRPCCAServerOption {
+	return func(g *grpcaCAServer) {
+		g.ctQueue = q
+	}
+}
+
+// WithLedger records every issued certificate in s, and serves the
+// ListIssuedCertificates and GetIssuedCertificate RPCs from it. Certificates
+// are not issued if they cannot be recorded.
+func WithLedger(s ledger.Store) GRPCCAServerOption {
+	return func(g *grpcaCAServer) {
+		g.ledger = s
+	}
+}
+
+// WithLedgerAdmins allows the identities to query the issuance ledger, with
+// identity tokens from the OIDC issuer issuerURL. Identities are matched
+// against the name of the token's principal, such as an email address.
+func WithLedgerAdmins(issuerURL string, identities []string) GRPCCAServerOption {
+	return func(g *grpcaCAServer) {
+		g.adminIssuer = issuerURL
+		g.admins = identities
+	}
+}
+
+// WithAuditSink records the outcome of every CreateSigningCertificate
+// request in s. Certificates are not returned if their issuance cannot be
+// recorded.
+func WithAuditSink(s audit.Sink) GRPCCAServerOption {
+	return func(g *grpcaCAServer) {
+		g.audit = s
+	}
+}
+
+// WithNotifier posts an event to n's endpoints for every issued
+// certificate. Events are delivered in the background, and never delay or
+// fail issuance.
+func WithNotifier(n *notify.Notifier) GRPCCAServerOption {
+	return func(g *grpcaCAServer) {
+		g.notifier = n
+	}
+}
+
+func NewGRPCCAServer(ct *ctl.MultiLog, ca certauth.CertificateAuthority, algorithmRegistry *signature.AlgorithmRegistryConfig, ip identity.IssuerPool, opts ...GRPCCAServerOption) GRPCCAServer {
+	g := &grpcaCAServer{
 		ct:                ct,
 		ca:                ca,
 		algorithmRegistry: algorithmRegistry,
-		IssuerPool:        ip,
+		issuerPool:        func(context.Context) identity.IssuerPool { return ip },
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.nonces == nil {
+		// Only fails if the system's random source is unavailable, in
+		// which case GetChallenge reports the service as unavailable
+		g.nonces, _ = challenges.NewEphemeralNonceIssuer(challenges.DefaultNonceTTL)
 	}
+	if g.replay == nil {
+		g.replay = replay.NewGuard(replay.NewMemoryCache())
+	}
+	return g
 }
 
 const (
@@ -63,14 +164,31 @@ const (
 
 type grpcaCAServer struct {
 	fulciogrpc.UnimplementedCAServer
-	ct                *ctclient.LogClient
+	ct                *ctl.MultiLog
+	ctQueue           *ctl.Queue
 	ca                certauth.CertificateAuthority
 	algorithmRegistry *signature.AlgorithmRegistryConfig
-	identity.IssuerPool
+	nonces            *challenges.NonceIssuer
+	replay            *replay.Guard
+	issuerPool        func(context.Context) identity.IssuerPool
+	ledger            ledger.Store
+	adminIssuer       string
+	admins            []string
+	audit             audit.Sink
+	notifier          *notify.Notifier
+	health            *healthmon.Monitor
 }
 
 func (g *grpcaCAServer) CreateSigningCertificate(ctx context.Context, request *fulciogrpc.CreateSigningCertificateRequest) (*fulciogrpc.SigningCertificate, error) {
+	if g.audit != nil {
+		return g.auditedCreateSigningCertificate(ctx, request)
+	}
+	return g.createSigningCertificate(ctx, request)
+}
+
+func (g *grpcaCAServer) createSigningCertificate(ctx context.Context, request *fulciogrpc.CreateSigningCertificateRequest) (*fulciogrpc.SigningCertificate, error) {
 	logger := log.ContextLogger(ctx)
+	decision := auditDecision(ctx)
 
 	// OIDC token either is passed in gRPC field or was extracted from HTTP headers
 	token := ""
@@ -79,18 +197,39 @@ func (g *grpcaCAServer) CreateSigningCertificate(ctx context.Context, request *f
 	}
 
 	if token == "" {
-		if md, ok := metadata.FromIncomingContext(ctx); ok {
-			vals := md.Get(MetadataOIDCTokenKey)
-			if len(vals) == 1 {
-				token = vals[0]
-			}
-		}
+		token = tokenFromMetadata(ctx)
 	}
+	// Recorded before authentication, so that rejected tokens are audited
+	// with their claimed issuer
+	decision.Issuer, _ = identity.ExtractIssuerURL(token)
+	issuerType, configuredIssuer, knownIssuer := issuerLabels(ctx, decision.Issuer)
 
 	// Authenticate OIDC ID token by checking signature
-	principal, err := g.Authenticate(ctx, token)
+	authCtx, span := tracing.Start(ctx, "authenticate")
+	start := time.Now()
+	principal, err := g.issuerPool(ctx).Authenticate(authCtx, token)
+	metricTokenVerificationDuration.WithLabelValues(issuerType, metricResult(err)).Observe(time.Since(start).Seconds())
+	tracing.End(span, err)
 	if err != nil {
-		return nil, handleFulcioGRPCError(ctx, codes.InvalidArgument, err, invalidIdentityToken)
+		reason := rejectInvalidToken
+		if decision.Issuer != "" && !knownIssuer {
+			reason = rejectUnknownIssuer
+		}
+		return nil, rejectRequest(ctx, reason, codes.InvalidArgument, err, invalidIdentityToken)
+	}
+	decision.Subject = principal.Name(ctx)
+
+	// Look up the issuer's configuration for per-issuer issuance settings
+	issuer, err := issuerConfigForToken(ctx, token)
+	if err != nil {
+		return nil, rejectRequest(ctx, rejectInvalidToken, codes.InvalidArgument, err, invalidIdentityToken)
+	}
+
+	// Determine the validity window of the certificate from the issuer's
+	// configuration and the lifetime requested by the client
+	ctx, err = withCertificateValidity(ctx, issuer, request.GetRequestedValidity())
+	if err != nil {
+		return nil, rejectRequest(ctx, rejectInvalidRequest, codes.InvalidArgument, err, invalidRequestedValidity)
 	}
 
 	var publicKey crypto.PublicKey
@@ -98,26 +237,33 @@ func (g *grpcaCAServer) CreateSigningCertificate(ctx context.Context, request *f
 	// Verify caller is in possession of their private key and extract
 	// public key from request.
 	if len(request.GetCertificateSigningRequest()) > 0 {
+		// A CSR's self-signature cannot cover a server-issued challenge
+		if issuer.RequireChallenge {
+			err := errors.New("issuer requires proof of possession over a challenge, which a CSR cannot provide")
+			return nil, rejectRequest(ctx, rejectProofOfPossession, codes.InvalidArgument, err, challengeRequired)
+		}
+
 		// Option 1: Verify CSR
 		csr, err := cryptoutils.ParseCSR(request.GetCertificateSigningRequest())
 		if err != nil {
-			return nil, handleFulcioGRPCError(ctx, codes.InvalidArgument, err, invalidCSR)
+			return nil, rejectRequest(ctx, rejectInvalidRequest, codes.InvalidArgument, err, invalidCSR)
 		}
 
 		// Parse public key and check for weak key parameters
 		publicKey = csr.PublicKey
 		if err := goodkey.ValidatePubKey(publicKey); err != nil {
-			return nil, handleFulcioGRPCError(ctx, codes.InvalidArgument, err, insecurePublicKey)
+			return nil, rejectRequest(ctx, rejectInvalidRequest, codes.InvalidArgument, err, insecurePublicKey)
 		}
 
 		if err := csr.CheckSignature(); err != nil {
-			return nil, handleFulcioGRPCError(ctx, codes.InvalidArgument, err, invalidSignature)
+			return nil, rejectRequest(ctx, rejectProofOfPossession, codes.InvalidArgument, err, invalidSignature)
 		}
 
 		hashFunc, err = getHashFuncForSignatureAlgorithm(csr.SignatureAlgorithm)
 		if err != nil {
-			return nil, handleFulcioGRPCError(ctx, codes.InvalidArgument, err, err.Error())
+			return nil, rejectRequest(ctx, rejectAlgorithmNotPermitted, codes.InvalidArgument, err, err.Error())
 		}
+		ctx = certauth.WithCertificateRequest(ctx, csr)
 	} else {
 		// Option 2: Check the signature for proof of possession of a private key
 		var (
@@ -135,141 +281,352 @@ func (g *grpcaCAServer) CreateSigningCertificate(ctx context.Context, request *f
 		// Parse public key and check for weak parameters
 		publicKey, err = challenges.ParsePublicKey(pubKeyContent)
 		if err != nil {
-			return nil, handleFulcioGRPCError(ctx, codes.InvalidArgument, err, invalidPublicKey)
+			return nil, rejectRequest(ctx, rejectInvalidRequest, codes.InvalidArgument, err, invalidPublicKey)
 		}
 		if err := goodkey.ValidatePubKey(publicKey); err != nil {
-			return nil, handleFulcioGRPCError(ctx, codes.InvalidArgument, err, insecurePublicKey)
+			return nil, rejectRequest(ctx, rejectInvalidRequest, codes.InvalidArgument, err, insecurePublicKey)
 		}
 
 		proofOfPossessionAlgo, err := signature.GetDefaultAlgorithmDetails(publicKey)
 		if err != nil {
-			return nil, handleFulcioGRPCError(ctx, codes.InvalidArgument, err, err.Error())
+			return nil, rejectRequest(ctx, rejectAlgorithmNotPermitted, codes.InvalidArgument, err, err.Error())
 		}
 		verifier, err := signature.LoadDefaultVerifier(publicKey)
 		if err != nil {
-			return nil, handleFulcioGRPCError(ctx, codes.InvalidArgument, err, err.Error())
+			return nil, rejectRequest(ctx, rejectInvalidRequest, codes.InvalidArgument, err, err.Error())
 		}
 		// TODO: Ideally this comes from the verifier
 		hashFunc = proofOfPossessionAlgo.GetHashType()
 
-		// Check proof of possession signature
-		if err := challenges.CheckSignatureWithVerifier(verifier, proofOfPossession, principal.Name(ctx)); err != nil {
-			return nil, handleFulcioGRPCError(ctx, codes.InvalidArgument, err, invalidSignature)
+		// Check proof of possession signature, which is over the
+		// server-issued challenge if one was provided and over the
+		// principal's name otherwise
+		signed := principal.Name(ctx)
+		if challenge := request.GetPublicKeyRequest().GetChallenge(); challenge != "" {
+			if g.nonces == nil {
+				return nil, rejectRequest(ctx, rejectInternalError, codes.Unavailable, errors.New("no nonce issuer configured"), invalidChallenge)
+			}
+			if err := g.nonces.Verify(challenge); err != nil {
+				return nil, rejectRequest(ctx, rejectProofOfPossession, codes.InvalidArgument, err, invalidChallenge)
+			}
+			signed = challenge
+		} else if issuer.RequireChallenge {
+			err := errors.New("issuer requires proof of possession over a challenge")
+			return nil, rejectRequest(ctx, rejectProofOfPossession, codes.InvalidArgument, err, challengeRequired)
+		}
+		if err := challenges.CheckSignatureWithVerifier(verifier, proofOfPossession, signed); err != nil {
+			return nil, rejectRequest(ctx, rejectProofOfPossession, codes.InvalidArgument, err, invalidSignature)
 		}
 	}
 
 	// Check whether the public-key/hash algorithm combination is allowed
 	isPermitted, err := g.algorithmRegistry.IsAlgorithmPermitted(publicKey, hashFunc)
 	if err != nil {
-		return nil, handleFulcioGRPCError(ctx, codes.InvalidArgument, err, err.Error())
+		return nil, rejectRequest(ctx, rejectAlgorithmNotPermitted, codes.InvalidArgument, err, err.Error())
 	}
 	if !isPermitted {
 		err = fmt.Errorf("signing algorithm not permitted: %T, %s", publicKey, hashFunc)
-		return nil, handleFulcioGRPCError(ctx, codes.InvalidArgument, err, err.Error())
+		return nil, rejectRequest(ctx, rejectAlgorithmNotPermitted, codes.InvalidArgument, err, err.Error())
 	}
 
-	var csc *certauth.CodeSigningCertificate
-	var sctBytes []byte
-	result := &fulciogrpc.SigningCertificate{}
-	// For CAs that do not support embedded SCTs or if the CT log is not configured
-	if sctCa, ok := g.ca.(certauth.EmbeddedSCTCA); !ok || g.ct == nil {
-		// currently configured CA doesn't support pre-certificate flow required to embed SCT in final certificate
-		csc, err = g.ca.CreateCertificate(ctx, principal, publicKey)
-		if err != nil {
-			// if the error was due to invalid input in the request, return HTTP 400
-			if _, ok := err.(certauth.ValidationError); ok {
-				return nil, handleFulcioGRPCError(ctx, codes.InvalidArgument, err, err.Error())
+	// Enforce the issuer's issuance policy before anything is signed
+	if len(issuer.IssuancePolicy) > 0 {
+		if err := evaluateIssuancePolicy(ctx, issuer, token, principal); err != nil {
+			var denied *policy.DeniedError
+			if errors.As(err, &denied) {
+				metricPolicyDenials.WithLabelValues(configuredIssuer, denied.Rule).Inc()
+				return nil, rejectRequest(ctx, rejectPolicyDenied, codes.PermissionDenied, err, fmt.Sprintf(policyDenied, denied.Rule))
 			}
-			err = fmt.Errorf("error creating certificate: %w", err)
-			// otherwise return a 500 error to reflect that it is a transient server issue that the client can't resolve
-			return nil, handleFulcioGRPCError(ctx, codes.Internal, err, genericCAError)
+			return nil, rejectRequest(ctx, rejectInternalError, codes.Internal, err, policyEvaluationError)
 		}
+	}
 
-		// Submit to CTL
-		if g.ct != nil {
-			sct, err := g.ct.AddChain(ctx, ctl.BuildCTChain(csc.FinalCertificate, csc.FinalChain))
-			if err != nil {
-				return nil, handleFulcioGRPCError(ctx, codes.Internal, err, failedToEnterCertInCTL)
+	// Reject tokens that have already been used, now that the request is
+	// otherwise valid
+	issued := false
+	if issuer.ReplayProtection {
+		if err := g.replay.Check(ctx, token); err != nil {
+			if errors.Is(err, replay.ErrReplayed) {
+				metricReplaysRejected.WithLabelValues(configuredIssuer).Inc()
+				return nil, rejectRequest(ctx, rejectTokenReplayed, codes.PermissionDenied, err, tokenReplayed)
 			}
-			// convert to AddChainResponse because Cosign expects this struct.
-			addChainResp, err := ctl.ToAddChainResponse(sct)
-			if err != nil {
-				return nil, handleFulcioGRPCError(ctx, codes.Internal, err, failedToMarshalSCT)
+			return nil, rejectRequest(ctx, rejectInternalError, codes.Unavailable, err, replayCheckError)
+		}
+		// A token is only used up by a certificate the client receives, so
+		// that retrying after a transient failure is not a replay
+		defer func() {
+			if issued {
+				return
 			}
-			sctBytes, err = json.Marshal(addChainResp)
-			if err != nil {
-				return nil, handleFulcioGRPCError(ctx, codes.Internal, err, failedToMarshalSCT)
+			if err := g.replay.Release(context.WithoutCancel(ctx), token); err != nil {
+				logger.Errorf("error releasing identity token after failed request: %v", err)
 			}
-		} else {
-			logger.Info("Skipping CT log upload.")
-		}
+		}()
+	}
 
-		finalPEM, err := csc.CertPEM()
-		if err != nil {
-			return nil, handleFulcioGRPCError(ctx, codes.Internal, err, failedToMarshalCert)
-		}
+	// Select the CA backend configured for the issuer
+	ctx = certauth.WithBackend(ctx, issuer.CA)
+	ca, err := certauth.Resolve(ctx, g.ca)
+	if err != nil {
+		return nil, rejectRequest(ctx, rejectCAError, codes.Internal, err, genericCAError)
+	}
+	caBackend := caBackendLabel(issuer.CA)
 
-		finalChainPEM, err := csc.ChainPEM()
-		if err != nil {
-			return nil, handleFulcioGRPCError(ctx, codes.Internal, err, failedToMarshalCert)
-		}
+	// Fail closed before anything is signed if the audit log is unwritable
+	if err := g.recordAttempt(ctx); err != nil {
+		return nil, rejectRequest(ctx, rejectInternalError, codes.Internal, err, failedToAuditDecision)
+	}
 
-		result.Certificate = &fulciogrpc.SigningCertificate_SignedCertificateDetachedSct{
-			SignedCertificateDetachedSct: &fulciogrpc.SigningCertificateDetachedSCT{
-				Chain: &fulciogrpc.CertificateChain{
-					Certificates: append([]string{finalPEM}, finalChainPEM...),
-				},
-			},
-		}
-		if len(sctBytes) > 0 {
-			result.GetSignedCertificateDetachedSct().SignedCertificateTimestamp = sctBytes
-		}
-	} else {
+	var csc *certauth.CodeSigningCertificate
+	var scts []*ct.SignedCertificateTimestamp
+	var sctList [][]byte
+	// Set if the CT logs are unavailable and the certificate is queued for
+	// submission instead
+	queued := false
+	// SCTs are embedded if the CA supports the pre-certificate flow and the CT log is configured
+	sctCa, embedded := ca.(certauth.EmbeddedSCTCA)
+	embedded = embedded && g.ct != nil
+	if embedded {
+		start = time.Now()
 		precert, err := sctCa.CreatePrecertificate(ctx, principal, publicKey)
+		metricCASigningDuration.WithLabelValues(caBackend, "precertificate", metricResult(err)).Observe(time.Since(start).Seconds())
 		if err != nil {
 			// if the error was due to invalid input in the request, return HTTP 400
-			if _, ok := err.(certauth.ValidationError); ok {
-				return nil, handleFulcioGRPCError(ctx, codes.InvalidArgument, err, err.Error())
+			if errors.As(err, new(certauth.ValidationError)) {
+				return nil, rejectRequest(ctx, rejectInvalidRequest, codes.InvalidArgument, err, err.Error())
 			}
 			err = fmt.Errorf("error creating a pre-certificate and chain: %w", err)
 			// otherwise return a 500 error to reflect that it is a transient server issue that the client can't resolve
-			return nil, handleFulcioGRPCError(ctx, codes.Internal, err, genericCAError)
+			return nil, rejectRequest(ctx, rejectCAError, codes.Internal, err, genericCAError)
 		}
-		// submit precertificate and chain to CT log
-		sct, err := g.ct.AddPreChain(ctx, ctl.BuildCTChain(precert.PreCert, precert.CertChain))
-		if err != nil {
-			return nil, handleFulcioGRPCError(ctx, codes.Internal, err, failedToEnterCertInCTL)
+		// submit precertificate and chain to CT logs
+		ctCtx, span := tracing.Start(ctx, "ct.submit", attribute.String("ct.operation", "add-pre-chain"))
+		start = time.Now()
+		scts, err = g.ct.AddPreChain(ctCtx, ctl.BuildCTChain(precert.PreCert, precert.CertChain))
+		metricCTSubmissionDuration.WithLabelValues("add-pre-chain", metricResult(err)).Observe(time.Since(start).Seconds())
+		tracing.End(span, err)
+		switch {
+		case err == nil:
+			start = time.Now()
+			csc, err = sctCa.IssueFinalCertificate(ctx, precert, scts...)
+			metricCASigningDuration.WithLabelValues(caBackend, "final_certificate", metricResult(err)).Observe(time.Since(start).Seconds())
+			if err != nil {
+				err = fmt.Errorf("error issuing final certificate using the pre-certificate with CA backend: %w", err)
+				return nil, rejectRequest(ctx, rejectCAError, codes.Internal, err, genericCAError)
+			}
+		case g.ctQueue != nil:
+			// Fall back to a certificate without an SCT below
+			logger.Warnf("CT logs unavailable, issuing certificate without an embedded SCT: %v", err)
+			embedded = false
+			queued = true
+		default:
+			return nil, rejectRequest(ctx, rejectCTError, codes.Internal, err, failedToEnterCertInCTL)
 		}
-		csc, err = sctCa.IssueFinalCertificate(ctx, precert, sct)
+	}
+	if !embedded {
+		// currently configured CA doesn't support pre-certificate flow required to embed SCT in final certificate
+		start = time.Now()
+		csc, err = ca.CreateCertificate(ctx, principal, publicKey)
+		metricCASigningDuration.WithLabelValues(caBackend, "certificate", metricResult(err)).Observe(time.Since(start).Seconds())
 		if err != nil {
-			err = fmt.Errorf("error issuing final certificate using the pre-certificate with CA backend: %w", err)
-			return nil, handleFulcioGRPCError(ctx, codes.Internal, err, genericCAError)
+			// if the error was due to invalid input in the request, return HTTP 400
+			if errors.As(err, new(certauth.ValidationError)) {
+				return nil, rejectRequest(ctx, rejectInvalidRequest, codes.InvalidArgument, err, err.Error())
+			}
+			err = fmt.Errorf("error creating certificate: %w", err)
+			// otherwise return a 500 error to reflect that it is a transient server issue that the client can't resolve
+			return nil, rejectRequest(ctx, rejectCAError, codes.Internal, err, genericCAError)
 		}
 
-		finalPEM, err := csc.CertPEM()
-		if err != nil {
-			return nil, handleFulcioGRPCError(ctx, codes.Internal, err, failedToMarshalCert)
+		// Submit to CTL
+		switch {
+		case g.ct == nil:
+			logger.Info("Skipping CT log upload.")
+		case !queued:
+			ctCtx, span := tracing.Start(ctx, "ct.submit", attribute.String("ct.operation", "add-chain"))
+			start = time.Now()
+			scts, err = g.ct.AddChain(ctCtx, ctl.BuildCTChain(csc.FinalCertificate, csc.FinalChain))
+			metricCTSubmissionDuration.WithLabelValues("add-chain", metricResult(err)).Observe(time.Since(start).Seconds())
+			tracing.End(span, err)
+			if err != nil {
+				if g.ctQueue == nil {
+					return nil, rejectRequest(ctx, rejectCTError, codes.Internal, err, failedToEnterCertInCTL)
+				}
+				logger.Warnf("CT logs unavailable, issuing certificate without a detached SCT: %v", err)
+				queued = true
+				break
+			}
+			for _, sct := range scts {
+				// convert to AddChainResponse because Cosign expects this struct.
+				addChainResp, err := ctl.ToAddChainResponse(sct)
+				if err != nil {
+					return nil, rejectRequest(ctx, rejectInternalError, codes.Internal, err, failedToMarshalSCT)
+				}
+				b, err := json.Marshal(addChainResp)
+				if err != nil {
+					return nil, rejectRequest(ctx, rejectInternalError, codes.Internal, err, failedToMarshalSCT)
+				}
+				sctList = append(sctList, b)
+			}
 		}
+		if queued {
+			if err := g.ctQueue.Enqueue(ctl.BuildCTChain(csc.FinalCertificate, csc.FinalChain)); err != nil {
+				return nil, rejectRequest(ctx, rejectCTError, codes.Internal, err, failedToEnterCertInCTL)
+			}
+			metricCTQueuedCerts.Inc()
+		}
+	}
 
-		finalChainPEM, err := csc.ChainPEM()
-		if err != nil {
-			return nil, handleFulcioGRPCError(ctx, codes.Internal, err, failedToMarshalCert)
+	decision.SerialNumber = csc.FinalCertificate.SerialNumber.Text(16)
+
+	if g.ledger != nil {
+		if err := g.recordIssuance(ctx, csc.FinalCertificate, scts); err != nil {
+			return nil, rejectRequest(ctx, rejectInternalError, codes.Internal, err, failedToRecordCert)
 		}
+	}
+
+	if g.notifier != nil {
+		g.notifyIssuance(ctx, token, csc.FinalCertificate)
+	}
+
+	finalPEM, err := csc.CertPEM()
+	if err != nil {
+		return nil, rejectRequest(ctx, rejectInternalError, codes.Internal, err, failedToMarshalCert)
+	}
 
+	finalChainPEM, err := csc.ChainPEM()
+	if err != nil {
+		return nil, rejectRequest(ctx, rejectInternalError, codes.Internal, err, failedToMarshalCert)
+	}
+
+	chain := &fulciogrpc.CertificateChain{
+		Certificates: append([]string{finalPEM}, finalChainPEM...),
+	}
+	result := &fulciogrpc.SigningCertificate{}
+	if embedded {
 		result.Certificate = &fulciogrpc.SigningCertificate_SignedCertificateEmbeddedSct{
 			SignedCertificateEmbeddedSct: &fulciogrpc.SigningCertificateEmbeddedSCT{
-				Chain: &fulciogrpc.CertificateChain{
-					Certificates: append([]string{finalPEM}, finalChainPEM...),
-				},
+				Chain: chain,
 			},
 		}
+	} else {
+		detached := &fulciogrpc.SigningCertificateDetachedSCT{
+			Chain: chain,
+		}
+		if len(sctList) > 0 {
+			detached.SignedCertificateTimestamp = sctList[0]
+			detached.SignedCertificateTimestamps = sctList
+		}
+		result.Certificate = &fulciogrpc.SigningCertificate_SignedCertificateDetachedSct{
+			SignedCertificateDetachedSct: detached,
+		}
 	}
 
 	metricNewEntries.Inc()
+	metricCertificatesIssued.WithLabelValues(issuerType, configuredIssuer, caBackend).Inc()
 
+	issued = true
 	return result, nil
 }
 
+// tokenFromMetadata returns the OIDC token extracted from the HTTP headers
+// of a request, or sent as gRPC metadata.
+func tokenFromMetadata(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		vals := md.Get(MetadataOIDCTokenKey)
+		if len(vals) == 1 {
+			return vals[0]
+		}
+	}
+	return ""
+}
+
+// issuerConfigForToken returns the configuration of the issuer of an
+// authenticated token. If no configuration is available, the zero value is
+// returned, which selects the default issuance settings.
+func issuerConfigForToken(ctx context.Context, token string) (config.OIDCIssuer, error) {
+	cfg := config.FromContext(ctx)
+	if cfg == nil {
+		return config.OIDCIssuer{}, nil
+	}
+	issuerURL, err := identity.ExtractIssuerURL(token)
+	if err != nil {
+		return config.OIDCIssuer{}, err
+	}
+	iss, _ := cfg.GetIssuer(issuerURL)
+	return iss, nil
+}
+
+// evaluateIssuancePolicy checks the issuance policy of iss against the
+// token's claims and the extensions that principal would embed in the
+// certificate. A *policy.DeniedError is returned if a rule does not hold.
+func evaluateIssuancePolicy(ctx context.Context, iss config.OIDCIssuer, token string, principal identity.Principal) error {
+	var p *policy.Policy
+	var err error
+	if cfg := config.FromContext(ctx); cfg != nil {
+		p, err = cfg.IssuerPolicy(iss.IssuerURL)
+	} else {
+		p, err = iss.Policy()
+	}
+	if err != nil {
+		return err
+	}
+	claims, err := identity.ExtractClaims(token)
+	if err != nil {
+		return err
+	}
+	// Render the principal's extensions into a scratch certificate to
+	// evaluate the same values the CA would sign
+	var cert x509.Certificate
+	if err := principal.Embed(ctx, &cert); err != nil {
+		return fmt.Errorf("embedding principal: %w", err)
+	}
+	exts, err := certificate.ParseExtensions(cert.ExtraExtensions)
+	if err != nil {
+		return fmt.Errorf("parsing extensions: %w", err)
+	}
+	return p.Evaluate(policy.Input{
+		Claims:     claims,
+		Extensions: exts,
+		Subject:    principal.Name(ctx),
+	})
+}
+
+// withCertificateValidity returns a context carrying the validity window for
+// a certificate issued under iss, clamping the requested lifetime to the
+// maximum allowed by the issuer.
+func withCertificateValidity(ctx context.Context, iss config.OIDCIssuer, requested *durationpb.Duration) (context.Context, error) {
+	var lifetime time.Duration
+	if requested != nil {
+		if err := requested.CheckValid(); err != nil {
+			return ctx, err
+		}
+		lifetime = requested.AsDuration()
+		if lifetime <= 0 {
+			return ctx, fmt.Errorf("requested validity must be positive, got %v", lifetime)
+		}
+	}
+
+	return certauth.WithValidity(ctx, certauth.Validity{
+		Lifetime:      iss.CertificateLifetime(lifetime),
+		NotBeforeSkew: iss.CertificateNotBeforeSkew(),
+	}), nil
+}
+
+func (g *grpcaCAServer) GetChallenge(ctx context.Context, _ *fulciogrpc.GetChallengeRequest) (*fulciogrpc.Challenge, error) {
+	if g.nonces == nil {
+		return nil, handleFulcioGRPCError(ctx, codes.Unavailable, errors.New("no nonce issuer configured"), issueChallengeError)
+	}
+	nonce, expiry, err := g.nonces.Issue()
+	if err != nil {
+		return nil, handleFulcioGRPCError(ctx, codes.Internal, err, issueChallengeError)
+	}
+	return &fulciogrpc.Challenge{
+		Nonce:     nonce,
+		ExpiresAt: timestamppb.New(expiry),
+	}, nil
+}
+
 func (g *grpcaCAServer) GetTrustBundle(ctx context.Context, _ *fulciogrpc.GetTrustBundleRequest) (*fulciogrpc.TrustBundle, error) {
 	trustBundle, err := g.ca.TrustBundle(ctx)
 	if err != nil {
@@ -306,18 +663,6 @@ func (g *grpcaCAServer) GetConfiguration(ctx context.Context, _ *fulciogrpc.GetC
 	}, nil
 }
 
-func (g *grpcaCAServer) Check(_ context.Context, _ *health.HealthCheckRequest) (*health.HealthCheckResponse, error) {
-	return &health.HealthCheckResponse{Status: health.HealthCheckResponse_SERVING}, nil
-}
-
-func (g *grpcaCAServer) List(_ context.Context, _ *health.HealthListRequest) (*health.HealthListResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "unimplemented")
-}
-
-func (g *grpcaCAServer) Watch(_ *health.HealthCheckRequest, _ health.Health_WatchServer) error {
-	return status.Error(codes.Unimplemented, "unimplemented")
-}
-
 func getHashFuncForSignatureAlgorithm(signatureAlgorithm x509.SignatureAlgorithm) (crypto.Hash, error) {
 	switch signatureAlgorithm {
 	case x509.ECDSAWithSHA256:
@@ -35,28 +35,37 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"chainguard.dev/sdk/uidp"
 	"github.com/go-jose/go-jose/v4"
 	"github.com/go-jose/go-jose/v4/jwt"
+	ct "github.com/google/certificate-transparency-go"
 	ctclient "github.com/google/certificate-transparency-go/client"
 	"github.com/google/certificate-transparency-go/jsonclient"
+	ctx509 "github.com/google/certificate-transparency-go/x509"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"github.com/sigstore/fulcio/pkg/ca"
 	"github.com/sigstore/fulcio/pkg/ca/ephemeralca"
+	"github.com/sigstore/fulcio/pkg/ca/routingca"
 	"github.com/sigstore/fulcio/pkg/certificate"
 	"github.com/sigstore/fulcio/pkg/config"
+	"github.com/sigstore/fulcio/pkg/ctl"
 	"github.com/sigstore/fulcio/pkg/generated/protobuf"
 	"github.com/sigstore/fulcio/pkg/identity"
+	"github.com/sigstore/fulcio/pkg/replay"
 	v1 "github.com/sigstore/protobuf-specs/gen/pb-go/common/v1"
 	"github.com/sigstore/sigstore/pkg/cryptoutils"
 	"github.com/sigstore/sigstore/pkg/signature"
@@ -89,7 +98,7 @@ func passFulcioConfigThruContext(cfg *config.FulcioConfig) grpc.UnaryServerInter
 	}
 }
 
-func setupGRPCForTest(t *testing.T, cfg *config.FulcioConfig, ctl *ctclient.LogClient, ca ca.CertificateAuthority) (*grpc.Server, *grpc.ClientConn) {
+func setupGRPCForTest(t *testing.T, cfg *config.FulcioConfig, ct *ctl.MultiLog, ca ca.CertificateAuthority, opts ...GRPCCAServerOption) (*grpc.Server, *grpc.ClientConn) {
 	t.Helper()
 	lis = bufconn.Listen(bufSize)
 	s := grpc.NewServer(grpc.UnaryInterceptor(passFulcioConfigThruContext(cfg)))
@@ -98,7 +107,7 @@ func setupGRPCForTest(t *testing.T, cfg *config.FulcioConfig, ctl *ctclient.LogC
 	if err != nil {
 		t.Error(err)
 	}
-	protobuf.RegisterCAServer(s, NewGRPCCAServer(ctl, ca, algorithmRegistry, ip))
+	protobuf.RegisterCAServer(s, NewGRPCCAServer(ct, ca, algorithmRegistry, ip, opts...))
 	go func() {
 		if err := s.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
 			t.Errorf("Server exited with error: %v", err)
@@ -594,6 +603,712 @@ func TestAPIWithEmail(t *testing.T) {
 	}
 }
 
+// Tests proof of possession over a server-issued challenge
+func TestAPIWithChallenge(t *testing.T) {
+	emailSigner, emailIssuer := newOIDCIssuer(t)
+
+	cfg, err := config.Read([]byte(fmt.Sprintf(`{
+		"OIDCIssuers": {
+			%q: {
+				"IssuerURL": %q,
+				"ClientID": "sigstore",
+				"Type": "email",
+				"RequireChallenge": true
+			}
+		}
+	}`, emailIssuer, emailIssuer)))
+	if err != nil {
+		t.Fatalf("config.Read() = %v", err)
+	}
+
+	emailSubject := "foo@example.com"
+	tok, err := jwt.Signed(emailSigner).Claims(jwt.Claims{
+		Issuer:   emailIssuer,
+		IssuedAt: jwt.NewNumericDate(time.Now()),
+		Expiry:   jwt.NewNumericDate(time.Now().Add(30 * time.Minute)),
+		Subject:  emailSubject,
+		Audience: jwt.Audience{"sigstore"},
+	}).Claims(customClaims{Email: emailSubject, EmailVerified: true}).Serialize()
+	if err != nil {
+		t.Fatalf("Serialize() = %v", err)
+	}
+
+	ctClient, eca := createCA(cfg, t)
+	ctx := context.Background()
+	server, conn := setupGRPCForTest(t, cfg, ctClient, eca)
+	defer func() {
+		server.Stop()
+		conn.Close()
+	}()
+	client := protobuf.NewCAClient(conn)
+
+	request := func(pubBytes string, proof []byte, challenge string) (*protobuf.SigningCertificate, error) {
+		return client.CreateSigningCertificate(ctx, &protobuf.CreateSigningCertificateRequest{
+			Credentials: &protobuf.Credentials{
+				Credentials: &protobuf.Credentials_OidcIdentityToken{
+					OidcIdentityToken: tok,
+				},
+			},
+			Key: &protobuf.CreateSigningCertificateRequest_PublicKeyRequest{
+				PublicKeyRequest: &protobuf.PublicKeyRequest{
+					PublicKey: &protobuf.PublicKey{
+						Content: pubBytes,
+					},
+					ProofOfPossession: proof,
+					Challenge:         challenge,
+				},
+			},
+		})
+	}
+
+	// A proof over the subject is not accepted when the issuer requires a challenge
+	pubBytes, proof := generateKeyAndProof(emailSubject, t)
+	if _, err := request(pubBytes, proof, ""); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument without challenge, got %v", err)
+	}
+
+	challenge, err := client.GetChallenge(ctx, &protobuf.GetChallengeRequest{})
+	if err != nil {
+		t.Fatalf("GetChallenge() = %v", err)
+	}
+	if challenge.GetNonce() == "" {
+		t.Fatal("expected nonce in challenge")
+	}
+	if !challenge.GetExpiresAt().AsTime().After(time.Now()) {
+		t.Fatalf("expected challenge to expire in the future, got %v", challenge.GetExpiresAt().AsTime())
+	}
+
+	// The proof must be over the challenge, not the subject
+	if _, err := request(pubBytes, proof, challenge.GetNonce()); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument for proof over subject, got %v", err)
+	}
+
+	// Challenges not issued by the server are rejected
+	pubBytes, proof = generateKeyAndProof("forged", t)
+	if _, err := request(pubBytes, proof, "forged"); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument for forged challenge, got %v", err)
+	}
+
+	pubBytes, proof = generateKeyAndProof(challenge.GetNonce(), t)
+	resp, err := request(pubBytes, proof, challenge.GetNonce())
+	if err != nil {
+		t.Fatalf("SigningCert() = %v", err)
+	}
+	leafCert := verifyResponse(resp, eca, emailIssuer, t)
+	if len(leafCert.EmailAddresses) != 1 || leafCert.EmailAddresses[0] != emailSubject {
+		t.Fatalf("unexpected email addresses %v", leafCert.EmailAddresses)
+	}
+}
+
+// flakyCA fails to issue certificates while failing is set.
+type flakyCA struct {
+	ca.CertificateAuthority
+	failing atomic.Bool
+}
+
+func (f *flakyCA) fail(failing bool) {
+	f.failing.Store(failing)
+}
+
+func (f *flakyCA) CreateCertificate(ctx context.Context, principal identity.Principal, publicKey crypto.PublicKey) (*ca.CodeSigningCertificate, error) {
+	if f.failing.Load() {
+		return nil, errors.New("CA unavailable")
+	}
+	return f.CertificateAuthority.CreateCertificate(ctx, principal, publicKey)
+}
+
+// Tests that tokens can only be used once for issuers with replay protection
+func TestAPIWithReplayProtection(t *testing.T) {
+	emailSigner, emailIssuer := newOIDCIssuer(t)
+	otherSigner, otherIssuer := newOIDCIssuer(t)
+
+	cfg, err := config.Read([]byte(fmt.Sprintf(`{
+		"OIDCIssuers": {
+			%q: {
+				"IssuerURL": %q,
+				"ClientID": "sigstore",
+				"Type": "email",
+				"ReplayProtection": true
+			},
+			%q: {
+				"IssuerURL": %q,
+				"ClientID": "sigstore",
+				"Type": "email"
+			}
+		}
+	}`, emailIssuer, emailIssuer, otherIssuer, otherIssuer)))
+	if err != nil {
+		t.Fatalf("config.Read() = %v", err)
+	}
+
+	ctClient, eca := createCA(cfg, t)
+	flaky := &flakyCA{CertificateAuthority: eca}
+	ctx := context.Background()
+	server, conn := setupGRPCForTest(t, cfg, ctClient, flaky, WithReplayCache(replay.NewMemoryCache()))
+	defer func() {
+		server.Stop()
+		conn.Close()
+	}()
+	client := protobuf.NewCAClient(conn)
+
+	emailSubject := "foo@example.com"
+	request := func(tok string) error {
+		pubBytes, proof := generateKeyAndProof(emailSubject, t)
+		_, err := client.CreateSigningCertificate(ctx, &protobuf.CreateSigningCertificateRequest{
+			Credentials: &protobuf.Credentials{
+				Credentials: &protobuf.Credentials_OidcIdentityToken{
+					OidcIdentityToken: tok,
+				},
+			},
+			Key: &protobuf.CreateSigningCertificateRequest_PublicKeyRequest{
+				PublicKeyRequest: &protobuf.PublicKeyRequest{
+					PublicKey: &protobuf.PublicKey{
+						Content: pubBytes,
+					},
+					ProofOfPossession: proof,
+				},
+			},
+		})
+		return err
+	}
+
+	for _, c := range []struct {
+		signer       jose.Signer
+		issuer       string
+		wantRejected bool
+	}{
+		{signer: emailSigner, issuer: emailIssuer, wantRejected: true},
+		{signer: otherSigner, issuer: otherIssuer, wantRejected: false},
+	} {
+		tok, err := jwt.Signed(c.signer).Claims(jwt.Claims{
+			Issuer:   c.issuer,
+			IssuedAt: jwt.NewNumericDate(time.Now()),
+			Expiry:   jwt.NewNumericDate(time.Now().Add(30 * time.Minute)),
+			Subject:  emailSubject,
+			Audience: jwt.Audience{"sigstore"},
+			ID:       "token-id",
+		}).Claims(customClaims{Email: emailSubject, EmailVerified: true}).Serialize()
+		if err != nil {
+			t.Fatalf("Serialize() = %v", err)
+		}
+
+		// A request that fails after the token was checked does not use it
+		// up
+		flaky.fail(true)
+		if err := request(tok); status.Code(err) != codes.Internal {
+			t.Fatalf("expected CA failure for token from %s, got %v", c.issuer, err)
+		}
+		flaky.fail(false)
+		if err := request(tok); err != nil {
+			t.Fatalf("first use of token from %s: %v", c.issuer, err)
+		}
+		err = request(tok)
+		if c.wantRejected && status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("expected replayed token from %s to be rejected, got %v", c.issuer, err)
+		}
+		if !c.wantRejected && err != nil {
+			t.Fatalf("expected reuse of token from %s to be allowed, got %v", c.issuer, err)
+		}
+	}
+}
+
+// Tests that issuance policies are enforced before a certificate is issued
+func TestAPIWithIssuancePolicy(t *testing.T) {
+	emailSigner, emailIssuer := newOIDCIssuer(t)
+
+	cfg, err := config.Read([]byte(fmt.Sprintf(`{
+		"OIDCIssuers": {
+			%q: {
+				"IssuerURL": %q,
+				"ClientID": "sigstore",
+				"Type": "email",
+				"IssuancePolicy": [
+					{"Name": "verified", "Expression": "claims.email_verified == true"},
+					{"Name": "issuer", "Expression": "extensions.Issuer == '%s'"},
+					{"Name": "domain", "Expression": "subject.endsWith('@example.com')"}
+				]
+			}
+		}
+	}`, emailIssuer, emailIssuer, emailIssuer)))
+	if err != nil {
+		t.Fatalf("config.Read() = %v", err)
+	}
+
+	ctClient, eca := createCA(cfg, t)
+	ctx := context.Background()
+	server, conn := setupGRPCForTest(t, cfg, ctClient, eca)
+	defer func() {
+		server.Stop()
+		conn.Close()
+	}()
+	client := protobuf.NewCAClient(conn)
+
+	for _, c := range []struct {
+		subject    string
+		deniedRule string
+	}{
+		{subject: "foo@example.com"},
+		{subject: "foo@example.org", deniedRule: "domain"},
+	} {
+		tok, err := jwt.Signed(emailSigner).Claims(jwt.Claims{
+			Issuer:   emailIssuer,
+			IssuedAt: jwt.NewNumericDate(time.Now()),
+			Expiry:   jwt.NewNumericDate(time.Now().Add(30 * time.Minute)),
+			Subject:  c.subject,
+			Audience: jwt.Audience{"sigstore"},
+		}).Claims(customClaims{Email: c.subject, EmailVerified: true}).Serialize()
+		if err != nil {
+			t.Fatalf("Serialize() = %v", err)
+		}
+
+		denials := testutil.ToFloat64(metricPolicyDenials.WithLabelValues(emailIssuer, "domain"))
+		pubBytes, proof := generateKeyAndProof(c.subject, t)
+		_, err = client.CreateSigningCertificate(ctx, &protobuf.CreateSigningCertificateRequest{
+			Credentials: &protobuf.Credentials{
+				Credentials: &protobuf.Credentials_OidcIdentityToken{
+					OidcIdentityToken: tok,
+				},
+			},
+			Key: &protobuf.CreateSigningCertificateRequest_PublicKeyRequest{
+				PublicKeyRequest: &protobuf.PublicKeyRequest{
+					PublicKey: &protobuf.PublicKey{
+						Content: pubBytes,
+					},
+					ProofOfPossession: proof,
+				},
+			},
+		})
+		if c.deniedRule == "" {
+			if err != nil {
+				t.Fatalf("expected %s to be allowed, got %v", c.subject, err)
+			}
+			continue
+		}
+		if status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("expected %s to be denied, got %v", c.subject, err)
+		}
+		if !strings.Contains(status.Convert(err).Message(), strconv.Quote(c.deniedRule)) {
+			t.Errorf("expected denial to name rule %q, got %q", c.deniedRule, status.Convert(err).Message())
+		}
+		if got := testutil.ToFloat64(metricPolicyDenials.WithLabelValues(emailIssuer, c.deniedRule)); got != denials+1 {
+			t.Errorf("expected denial to be counted, counter went from %v to %v", denials, got)
+		}
+	}
+}
+
+// Tests that issuers are signed by the CA backend named in their config
+func TestAPIWithCARouting(t *testing.T) {
+	defaultSigner, defaultIssuer := newOIDCIssuer(t)
+	teamSigner, teamIssuer := newOIDCIssuer(t)
+
+	cfg, err := config.Read([]byte(fmt.Sprintf(`{
+		"OIDCIssuers": {
+			%q: {
+				"IssuerURL": %q,
+				"ClientID": "sigstore",
+				"Type": "email"
+			},
+			%q: {
+				"IssuerURL": %q,
+				"ClientID": "sigstore",
+				"Type": "email",
+				"CA": "team"
+			}
+		}
+	}`, defaultIssuer, defaultIssuer, teamIssuer, teamIssuer)))
+	if err != nil {
+		t.Fatalf("config.Read() = %v", err)
+	}
+
+	ctClient, defaultCA := createCA(cfg, t)
+	_, teamCA := createCA(cfg, t)
+	rca, err := routingca.NewRoutingCA(defaultCA, map[string]ca.CertificateAuthority{"team": teamCA})
+	if err != nil {
+		t.Fatalf("NewRoutingCA() = %v", err)
+	}
+	ctx := context.Background()
+	server, conn := setupGRPCForTest(t, cfg, ctClient, rca)
+	defer func() {
+		server.Stop()
+		conn.Close()
+	}()
+	client := protobuf.NewCAClient(conn)
+
+	for _, c := range []struct {
+		signer jose.Signer
+		issuer string
+		eca    *ephemeralca.EphemeralCA
+	}{
+		{signer: defaultSigner, issuer: defaultIssuer, eca: defaultCA},
+		{signer: teamSigner, issuer: teamIssuer, eca: teamCA},
+	} {
+		tok := emailToken(t, c.signer, c.issuer)
+		pubBytes, proof := generateKeyAndProof("foo@example.com", t)
+		resp, err := client.CreateSigningCertificate(ctx, &protobuf.CreateSigningCertificateRequest{
+			Credentials: &protobuf.Credentials{
+				Credentials: &protobuf.Credentials_OidcIdentityToken{
+					OidcIdentityToken: tok,
+				},
+			},
+			Key: &protobuf.CreateSigningCertificateRequest_PublicKeyRequest{
+				PublicKeyRequest: &protobuf.PublicKeyRequest{
+					PublicKey: &protobuf.PublicKey{
+						Content: pubBytes,
+					},
+					ProofOfPossession: proof,
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("SigningCert() = %v", err)
+		}
+		// The selected backend supports embedded SCTs
+		if resp.GetSignedCertificateEmbeddedSct() == nil {
+			t.Errorf("expected certificate with embedded SCT for %s", c.issuer)
+		}
+		verifyResponse(resp, c.eca, c.issuer, t)
+	}
+
+	// The trust bundle contains the chains of both backends
+	bundle, err := client.GetTrustBundle(ctx, &protobuf.GetTrustBundleRequest{})
+	if err != nil {
+		t.Fatalf("GetTrustBundle() = %v", err)
+	}
+	if len(bundle.Chains) != 2 {
+		t.Errorf("expected 2 chains in trust bundle, got %d", len(bundle.Chains))
+	}
+}
+
+// Tests that the SCTs of every configured CT log are returned
+func TestAPIWithMultipleCTLogs(t *testing.T) {
+	emailSigner, emailIssuer := newOIDCIssuer(t)
+
+	cfg, err := config.Read([]byte(fmt.Sprintf(`{
+		"OIDCIssuers": {
+			%q: {
+				"IssuerURL": %q,
+				"ClientID": "sigstore",
+				"Type": "email"
+			}
+		}
+	}`, emailIssuer, emailIssuer)))
+	if err != nil {
+		t.Fatalf("config.Read() = %v", err)
+	}
+
+	var logs []ctl.Log
+	for i := 0; i < 2; i++ {
+		ctlogServer := fakeCTLogServer(t)
+		defer ctlogServer.Close()
+		ctClient, err := ctclient.New(ctlogServer.URL, &http.Client{Timeout: 30 * time.Second}, jsonclient.Options{})
+		if err != nil {
+			t.Fatalf("error creating CT client: %v", err)
+		}
+		logs = append(logs, ctClient)
+	}
+	multiLog, err := ctl.NewMultiLog(logs, ctl.WithQuorum(2))
+	if err != nil {
+		t.Fatalf("NewMultiLog() = %v", err)
+	}
+	eca, err := ephemeralca.NewEphemeralCA()
+	if err != nil {
+		t.Fatalf("ephemeralca.NewEphemeralCA() = %v", err)
+	}
+
+	for name, backend := range map[string]ca.CertificateAuthority{
+		"embedded": eca,
+		// Hides IssueFinalCertificate, so SCTs are detached
+		"detached": struct{ ca.CertificateAuthority }{eca},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server, conn := setupGRPCForTest(t, cfg, multiLog, backend)
+			defer func() {
+				server.Stop()
+				conn.Close()
+			}()
+			client := protobuf.NewCAClient(conn)
+
+			pubBytes, proof := generateKeyAndProof("foo@example.com", t)
+			resp, err := client.CreateSigningCertificate(context.Background(), &protobuf.CreateSigningCertificateRequest{
+				Credentials: &protobuf.Credentials{
+					Credentials: &protobuf.Credentials_OidcIdentityToken{
+						OidcIdentityToken: emailToken(t, emailSigner, emailIssuer),
+					},
+				},
+				Key: &protobuf.CreateSigningCertificateRequest_PublicKeyRequest{
+					PublicKeyRequest: &protobuf.PublicKeyRequest{
+						PublicKey: &protobuf.PublicKey{
+							Content: pubBytes,
+						},
+						ProofOfPossession: proof,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("SigningCert() = %v", err)
+			}
+			verifyResponse(resp, eca, emailIssuer, t)
+
+			if detached := resp.GetSignedCertificateDetachedSct(); detached != nil {
+				if got := len(detached.SignedCertificateTimestamps); got != 2 {
+					t.Fatalf("expected 2 detached SCTs, got %d", got)
+				}
+				if string(detached.SignedCertificateTimestamp) != string(detached.SignedCertificateTimestamps[0]) {
+					t.Error("expected the first detached SCT in signed_certificate_timestamp")
+				}
+				return
+			}
+			block, _ := pem.Decode([]byte(resp.GetSignedCertificateEmbeddedSct().Chain.Certificates[0]))
+			if block == nil {
+				t.Fatal("missing PEM data")
+			}
+			leafCert, err := ctx509.ParseCertificate(block.Bytes)
+			if err != nil {
+				t.Fatalf("failed to parse the received leaf cert: %v", err)
+			}
+			if got := len(leafCert.SCTList.SCTList); got != 2 {
+				t.Fatalf("expected 2 embedded SCTs, got %d", got)
+			}
+		})
+	}
+}
+
+// unavailableCTLog is a CT log that fails every submission
+type unavailableCTLog struct{}
+
+func (unavailableCTLog) AddChain(context.Context, []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	return nil, errors.New("log unavailable")
+}
+
+func (unavailableCTLog) AddPreChain(context.Context, []ct.ASN1Cert) (*ct.SignedCertificateTimestamp, error) {
+	return nil, errors.New("log unavailable")
+}
+
+// Tests that certificates are issued and queued while the CT log is unavailable
+func TestAPIWithCTQueue(t *testing.T) {
+	emailSigner, emailIssuer := newOIDCIssuer(t)
+
+	cfg, err := config.Read([]byte(fmt.Sprintf(`{
+		"OIDCIssuers": {
+			%q: {
+				"IssuerURL": %q,
+				"ClientID": "sigstore",
+				"Type": "email"
+			}
+		}
+	}`, emailIssuer, emailIssuer)))
+	if err != nil {
+		t.Fatalf("config.Read() = %v", err)
+	}
+
+	unavailable, err := ctl.NewMultiLog([]ctl.Log{unavailableCTLog{}})
+	if err != nil {
+		t.Fatalf("NewMultiLog() = %v", err)
+	}
+	available, eca := createCA(cfg, t)
+
+	for name, backend := range map[string]ca.CertificateAuthority{
+		"embedded": eca,
+		// Hides IssueFinalCertificate, so SCTs are detached
+		"detached": struct{ ca.CertificateAuthority }{eca},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			queue, err := ctl.NewQueue(dir, unavailable)
+			if err != nil {
+				t.Fatalf("NewQueue() = %v", err)
+			}
+			server, conn := setupGRPCForTest(t, cfg, unavailable, backend, WithCTQueue(queue))
+			defer func() {
+				server.Stop()
+				conn.Close()
+			}()
+			client := protobuf.NewCAClient(conn)
+
+			pubBytes, proof := generateKeyAndProof("foo@example.com", t)
+			resp, err := client.CreateSigningCertificate(context.Background(), &protobuf.CreateSigningCertificateRequest{
+				Credentials: &protobuf.Credentials{
+					Credentials: &protobuf.Credentials_OidcIdentityToken{
+						OidcIdentityToken: emailToken(t, emailSigner, emailIssuer),
+					},
+				},
+				Key: &protobuf.CreateSigningCertificateRequest_PublicKeyRequest{
+					PublicKeyRequest: &protobuf.PublicKeyRequest{
+						PublicKey: &protobuf.PublicKey{
+							Content: pubBytes,
+						},
+						ProofOfPossession: proof,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("SigningCert() = %v", err)
+			}
+			detached := resp.GetSignedCertificateDetachedSct()
+			if detached == nil {
+				t.Fatal("expected a certificate without an embedded SCT")
+			}
+			if len(detached.SignedCertificateTimestamp) != 0 || len(detached.SignedCertificateTimestamps) != 0 {
+				t.Error("expected no SCT while the CT log is unavailable")
+			}
+			if n, err := queue.Len(); err != nil || n != 1 {
+				t.Fatalf("Len() = %d, %v, want 1 queued certificate", n, err)
+			}
+
+			// The queue survives a restart, and is drained once the log recovers
+			restarted, err := ctl.NewQueue(dir, available)
+			if err != nil {
+				t.Fatalf("NewQueue() = %v", err)
+			}
+			if err := restarted.Drain(context.Background()); err != nil {
+				t.Fatalf("Drain() = %v", err)
+			}
+			if n, err := restarted.Len(); err != nil || n != 0 {
+				t.Fatalf("Len() = %d, %v, want empty queue", n, err)
+			}
+		})
+	}
+
+	// Without a queue, issuance fails while the CT log is unavailable
+	server, conn := setupGRPCForTest(t, cfg, unavailable, eca)
+	defer func() {
+		server.Stop()
+		conn.Close()
+	}()
+	pubBytes, proof := generateKeyAndProof("foo@example.com", t)
+	_, err = protobuf.NewCAClient(conn).CreateSigningCertificate(context.Background(), &protobuf.CreateSigningCertificateRequest{
+		Credentials: &protobuf.Credentials{
+			Credentials: &protobuf.Credentials_OidcIdentityToken{
+				OidcIdentityToken: emailToken(t, emailSigner, emailIssuer),
+			},
+		},
+		Key: &protobuf.CreateSigningCertificateRequest_PublicKeyRequest{
+			PublicKeyRequest: &protobuf.PublicKeyRequest{
+				PublicKey: &protobuf.PublicKey{
+					Content: pubBytes,
+				},
+				ProofOfPossession: proof,
+			},
+		},
+	})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal error, got %v", err)
+	}
+}
+
+// Tests that requested certificate lifetimes are clamped to the issuer's maximum
+func TestAPIWithRequestedValidity(t *testing.T) {
+	emailSigner, emailIssuer := newOIDCIssuer(t)
+
+	cfg, err := config.Read([]byte(fmt.Sprintf(`{
+		"OIDCIssuers": {
+			%q: {
+				"IssuerURL": %q,
+				"ClientID": "sigstore",
+				"Type": "email",
+				"DefaultCertificateLifetime": "5m",
+				"MaxCertificateLifetime": "1h",
+				"NotBeforeSkew": "1m"
+			}
+		}
+	}`, emailIssuer, emailIssuer)))
+	if err != nil {
+		t.Fatalf("config.Read() = %v", err)
+	}
+
+	emailSubject := "foo@example.com"
+	tok, err := jwt.Signed(emailSigner).Claims(jwt.Claims{
+		Issuer:   emailIssuer,
+		IssuedAt: jwt.NewNumericDate(time.Now()),
+		Expiry:   jwt.NewNumericDate(time.Now().Add(30 * time.Minute)),
+		Subject:  emailSubject,
+		Audience: jwt.Audience{"sigstore"},
+	}).Claims(customClaims{Email: emailSubject, EmailVerified: true}).Serialize()
+	if err != nil {
+		t.Fatalf("Serialize() = %v", err)
+	}
+
+	ctClient, eca := createCA(cfg, t)
+	server, conn := setupGRPCForTest(t, cfg, ctClient, eca)
+	defer func() {
+		server.Stop()
+		conn.Close()
+	}()
+	client := protobuf.NewCAClient(conn)
+
+	tests := map[string]struct {
+		requested *durationpb.Duration
+		want      time.Duration
+		wantCode  codes.Code
+	}{
+		"unset uses issuer default": {
+			want: 5 * time.Minute,
+		},
+		"within issuer maximum": {
+			requested: durationpb.New(20 * time.Minute),
+			want:      20 * time.Minute,
+		},
+		"clamped to issuer maximum": {
+			requested: durationpb.New(24 * time.Hour),
+			want:      time.Hour,
+		},
+		"negative lifetime is rejected": {
+			requested: durationpb.New(-time.Minute),
+			wantCode:  codes.InvalidArgument,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			pubBytes, proof := generateKeyAndProof(emailSubject, t)
+			resp, err := client.CreateSigningCertificate(context.Background(), &protobuf.CreateSigningCertificateRequest{
+				Credentials: &protobuf.Credentials{
+					Credentials: &protobuf.Credentials_OidcIdentityToken{
+						OidcIdentityToken: tok,
+					},
+				},
+				Key: &protobuf.CreateSigningCertificateRequest_PublicKeyRequest{
+					PublicKeyRequest: &protobuf.PublicKeyRequest{
+						PublicKey: &protobuf.PublicKey{
+							Content: pubBytes,
+						},
+						ProofOfPossession: proof,
+					},
+				},
+				RequestedValidity: test.requested,
+			})
+			if test.wantCode != codes.OK {
+				if status.Code(err) != test.wantCode {
+					t.Fatalf("expected %v, got %v", test.wantCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SigningCert() = %v", err)
+			}
+
+			chain := resp.GetSignedCertificateDetachedSct().GetChain()
+			if chain == nil {
+				chain = resp.GetSignedCertificateEmbeddedSct().GetChain()
+			}
+			if len(chain.GetCertificates()) == 0 {
+				t.Fatal("unexpected empty chain in response")
+			}
+			block, _ := pem.Decode([]byte(chain.Certificates[0]))
+			if block == nil {
+				t.Fatal("missing PEM data")
+			}
+			leafCert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				t.Fatalf("failed to parse the received leaf cert: %v", err)
+			}
+			// NotBefore is backdated by the configured skew
+			if got := leafCert.NotAfter.Sub(leafCert.NotBefore); got != test.want+time.Minute {
+				t.Fatalf("expected validity window of %v, got %v", test.want+time.Minute, got)
+			}
+		})
+	}
+}
+
 // Tests API for username subject types
 func TestAPIWithUsername(t *testing.T) {
 	usernameSigner, usernameIssuer := newOIDCIssuer(t)
@@ -2639,7 +3354,7 @@ func fakeCTLogServer(_ *testing.T) *httptest.Server {
 }
 
 // createCA initializes an ephemeral CA server and CT log server
-func createCA(_ *config.FulcioConfig, t *testing.T) (*ctclient.LogClient, *ephemeralca.EphemeralCA) {
+func createCA(_ *config.FulcioConfig, t *testing.T) (*ctl.MultiLog, *ephemeralca.EphemeralCA) {
 	// Stand up an ephemeral CA we can use for signing certificate requests.
 	eca, err := ephemeralca.NewEphemeralCA()
 	if err != nil {
@@ -2658,7 +3373,11 @@ func createCA(_ *config.FulcioConfig, t *testing.T) (*ctclient.LogClient, *ephem
 	if err != nil {
 		t.Fatalf("error creating CT client: %v", err)
 	}
-	return ctClient, eca
+	logs, err := ctl.NewMultiLog([]ctl.Log{ctClient})
+	if err != nil {
+		t.Fatalf("error creating CT logs: %v", err)
+	}
+	return logs, eca
 }
 
 // generateKeyAndProof creates a public key to be certified and creates a
@@ -0,0 +1,159 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	healthmon "github.com/sigstore/fulcio/pkg/health"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// ReadinessService is the gRPC health service whose status is whether the
+// server is ready to issue certificates, which requires every health check
+// that is not optional to pass. The empty service reports liveness, and is
+// serving whenever the server is.
+const ReadinessService = "dev.sigstore.fulcio.v2.CA"
+
+// Names of the health checks, which are also their gRPC health services.
+const (
+	// HealthCheckCA signs a probe with the CA's signing key.
+	HealthCheckCA = "ca"
+	// HealthCheckCTLog fetches the tree head of the CT logs.
+	HealthCheckCTLog = "ct-log"
+	// HealthCheckConfig checks that a config is loaded.
+	HealthCheckConfig = "config"
+	// HealthCheckOIDCDiscovery checks that the configured OIDC issuers
+	// have been discovered.
+	HealthCheckOIDCDiscovery = "oidc-discovery"
+)
+
+// WithHealthMonitor reports the status of m's checks through the gRPC
+// health service, each as the service named after the check. By default
+// the server is always ready.
+func WithHealthMonitor(m *healthmon.Monitor) GRPCCAServerOption {
+	return func(g *grpcaCAServer) {
+		g.health = m
+	}
+}
+
+// CheckConfig is a health check that a config is loaded in r.
+func (r *ConfigReloader) CheckConfig(context.Context) error {
+	if r.Config() == nil {
+		return errors.New("no config loaded")
+	}
+	return nil
+}
+
+// CheckOIDCDiscovery is a health check that discovers the configuration
+// of each OIDC issuer in the current config that has not been discovered
+// yet. Issuers matched by MetaIssuers patterns are discovered on first use,
+// so are not checked.
+func (r *ConfigReloader) CheckOIDCDiscovery(ctx context.Context) error {
+	cfg := r.Config()
+	if cfg == nil {
+		return errors.New("no config loaded")
+	}
+	var errs []error
+	for _, url := range slices.Sorted(maps.Keys(cfg.OIDCIssuers)) {
+		if _, ok := cfg.GetVerifierContext(ctx, url); !ok {
+			errs = append(errs, fmt.Errorf("discovery of OIDC issuer %s failed", url))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// servingStatus returns the status of a gRPC health service, and whether
+// the service exists.
+func (g *grpcaCAServer) servingStatus(service string) (healthpb.HealthCheckResponse_ServingStatus, bool) {
+	switch {
+	case service == "":
+		return healthpb.HealthCheckResponse_SERVING, true
+	case service == ReadinessService:
+		if g.health == nil || g.health.Healthy() {
+			return healthpb.HealthCheckResponse_SERVING, true
+		}
+		return healthpb.HealthCheckResponse_NOT_SERVING, true
+	case g.health != nil:
+		s, ok := g.health.Status(service)
+		if !ok {
+			break
+		}
+		if s.Healthy() {
+			return healthpb.HealthCheckResponse_SERVING, true
+		}
+		return healthpb.HealthCheckResponse_NOT_SERVING, true
+	}
+	return healthpb.HealthCheckResponse_SERVICE_UNKNOWN, false
+}
+
+// services returns the names of the gRPC health services.
+func (g *grpcaCAServer) services() []string {
+	services := []string{"", ReadinessService}
+	if g.health != nil {
+		services = append(services, g.health.Names()...)
+	}
+	return services
+}
+
+func (g *grpcaCAServer) Check(_ context.Context, request *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	st, ok := g.servingStatus(request.GetService())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", request.GetService())
+	}
+	return &healthpb.HealthCheckResponse{Status: st}, nil
+}
+
+func (g *grpcaCAServer) List(_ context.Context, _ *healthpb.HealthListRequest) (*healthpb.HealthListResponse, error) {
+	statuses := map[string]*healthpb.HealthCheckResponse{}
+	for _, service := range g.services() {
+		st, _ := g.servingStatus(service)
+		statuses[service] = &healthpb.HealthCheckResponse{Status: st}
+	}
+	return &healthpb.HealthListResponse{Statuses: statuses}, nil
+}
+
+// Watch sends the status of the service, and then its status whenever it
+// changes. Unknown services are reported as SERVICE_UNKNOWN.
+func (g *grpcaCAServer) Watch(request *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	var changes <-chan struct{}
+	if g.health != nil {
+		var stop func()
+		changes, stop = g.health.Subscribe()
+		defer stop()
+	}
+
+	ctx := stream.Context()
+	last := healthpb.HealthCheckResponse_ServingStatus(-1)
+	for {
+		if st, _ := g.servingStatus(request.GetService()); st != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: st}); err != nil {
+				return err
+			}
+			last = st
+		}
+		select {
+		case <-changes:
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	certauth "github.com/sigstore/fulcio/pkg/ca"
+	healthmon "github.com/sigstore/fulcio/pkg/health"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// watchStream records the responses sent by Watch.
+type watchStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan healthpb.HealthCheckResponse_ServingStatus
+}
+
+func (w *watchStream) Context() context.Context {
+	return w.ctx
+}
+
+func (w *watchStream) Send(r *healthpb.HealthCheckResponse) error {
+	w.sent <- r.GetStatus()
+	return nil
+}
+
+func TestHealth(t *testing.T) {
+	_, eca := createCA(nil, t)
+	var ctErr atomic.Pointer[error]
+	monitor, err := healthmon.NewMonitor([]healthmon.Check{{
+		Name: HealthCheckCA,
+		Func: func(ctx context.Context) error { return certauth.Probe(ctx, eca) },
+	}, {
+		Name: HealthCheckCTLog,
+		Func: func(context.Context) error {
+			if err := ctErr.Load(); err != nil {
+				return *err
+			}
+			return nil
+		},
+	}, {
+		Name:     HealthCheckOIDCDiscovery,
+		Func:     func(context.Context) error { return errors.New("discovery failed") },
+		Optional: true,
+	}})
+	if err != nil {
+		t.Fatalf("NewMonitor() = %v", err)
+	}
+	g := NewGRPCCAServer(nil, eca, nil, nil, WithHealthMonitor(monitor))
+	ctx := context.Background()
+
+	check := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		t.Helper()
+		resp, err := g.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("Check(%q) = %v", service, err)
+		}
+		return resp.GetStatus()
+	}
+	if got := check(""); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected the server to be live before the checks are evaluated, got %v", got)
+	}
+	if got := check(ReadinessService); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected the server not to be ready before the checks are evaluated, got %v", got)
+	}
+	if _, err := g.Check(ctx, &healthpb.HealthCheckRequest{Service: "unknown"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for an unknown service, got %v", err)
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	stream := &watchStream{ctx: watchCtx, sent: make(chan healthpb.HealthCheckResponse_ServingStatus, 10)}
+	watched := make(chan error)
+	go func() {
+		watched <- g.Watch(&healthpb.HealthCheckRequest{Service: ReadinessService}, stream)
+	}()
+	expectSent := func(want healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		select {
+		case got := <-stream.sent:
+			if got != want {
+				t.Errorf("Watch sent %v, want %v", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Watch did not send %v", want)
+		}
+	}
+	expectSent(healthpb.HealthCheckResponse_NOT_SERVING)
+
+	monitor.Evaluate(ctx)
+	expectSent(healthpb.HealthCheckResponse_SERVING)
+
+	resp, err := g.List(ctx, &healthpb.HealthListRequest{})
+	if err != nil {
+		t.Fatalf("List() = %v", err)
+	}
+	want := map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"":                       healthpb.HealthCheckResponse_SERVING,
+		ReadinessService:         healthpb.HealthCheckResponse_SERVING,
+		HealthCheckCA:            healthpb.HealthCheckResponse_SERVING,
+		HealthCheckCTLog:         healthpb.HealthCheckResponse_SERVING,
+		HealthCheckOIDCDiscovery: healthpb.HealthCheckResponse_NOT_SERVING,
+	}
+	if len(resp.GetStatuses()) != len(want) {
+		t.Errorf("List() returned %d services, want %d", len(resp.GetStatuses()), len(want))
+	}
+	for service, st := range want {
+		if got := resp.GetStatuses()[service].GetStatus(); got != st {
+			t.Errorf("List() status of %q = %v, want %v", service, got, st)
+		}
+	}
+
+	failure := errors.New("CT log unreachable")
+	ctErr.Store(&failure)
+	monitor.Evaluate(ctx)
+	expectSent(healthpb.HealthCheckResponse_NOT_SERVING)
+	if got := check(HealthCheckCTLog); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected the CT log to be unhealthy, got %v", got)
+	}
+
+	cancel()
+	if err := <-watched; status.Code(err) != codes.Canceled {
+		t.Errorf("expected Watch to be canceled, got %v", err)
+	}
+}
+
+func TestHealthWithoutMonitor(t *testing.T) {
+	g := NewGRPCCAServer(nil, nil, nil, nil)
+	resp, err := g.List(context.Background(), &healthpb.HealthListRequest{})
+	if err != nil {
+		t.Fatalf("List() = %v", err)
+	}
+	for _, service := range []string{"", ReadinessService} {
+		if got := resp.GetStatuses()[service].GetStatus(); got != healthpb.HealthCheckResponse_SERVING {
+			t.Errorf("List() status of %q = %v, want SERVING", service, got)
+		}
+	}
+}
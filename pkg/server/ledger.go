@@ -0,0 +1,184 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	cttls "github.com/google/certificate-transparency-go/tls"
+	fulciogrpc "github.com/sigstore/fulcio/pkg/generated/protobuf"
+	"github.com/sigstore/fulcio/pkg/identity"
+	"github.com/sigstore/fulcio/pkg/ledger"
+	"github.com/sigstore/fulcio/pkg/log"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// recordIssuance records an issued certificate and its SCTs in the ledger.
+func (g *grpcaCAServer) recordIssuance(ctx context.Context, cert *x509.Certificate, scts []*ct.SignedCertificateTimestamp) error {
+	var encoded [][]byte
+	for _, sct := range scts {
+		b, err := cttls.Marshal(*sct)
+		if err != nil {
+			return fmt.Errorf("marshaling SCT: %w", err)
+		}
+		encoded = append(encoded, b)
+	}
+	r, err := ledger.NewRecord(cert, encoded, time.Now())
+	if err != nil {
+		return err
+	}
+	return g.ledger.Put(ctx, r)
+}
+
+func (g *grpcaCAServer) ListIssuedCertificates(ctx context.Context, request *fulciogrpc.ListIssuedCertificatesRequest) (*fulciogrpc.ListIssuedCertificatesResponse, error) {
+	if err := g.authorizeLedgerAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	q := ledger.Query{
+		Identity:  request.GetIdentity(),
+		Issuer:    request.GetIssuer(),
+		PageSize:  int(request.GetPageSize()),
+		PageToken: request.GetPageToken(),
+	}
+	if request.GetSerialNumber() != "" {
+		serial, err := ledger.NormalizeSerialNumber(request.GetSerialNumber())
+		if err != nil {
+			return nil, handleFulcioGRPCError(ctx, codes.InvalidArgument, err, invalidSerialNumber)
+		}
+		q.SerialNumber = serial
+	}
+	if request.GetIssuedAfter() != nil {
+		q.IssuedAfter = request.GetIssuedAfter().AsTime()
+	}
+	if request.GetIssuedBefore() != nil {
+		q.IssuedBefore = request.GetIssuedBefore().AsTime()
+	}
+
+	records, next, err := g.ledger.List(ctx, q)
+	if err != nil {
+		if errors.Is(err, ledger.ErrInvalidPageToken) {
+			return nil, handleFulcioGRPCError(ctx, codes.InvalidArgument, err, invalidPageToken)
+		}
+		return nil, handleFulcioGRPCError(ctx, codes.Internal, err, queryingLedgerError)
+	}
+	resp := &fulciogrpc.ListIssuedCertificatesResponse{NextPageToken: next}
+	for _, r := range records {
+		c, err := toIssuedCertificate(r)
+		if err != nil {
+			return nil, handleFulcioGRPCError(ctx, codes.Internal, err, queryingLedgerError)
+		}
+		resp.Certificates = append(resp.Certificates, c)
+	}
+	return resp, nil
+}
+
+func (g *grpcaCAServer) GetIssuedCertificate(ctx context.Context, request *fulciogrpc.GetIssuedCertificateRequest) (*fulciogrpc.IssuedCertificate, error) {
+	if err := g.authorizeLedgerAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	serial, err := ledger.NormalizeSerialNumber(request.GetSerialNumber())
+	if err != nil {
+		return nil, handleFulcioGRPCError(ctx, codes.InvalidArgument, err, invalidSerialNumber)
+	}
+	r, err := g.ledger.Get(ctx, serial)
+	if err != nil {
+		if errors.Is(err, ledger.ErrNotFound) {
+			return nil, handleFulcioGRPCError(ctx, codes.NotFound, err, certificateNotFound)
+		}
+		return nil, handleFulcioGRPCError(ctx, codes.Internal, err, queryingLedgerError)
+	}
+	c, err := toIssuedCertificate(r)
+	if err != nil {
+		return nil, handleFulcioGRPCError(ctx, codes.Internal, err, queryingLedgerError)
+	}
+	return c, nil
+}
+
+// authorizeLedgerAdmin checks that the request carries the identity token of
+// an administrator of the issuance ledger.
+func (g *grpcaCAServer) authorizeLedgerAdmin(ctx context.Context) error {
+	if g.ledger == nil {
+		return handleFulcioGRPCError(ctx, codes.Unimplemented, errors.New("no issuance ledger configured"), ledgerNotEnabled)
+	}
+	token := tokenFromMetadata(ctx)
+	if token == "" {
+		return handleFulcioGRPCError(ctx, codes.Unauthenticated, errors.New("no identity token"), adminTokenRequired)
+	}
+	principal, err := g.issuerPool(ctx).Authenticate(ctx, token)
+	if err != nil {
+		return handleFulcioGRPCError(ctx, codes.Unauthenticated, err, invalidIdentityToken)
+	}
+	issuerURL, err := identity.ExtractIssuerURL(token)
+	if err != nil {
+		return handleFulcioGRPCError(ctx, codes.Unauthenticated, err, invalidIdentityToken)
+	}
+	name := principal.Name(ctx)
+	if g.adminIssuer == "" || issuerURL != g.adminIssuer || !slices.Contains(g.admins, name) {
+		err := fmt.Errorf("%s from %s is not an issuance ledger administrator", name, issuerURL)
+		return handleFulcioGRPCError(ctx, codes.PermissionDenied, err, notAnAdmin)
+	}
+	log.ContextLogger(ctx).Infow("Issuance ledger queried", "admin", name)
+	return nil
+}
+
+func toIssuedCertificate(r *ledger.Record) (*fulciogrpc.IssuedCertificate, error) {
+	cert, err := x509.ParseCertificate(r.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing certificate %s: %w", r.SerialNumber, err)
+	}
+	certPEM, err := cryptoutils.MarshalCertificateToPEM(cert)
+	if err != nil {
+		return nil, err
+	}
+	// Extensions are keyed by the names of their fields
+	b, err := json.Marshal(r.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]string
+	if err := json.Unmarshal(b, &all); err != nil {
+		return nil, err
+	}
+	exts := map[string]string{}
+	for k, v := range all {
+		if v != "" {
+			exts[k] = v
+		}
+	}
+	return &fulciogrpc.IssuedCertificate{
+		SerialNumber:                r.SerialNumber,
+		Identity:                    r.Identity,
+		Issuer:                      r.Issuer,
+		SubjectKeyId:                r.SubjectKeyID,
+		PublicKeySha256:             r.PublicKeySHA256,
+		Extensions:                  exts,
+		SignedCertificateTimestamps: r.SignedCertificateTimestamps,
+		IssuedAt:                    timestamppb.New(r.IssuedAt),
+		NotBefore:                   timestamppb.New(r.NotBefore),
+		NotAfter:                    timestamppb.New(r.NotAfter),
+		Certificate:                 string(certPEM),
+	}, nil
+}
@@ -0,0 +1,202 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/sigstore/fulcio/pkg/config"
+	"github.com/sigstore/fulcio/pkg/generated/protobuf"
+	"github.com/sigstore/fulcio/pkg/ledger"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAPIWithLedger(t *testing.T) {
+	emailSigner, emailIssuer := newOIDCIssuer(t)
+
+	cfg, err := config.Read([]byte(fmt.Sprintf(`{
+		"OIDCIssuers": {
+			%q: {
+				"IssuerURL": %q,
+				"ClientID": "sigstore",
+				"Type": "email"
+			}
+		}
+	}`, emailIssuer, emailIssuer)))
+	if err != nil {
+		t.Fatalf("config.Read() = %v", err)
+	}
+	ctLogs, eca := createCA(cfg, t)
+
+	store, err := ledger.NewBoltStore(filepath.Join(t.TempDir(), "ledger.db"))
+	if err != nil {
+		t.Fatalf("NewBoltStore() = %v", err)
+	}
+	defer store.Close()
+	server, conn := setupGRPCForTest(t, cfg, ctLogs, eca, WithLedger(store), WithLedgerAdmins(emailIssuer, []string{"foo@example.com"}))
+	defer func() {
+		server.Stop()
+		conn.Close()
+	}()
+	client := protobuf.NewCAClient(conn)
+
+	adminToken := emailToken(t, emailSigner, emailIssuer)
+	pubBytes, proof := generateKeyAndProof("foo@example.com", t)
+	resp, err := client.CreateSigningCertificate(context.Background(), &protobuf.CreateSigningCertificateRequest{
+		Credentials: &protobuf.Credentials{
+			Credentials: &protobuf.Credentials_OidcIdentityToken{
+				OidcIdentityToken: adminToken,
+			},
+		},
+		Key: &protobuf.CreateSigningCertificateRequest_PublicKeyRequest{
+			PublicKeyRequest: &protobuf.PublicKeyRequest{
+				PublicKey: &protobuf.PublicKey{
+					Content: pubBytes,
+				},
+				ProofOfPossession: proof,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SigningCert() = %v", err)
+	}
+	certs, err := cryptoutils.UnmarshalCertificatesFromPEM([]byte(resp.GetSignedCertificateEmbeddedSct().GetChain().GetCertificates()[0]))
+	if err != nil {
+		t.Fatalf("UnmarshalCertificatesFromPEM() = %v", err)
+	}
+	serial := certs[0].SerialNumber.Text(16)
+
+	adminCtx := metadata.AppendToOutgoingContext(context.Background(), MetadataOIDCTokenKey, adminToken)
+	list, err := client.ListIssuedCertificates(adminCtx, &protobuf.ListIssuedCertificatesRequest{
+		Identity: "foo@example.com",
+	})
+	if err != nil {
+		t.Fatalf("ListIssuedCertificates() = %v", err)
+	}
+	if len(list.Certificates) != 1 {
+		t.Fatalf("expected 1 certificate, got %d", len(list.Certificates))
+	}
+	got := list.Certificates[0]
+	switch {
+	case got.SerialNumber != serial:
+		t.Errorf("expected serial number %s, got %s", serial, got.SerialNumber)
+	case got.Issuer != emailIssuer || got.Extensions["Issuer"] != emailIssuer:
+		t.Errorf("unexpected issuer %q, extensions %v", got.Issuer, got.Extensions)
+	case len(got.SignedCertificateTimestamps) != 1:
+		t.Errorf("expected 1 SCT, got %d", len(got.SignedCertificateTimestamps))
+	case got.PublicKeySha256 == "" || got.SubjectKeyId == "":
+		t.Errorf("expected key identifiers, got %+v", got)
+	case !strings.Contains(got.Certificate, "BEGIN CERTIFICATE"):
+		t.Errorf("expected a PEM-encoded certificate, got %q", got.Certificate)
+	}
+
+	// Certificates are selected by issuer and time range
+	for _, test := range []struct {
+		req  *protobuf.ListIssuedCertificatesRequest
+		want int
+	}{
+		{&protobuf.ListIssuedCertificatesRequest{Issuer: emailIssuer}, 1},
+		{&protobuf.ListIssuedCertificatesRequest{Identity: "bar@example.com"}, 0},
+		{&protobuf.ListIssuedCertificatesRequest{IssuedBefore: got.IssuedAt}, 0},
+		{&protobuf.ListIssuedCertificatesRequest{IssuedAfter: got.IssuedAt}, 1},
+		{&protobuf.ListIssuedCertificatesRequest{SerialNumber: serial}, 1},
+	} {
+		list, err := client.ListIssuedCertificates(adminCtx, test.req)
+		if err != nil {
+			t.Fatalf("ListIssuedCertificates(%v) = %v", test.req, err)
+		}
+		if len(list.Certificates) != test.want {
+			t.Errorf("ListIssuedCertificates(%v) returned %d certificates, want %d", test.req, len(list.Certificates), test.want)
+		}
+	}
+
+	// Serial numbers may be separated by colons, as printed by OpenSSL
+	var colons []string
+	hexSerial := fmt.Sprintf("%040x", certs[0].SerialNumber)
+	for i := 0; i < len(hexSerial); i += 2 {
+		colons = append(colons, hexSerial[i:i+2])
+	}
+	cert, err := client.GetIssuedCertificate(adminCtx, &protobuf.GetIssuedCertificateRequest{SerialNumber: strings.Join(colons, ":")})
+	if err != nil {
+		t.Fatalf("GetIssuedCertificate() = %v", err)
+	}
+	if cert.Identity != "foo@example.com" {
+		t.Errorf("unexpected identity %q", cert.Identity)
+	}
+
+	for name, test := range map[string]struct {
+		ctx  context.Context
+		req  *protobuf.GetIssuedCertificateRequest
+		code codes.Code
+	}{
+		"unknown serial number": {adminCtx, &protobuf.GetIssuedCertificateRequest{SerialNumber: "1234"}, codes.NotFound},
+		"invalid serial number": {adminCtx, &protobuf.GetIssuedCertificateRequest{SerialNumber: "xyz"}, codes.InvalidArgument},
+		"no token":              {context.Background(), &protobuf.GetIssuedCertificateRequest{SerialNumber: serial}, codes.Unauthenticated},
+		"invalid token": {
+			metadata.AppendToOutgoingContext(context.Background(), MetadataOIDCTokenKey, "invalid"),
+			&protobuf.GetIssuedCertificateRequest{SerialNumber: serial},
+			codes.Unauthenticated,
+		},
+		"not an administrator": {
+			metadata.AppendToOutgoingContext(context.Background(), MetadataOIDCTokenKey, ledgerToken(t, emailSigner, emailIssuer, "bar@example.com")),
+			&protobuf.GetIssuedCertificateRequest{SerialNumber: serial},
+			codes.PermissionDenied,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := client.GetIssuedCertificate(test.ctx, test.req)
+			if status.Code(err) != test.code {
+				t.Errorf("expected %v, got %v", test.code, err)
+			}
+		})
+	}
+
+	// Without a ledger, certificates can't be looked up
+	noLedger, noLedgerConn := setupGRPCForTest(t, cfg, ctLogs, eca)
+	defer func() {
+		noLedger.Stop()
+		noLedgerConn.Close()
+	}()
+	_, err = protobuf.NewCAClient(noLedgerConn).ListIssuedCertificates(adminCtx, &protobuf.ListIssuedCertificatesRequest{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("expected Unimplemented, got %v", err)
+	}
+}
+
+// ledgerToken returns a token from issuer for email.
+func ledgerToken(t *testing.T, signer jose.Signer, issuer, email string) string {
+	t.Helper()
+	tok, err := jwt.Signed(signer).Claims(jwt.Claims{
+		Issuer:   issuer,
+		IssuedAt: jwt.NewNumericDate(time.Now()),
+		Expiry:   jwt.NewNumericDate(time.Now().Add(30 * time.Minute)),
+		Subject:  email,
+		Audience: jwt.Audience{"sigstore"},
+	}).Claims(customClaims{Email: email, EmailVerified: true}).Serialize()
+	if err != nil {
+		t.Fatalf("Serialize() = %v", err)
+	}
+	return tok
+}
@@ -16,11 +16,29 @@
 package server
 
 import (
+	"context"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sigstore/fulcio/pkg/config"
 	"sigs.k8s.io/release-utils/version"
 )
 
+// Reasons a certificate request is rejected, which label
+// metricRequestsRejected.
+const (
+	rejectInvalidToken          = "invalid_token"
+	rejectUnknownIssuer         = "unknown_issuer"
+	rejectInvalidRequest        = "invalid_request"
+	rejectProofOfPossession     = "bad_proof_of_possession"
+	rejectAlgorithmNotPermitted = "algorithm_not_permitted"
+	rejectPolicyDenied          = "policy_denied"
+	rejectTokenReplayed         = "token_replayed"
+	rejectCAError               = "ca_error"
+	rejectCTError               = "ct_error"
+	rejectInternalError         = "internal_error"
+)
+
 var (
 	metricNewEntries = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "fulcio_new_certs",
@@ -37,6 +55,59 @@ var (
 		Help: "Count all HTTP requests",
 	}, []string{"code", "method"})
 
+	metricCertificatesIssued = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulcio_certificates_issued_total",
+		Help: "The total number of certificates issued, by issuer type, configured OIDC issuer and CA backend",
+	}, []string{"issuer_type", "issuer", "ca_backend"})
+
+	metricRequestsRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulcio_certificate_requests_rejected_total",
+		Help: "The total number of certificate requests rejected, by reason",
+	}, []string{"reason"})
+
+	metricTokenVerificationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fulcio_token_verification_duration_seconds",
+		Help:    "Latency of identity token verification, including OIDC discovery and key fetches, by issuer type and result",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"issuer_type", "result"})
+
+	metricCASigningDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fulcio_ca_signing_duration_seconds",
+		Help:    "Latency of creating certificates with the CA backend, by backend, certificate kind and result",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"ca_backend", "operation", "result"})
+
+	metricCTSubmissionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fulcio_ct_submission_duration_seconds",
+		Help:    "Latency of submitting a certificate to all CT logs, including retries, by operation and result",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation", "result"})
+
+	metricCTQueuedCerts = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "fulcio_ct_queued_certs_total",
+		Help: "The total number of certificates issued without an SCT and queued for CT submission while the CT logs were unavailable",
+	})
+
+	metricReplaysRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulcio_token_replays_rejected_total",
+		Help: "The total number of requests rejected for reusing an identity token, by configured issuer or meta issuer pattern",
+	}, []string{"issuer"})
+
+	metricPolicyDenials = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fulcio_issuance_policy_denials_total",
+		Help: "The total number of requests denied by each issuance policy rule, by configured issuer or meta issuer pattern",
+	}, []string{"issuer", "rule"})
+
+	metricConfigReloads = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "fulcio_config_reloads_total",
+		Help: "The total number of times a changed config was loaded",
+	})
+
+	metricConfigReloadFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "fulcio_config_reload_failures_total",
+		Help: "The total number of config reloads that failed, leaving the previous config in place",
+	})
+
 	_ = promauto.NewGaugeFunc(
 		prometheus.GaugeOpts{
 			Namespace: "fulcio",
@@ -52,3 +123,38 @@ var (
 		func() float64 { return 1 },
 	)
 )
+
+// metricResult returns the result label of an operation that returned err.
+func metricResult(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// issuerLabels returns the type and configured URL of the issuer for
+// issuerURL, and whether one is configured. Issuers matched by a
+// MetaIssuers pattern are labelled with the pattern, so that each matching
+// URL does not create new series.
+func issuerLabels(ctx context.Context, issuerURL string) (issuerType, issuer string, ok bool) {
+	cfg := config.FromContext(ctx)
+	if cfg == nil {
+		return "unknown", "unknown", false
+	}
+	if iss, ok := cfg.OIDCIssuers[issuerURL]; ok {
+		return iss.Type.String(), issuerURL, true
+	}
+	if meta, iss, ok := cfg.MatchMetaIssuer(issuerURL); ok {
+		return iss.Type.String(), meta, true
+	}
+	return "unknown", "unknown", false
+}
+
+// caBackendLabel returns the label of the CA backend named by an issuer's
+// CA setting.
+func caBackendLabel(name string) string {
+	if name == "" {
+		return "default"
+	}
+	return name
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sigstore/fulcio/pkg/config"
+	"github.com/sigstore/fulcio/pkg/generated/protobuf"
+)
+
+func TestAPIMetrics(t *testing.T) {
+	emailSigner, emailIssuer := newOIDCIssuer(t)
+	unknownSigner, unknownIssuer := newOIDCIssuer(t)
+
+	cfg, err := config.Read([]byte(fmt.Sprintf(`{
+		"OIDCIssuers": {
+			%q: {
+				"IssuerURL": %q,
+				"ClientID": "sigstore",
+				"Type": "email"
+			}
+		}
+	}`, emailIssuer, emailIssuer)))
+	if err != nil {
+		t.Fatalf("config.Read() = %v", err)
+	}
+	ctLogs, eca := createCA(cfg, t)
+
+	server, conn := setupGRPCForTest(t, cfg, ctLogs, eca)
+	defer func() {
+		server.Stop()
+		conn.Close()
+	}()
+	client := protobuf.NewCAClient(conn)
+
+	request := func(token, signed string) error {
+		pubBytes, proof := generateKeyAndProof(signed, t)
+		_, err := client.CreateSigningCertificate(context.Background(), &protobuf.CreateSigningCertificateRequest{
+			Credentials: &protobuf.Credentials{
+				Credentials: &protobuf.Credentials_OidcIdentityToken{
+					OidcIdentityToken: token,
+				},
+			},
+			Key: &protobuf.CreateSigningCertificateRequest_PublicKeyRequest{
+				PublicKeyRequest: &protobuf.PublicKeyRequest{
+					PublicKey: &protobuf.PublicKey{
+						Content: pubBytes,
+					},
+					ProofOfPossession: proof,
+				},
+			},
+		})
+		return err
+	}
+
+	issued := metricCertificatesIssued.WithLabelValues("email", emailIssuer, "default")
+	before := testutil.ToFloat64(issued)
+	if err := request(emailToken(t, emailSigner, emailIssuer), "foo@example.com"); err != nil {
+		t.Fatalf("SigningCert() = %v", err)
+	}
+	if got := testutil.ToFloat64(issued); got != before+1 {
+		t.Errorf("expected %v certificates issued, got %v", before+1, got)
+	}
+
+	for reason, test := range map[string]struct {
+		token  string
+		signed string
+	}{
+		rejectUnknownIssuer:     {emailToken(t, unknownSigner, unknownIssuer), "foo@example.com"},
+		rejectInvalidToken:      {"not a token", "foo@example.com"},
+		rejectProofOfPossession: {emailToken(t, emailSigner, emailIssuer), "bar@example.com"},
+	} {
+		t.Run(reason, func(t *testing.T) {
+			rejected := metricRequestsRejected.WithLabelValues(reason)
+			before := testutil.ToFloat64(rejected)
+			if err := request(test.token, test.signed); err == nil {
+				t.Fatal("expected error")
+			}
+			if got := testutil.ToFloat64(rejected); got != before+1 {
+				t.Errorf("expected %v requests rejected, got %v", before+1, got)
+			}
+		})
+	}
+}
+
+func TestSigningChainCollector(t *testing.T) {
+	_, eca := createCA(nil, t)
+	chains, err := eca.TrustBundle(context.Background())
+	if err != nil {
+		t.Fatalf("TrustBundle() = %v", err)
+	}
+	root := chains[0][0]
+
+	c := NewSigningChainCollector(eca).(*signingChainCollector)
+	c.now = func() time.Time { return root.NotAfter.Add(-time.Hour) }
+	want := fmt.Sprintf(`
+# HELP fulcio_signing_chain_remaining_validity_seconds Seconds until each certificate of the CA's signing chains expires, by chain, position in the chain from the issuing certificate, and subject
+# TYPE fulcio_signing_chain_remaining_validity_seconds gauge
+fulcio_signing_chain_remaining_validity_seconds{chain="0",position="0",subject=%q} 3600
+`, root.Subject.String())
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/x509"
+
+	"github.com/sigstore/fulcio/pkg/identity"
+	"github.com/sigstore/fulcio/pkg/log"
+	"github.com/sigstore/fulcio/pkg/notify"
+)
+
+// notifyIssuance queues an event for an issued certificate, which is posted
+// to the notifier's endpoints in the background. Errors are only logged,
+// since notifications never fail issuance.
+func (g *grpcaCAServer) notifyIssuance(ctx context.Context, token string, cert *x509.Certificate) {
+	claims, err := identity.ExtractClaims(token)
+	if err != nil {
+		log.ContextLogger(ctx).Errorf("error reading claims for issuance notification: %v", err)
+		return
+	}
+	e, err := notify.NewEvent(cert, claims)
+	if err != nil {
+		log.ContextLogger(ctx).Errorf("error creating issuance notification: %v", err)
+		return
+	}
+	g.notifier.Notify(ctx, e)
+}
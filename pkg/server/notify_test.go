@@ -0,0 +1,116 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sigstore/fulcio/pkg/config"
+	"github.com/sigstore/fulcio/pkg/generated/protobuf"
+	"github.com/sigstore/fulcio/pkg/notify"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+func TestAPIWithNotifier(t *testing.T) {
+	emailSigner, emailIssuer := newOIDCIssuer(t)
+
+	cfg, err := config.Read([]byte(fmt.Sprintf(`{
+		"OIDCIssuers": {
+			%q: {
+				"IssuerURL": %q,
+				"ClientID": "sigstore",
+				"Type": "email"
+			}
+		}
+	}`, emailIssuer, emailIssuer)))
+	if err != nil {
+		t.Fatalf("config.Read() = %v", err)
+	}
+	ctLogs, eca := createCA(cfg, t)
+
+	events := make(chan notify.Event, 10)
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ce struct {
+			Data notify.Event `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&ce); err != nil {
+			t.Errorf("decoding event: %v", err)
+		}
+		events <- ce.Data
+	}))
+	defer endpoint.Close()
+	n, err := notify.NewNotifier([]notify.Endpoint{{
+		Name:    "alerts",
+		URL:     endpoint.URL,
+		Filters: []notify.Filter{{"email": {"foo@example.com"}}},
+	}})
+	if err != nil {
+		t.Fatalf("NewNotifier() = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go n.Run(ctx)
+
+	server, conn := setupGRPCForTest(t, cfg, ctLogs, eca, WithNotifier(n))
+	defer func() {
+		server.Stop()
+		conn.Close()
+	}()
+	client := protobuf.NewCAClient(conn)
+
+	pubBytes, proof := generateKeyAndProof("foo@example.com", t)
+	resp, err := client.CreateSigningCertificate(context.Background(), &protobuf.CreateSigningCertificateRequest{
+		Credentials: &protobuf.Credentials{
+			Credentials: &protobuf.Credentials_OidcIdentityToken{
+				OidcIdentityToken: emailToken(t, emailSigner, emailIssuer),
+			},
+		},
+		Key: &protobuf.CreateSigningCertificateRequest_PublicKeyRequest{
+			PublicKeyRequest: &protobuf.PublicKeyRequest{
+				PublicKey: &protobuf.PublicKey{
+					Content: pubBytes,
+				},
+				ProofOfPossession: proof,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SigningCert() = %v", err)
+	}
+	certs, err := cryptoutils.UnmarshalCertificatesFromPEM([]byte(resp.GetSignedCertificateEmbeddedSct().GetChain().GetCertificates()[0]))
+	if err != nil {
+		t.Fatalf("UnmarshalCertificatesFromPEM() = %v", err)
+	}
+
+	select {
+	case e := <-events:
+		switch {
+		case e.SerialNumber != certs[0].SerialNumber.Text(16):
+			t.Errorf("expected serial number %s, got %s", certs[0].SerialNumber.Text(16), e.SerialNumber)
+		case e.SubjectAlternativeName != "foo@example.com":
+			t.Errorf("unexpected subject alternative name %q", e.SubjectAlternativeName)
+		case e.Issuer != emailIssuer || e.Extensions.Issuer != emailIssuer:
+			t.Errorf("unexpected issuer %q, extensions %+v", e.Issuer, e.Extensions)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for issuance event")
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sigstore/fulcio/pkg/config"
+	"github.com/sigstore/fulcio/pkg/identity"
+	"github.com/sigstore/fulcio/pkg/log"
+	"google.golang.org/grpc"
+)
+
+// servingConfig is a FulcioConfig and the IssuerPool built from it, which
+// are swapped together, and handed to a request together by
+// ConfigReloader.With, so that a request never sees one without the other.
+type servingConfig struct {
+	cfg *config.FulcioConfig
+	ip  identity.IssuerPool
+	raw []byte
+}
+
+// ConfigReloader holds the FulcioConfig and IssuerPool being served and
+// replaces them when the config file changes. If a new config fails to
+// load, the previous one continues to be served.
+type ConfigReloader struct {
+	path       string
+	validators []func(*config.FulcioConfig) error
+	mu         sync.Mutex // serializes reloads
+	current    atomic.Pointer[servingConfig]
+}
+
+// ConfigReloaderOption configures a ConfigReloader.
+type ConfigReloaderOption func(*ConfigReloader)
+
+// WithConfigValidator adds a check that a reloaded config must pass before it
+// is served, for settings that depend on more than the config file, such as
+// the CA backends that issuers name.
+func WithConfigValidator(validate func(*config.FulcioConfig) error) ConfigReloaderOption {
+	return func(r *ConfigReloader) {
+		r.validators = append(r.validators, validate)
+	}
+}
+
+// NewConfigReloader returns a ConfigReloader serving cfg, which was loaded
+// from path. An empty path disables reloading.
+func NewConfigReloader(path string, cfg *config.FulcioConfig, opts ...ConfigReloaderOption) *ConfigReloader {
+	r := &ConfigReloader{path: path}
+	for _, opt := range opts {
+		opt(r)
+	}
+	sc := &servingConfig{cfg: cfg}
+	if cfg != nil {
+		sc.ip = NewIssuerPool(cfg)
+	}
+	if path != "" {
+		// Used to skip reloads of unchanged files, which a missing file
+		// never matches
+		sc.raw, _ = os.ReadFile(path)
+	}
+	r.current.Store(sc)
+	return r
+}
+
+// Config returns the FulcioConfig currently being served.
+func (r *ConfigReloader) Config() *config.FulcioConfig {
+	return r.current.Load().cfg
+}
+
+// IssuerPool returns the IssuerPool built from the current config.
+func (r *ConfigReloader) IssuerPool() identity.IssuerPool {
+	return r.current.Load().ip
+}
+
+type servingConfigKey struct{}
+
+// With returns a context carrying the current config, as returned by
+// config.FromContext, and the IssuerPool built from it. Both are taken from
+// one snapshot, so a request is authenticated against the issuers of the
+// config it is served with even if the config is reloaded mid-request.
+func (r *ConfigReloader) With(ctx context.Context) context.Context {
+	sc := r.current.Load()
+	return context.WithValue(config.With(ctx, sc.cfg), servingConfigKey{}, sc)
+}
+
+// UnaryInterceptor returns a gRPC interceptor that gives each request the
+// context returned by With when it arrives, so that config updates are
+// picked up without cycling pods.
+func (r *ConfigReloader) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, cancel := context.WithCancel(r.With(ctx))
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// issuerPoolFor returns the IssuerPool of the config ctx was given by With,
+// or of the current config if ctx does not carry one.
+func (r *ConfigReloader) issuerPoolFor(ctx context.Context) identity.IssuerPool {
+	if sc, ok := ctx.Value(servingConfigKey{}).(*servingConfig); ok {
+		return sc.ip
+	}
+	return r.IssuerPool()
+}
+
+// Reload reads and validates the config file and, if it is valid and has
+// changed, swaps it in. On failure the current config is kept.
+func (r *ConfigReloader) Reload() error {
+	if r.path == "" {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, err := os.ReadFile(r.path)
+	if err != nil {
+		metricConfigReloadFailures.Inc()
+		return fmt.Errorf("reading config: %w", err)
+	}
+	if bytes.Equal(b, r.current.Load().raw) {
+		return nil
+	}
+	// Read validates the config and builds its verifiers and meta issuer
+	// cache, so a config that fails here is never served
+	cfg, err := config.Read(b)
+	if err != nil {
+		metricConfigReloadFailures.Inc()
+		return fmt.Errorf("loading config: %w", err)
+	}
+	for _, validate := range r.validators {
+		if err := validate(cfg); err != nil {
+			metricConfigReloadFailures.Inc()
+			return fmt.Errorf("validating config: %w", err)
+		}
+	}
+	r.current.Store(&servingConfig{cfg: cfg, ip: NewIssuerPool(cfg), raw: b})
+	metricConfigReloads.Inc()
+	log.Logger.Infof("reloaded config from %s", r.path)
+	return nil
+}
+
+// Watch reloads the config whenever the config file changes, until ctx is
+// done. The file's directory is watched rather than the file itself, so that
+// files replaced by a rename, such as Kubernetes ConfigMap volumes, are
+// picked up.
+func (r *ConfigReloader) Watch(ctx context.Context) error {
+	if r.path == "" {
+		return nil
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(r.path)
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				// Kubernetes updates ConfigMap volumes by swapping the
+				// ..data symlink
+				if filepath.Clean(event.Name) != filepath.Clean(r.path) && filepath.Base(event.Name) != "..data" {
+					continue
+				}
+				if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+					continue
+				}
+				if err := r.Reload(); err != nil {
+					log.Logger.Errorf("error reloading config from %s, continuing with previous config: %v", r.path, err)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Logger.Error("fsnotify config error:", err)
+			}
+		}
+	}()
+	return nil
+}
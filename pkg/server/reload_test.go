@@ -0,0 +1,193 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sigstore/fulcio/pkg/config"
+)
+
+func emailIssuersConfig(issuers ...string) string {
+	var entries []string
+	for _, iss := range issuers {
+		entries = append(entries, fmt.Sprintf(`%q: {"IssuerURL": %q, "ClientID": "sigstore", "Type": "email"}`, iss, iss))
+	}
+	return `{"OIDCIssuers": {` + strings.Join(entries, ",") + `}}`
+}
+
+func emailToken(t *testing.T, signer jose.Signer, issuer string) string {
+	t.Helper()
+	tok, err := jwt.Signed(signer).Claims(jwt.Claims{
+		Issuer:   issuer,
+		IssuedAt: jwt.NewNumericDate(time.Now()),
+		Expiry:   jwt.NewNumericDate(time.Now().Add(30 * time.Minute)),
+		Subject:  "foo@example.com",
+		Audience: jwt.Audience{"sigstore"},
+	}).Claims(customClaims{Email: "foo@example.com", EmailVerified: true}).Serialize()
+	if err != nil {
+		t.Fatalf("Serialize() = %v", err)
+	}
+	return tok
+}
+
+func writeConfig(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfigReloader(t *testing.T) {
+	ctx := context.Background()
+	_, oldIssuer := newOIDCIssuer(t)
+	newSigner, newIssuer := newOIDCIssuer(t)
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeConfig(t, path, emailIssuersConfig(oldIssuer))
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewConfigReloader(path, cfg)
+
+	tok := emailToken(t, newSigner, newIssuer)
+	if _, err := r.IssuerPool().Authenticate(config.With(ctx, r.Config()), tok); err == nil {
+		t.Fatal("expected token from unconfigured issuer to be rejected")
+	}
+
+	// Unchanged files are not reloaded
+	reloads := testutil.ToFloat64(metricConfigReloads)
+	if err := r.Reload(); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+	if got := testutil.ToFloat64(metricConfigReloads); got != reloads {
+		t.Errorf("expected unchanged config not to be reloaded, reloads went from %v to %v", reloads, got)
+	}
+
+	// Adding an issuer takes effect on reload, but not for requests that
+	// were already being served
+	reqCtx := r.With(ctx)
+	writeConfig(t, path, emailIssuersConfig(oldIssuer, newIssuer))
+	if err := r.Reload(); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+	if _, ok := config.FromContext(reqCtx).GetIssuer(newIssuer); ok {
+		t.Error("expected request context to keep the config it was served with")
+	}
+	if _, err := r.issuerPoolFor(reqCtx).Authenticate(reqCtx, tok); err == nil {
+		t.Error("expected request context to keep the issuer pool of its config")
+	}
+	if _, err := r.issuerPoolFor(r.With(ctx)).Authenticate(r.With(ctx), tok); err != nil {
+		t.Errorf("expected new request context to use the reloaded issuer pool, got %v", err)
+	}
+	if _, ok := r.Config().GetIssuer(newIssuer); !ok {
+		t.Error("expected reloaded config to contain new issuer")
+	}
+	_, _ = r.UnaryInterceptor()(ctx, nil, nil, func(ctx context.Context, _ any) (any, error) {
+		if _, ok := config.FromContext(ctx).GetIssuer(newIssuer); !ok {
+			t.Error("expected intercepted requests to be served the reloaded config")
+		}
+		return nil, nil
+	})
+	if _, err := r.IssuerPool().Authenticate(config.With(ctx, r.Config()), tok); err != nil {
+		t.Fatalf("expected token from new issuer to be accepted, got %v", err)
+	}
+
+	// A bad file keeps the previous config
+	failures := testutil.ToFloat64(metricConfigReloadFailures)
+	writeConfig(t, path, `{"OIDCIssuers": {"https://issuer.example.com": {"IssuerURL": "https://issuer.example.com", "Type": "spiffe"}}}`)
+	if err := r.Reload(); err == nil {
+		t.Fatal("expected invalid config to fail to reload")
+	}
+	if got := testutil.ToFloat64(metricConfigReloadFailures); got != failures+1 {
+		t.Errorf("expected failure to be counted, failures went from %v to %v", failures, got)
+	}
+	if _, err := r.IssuerPool().Authenticate(config.With(ctx, r.Config()), tok); err != nil {
+		t.Fatalf("expected previous config to be kept, got %v", err)
+	}
+}
+
+func TestConfigReloaderWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, oldIssuer := newOIDCIssuer(t)
+	_, newIssuer := newOIDCIssuer(t)
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeConfig(t, path, emailIssuersConfig(oldIssuer))
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewConfigReloader(path, cfg)
+	if err := r.Watch(ctx); err != nil {
+		t.Fatalf("Watch() = %v", err)
+	}
+
+	// Replace the file by renaming over it, as editors and Kubernetes do
+	tmp := filepath.Join(filepath.Dir(path), "config.json.tmp")
+	writeConfig(t, tmp, emailIssuersConfig(oldIssuer, newIssuer))
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if _, ok := r.Config().GetIssuer(newIssuer); ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("config was not reloaded after the file changed")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestConfigReloaderValidator(t *testing.T) {
+	_, oldIssuer := newOIDCIssuer(t)
+	_, newIssuer := newOIDCIssuer(t)
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeConfig(t, path, emailIssuersConfig(oldIssuer))
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewConfigReloader(path, cfg, WithConfigValidator(func(cfg *config.FulcioConfig) error {
+		if _, ok := cfg.GetIssuer(newIssuer); ok {
+			return fmt.Errorf("issuer %s is not allowed", newIssuer)
+		}
+		return nil
+	}))
+
+	writeConfig(t, path, emailIssuersConfig(oldIssuer, newIssuer))
+	if err := r.Reload(); err == nil {
+		t.Fatal("expected config rejected by validator to fail to reload")
+	}
+	if _, ok := r.Config().GetIssuer(newIssuer); ok {
+		t.Error("expected previous config to be kept")
+	}
+}
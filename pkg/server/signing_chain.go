@@ -0,0 +1,90 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	certauth "github.com/sigstore/fulcio/pkg/ca"
+	"github.com/sigstore/fulcio/pkg/log"
+)
+
+// signingChainRefreshInterval is how long the trust bundle is cached
+// between scrapes, since fetching it may call a remote CA.
+const signingChainRefreshInterval = time.Minute
+
+var signingChainValidityDesc = prometheus.NewDesc(
+	"fulcio_signing_chain_remaining_validity_seconds",
+	"Seconds until each certificate of the CA's signing chains expires, by chain, position in the chain from the issuing certificate, and subject",
+	[]string{"chain", "position", "subject"}, nil)
+
+// signingChainCollector reports the remaining validity of the certificates
+// of a CA's signing chains.
+type signingChainCollector struct {
+	ca  certauth.CertificateAuthority
+	now func() time.Time
+
+	mu        sync.Mutex
+	chains    [][]*x509.Certificate
+	fetchedAt time.Time
+}
+
+// NewSigningChainCollector returns a collector reporting how long each
+// certificate of ca's signing chains remains valid, so that expiring
+// chains can be alerted on.
+func NewSigningChainCollector(ca certauth.CertificateAuthority) prometheus.Collector {
+	return &signingChainCollector{ca: ca, now: time.Now}
+}
+
+// Describe implements prometheus.Collector.
+func (c *signingChainCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- signingChainValidityDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *signingChainCollector) Collect(ch chan<- prometheus.Metric) {
+	now := c.now()
+	for i, chain := range c.trustBundle(now) {
+		for j, cert := range chain {
+			ch <- prometheus.MustNewConstMetric(signingChainValidityDesc, prometheus.GaugeValue,
+				cert.NotAfter.Sub(now).Seconds(), strconv.Itoa(i), strconv.Itoa(j), cert.Subject.String())
+		}
+	}
+}
+
+// trustBundle returns the CA's chains, fetching them if the cached chains
+// are older than signingChainRefreshInterval. The cached chains are
+// returned if fetching fails.
+func (c *signingChainCollector) trustBundle(now time.Time) [][]*x509.Certificate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.chains != nil && now.Sub(c.fetchedAt) < signingChainRefreshInterval {
+		return c.chains
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	chains, err := c.ca.TrustBundle(ctx)
+	if err != nil {
+		log.Logger.Warnf("error fetching CA trust bundle for metrics: %v", err)
+		return c.chains
+	}
+	c.chains, c.fetchedAt = chains, now
+	return chains
+}
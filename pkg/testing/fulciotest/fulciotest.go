@@ -0,0 +1,306 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fulciotest runs a complete Fulcio in-process for end-to-end tests
+// of code that requests certificates. A Server issues certificates from an
+// ephemeral CA for tokens of its own OIDC issuers, and logs them to an
+// in-memory CT log that returns real SCTs.
+package fulciotest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	ctclient "github.com/google/certificate-transparency-go/client"
+	"github.com/google/certificate-transparency-go/jsonclient"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/sigstore/fulcio/pkg/ca/ephemeralca"
+	"github.com/sigstore/fulcio/pkg/config"
+	"github.com/sigstore/fulcio/pkg/ctl"
+	gw "github.com/sigstore/fulcio/pkg/generated/protobuf"
+	legacy_gw "github.com/sigstore/fulcio/pkg/generated/protobuf/legacy"
+	"github.com/sigstore/fulcio/pkg/server"
+	v1 "github.com/sigstore/protobuf-specs/gen/pb-go/common/v1"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	health "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// DefaultClientID is the client ID of issuers that do not set one, which
+// tokens are minted for.
+const DefaultClientID = "sigstore"
+
+// DefaultIssuer is the name of the email issuer configured when no issuers
+// are given with WithIssuer.
+const DefaultIssuer = "email"
+
+// Server is an in-process Fulcio serving gRPC and HTTP on ephemeral ports of
+// the loopback interface.
+type Server struct {
+	// GRPCAddr is the host:port of the plaintext gRPC API.
+	GRPCAddr string
+	// URL is the base URL of the HTTP API, e.g. "http://127.0.0.1:1234".
+	URL string
+	// CTLogURL is the URL of the RFC 6962 API of the CT log.
+	CTLogURL string
+
+	// CA issues the certificates.
+	CA *ephemeralca.EphemeralCA
+	// CTLog is the CT log that certificates are submitted to.
+	CTLog *ctl.MemoryLog
+	// Config is the config of the server, with an OIDC issuer for each
+	// issuer given with WithIssuer.
+	Config *config.FulcioConfig
+
+	oidc       *oidcServer
+	ctServer   *httptest.Server
+	grpcServer *grpc.Server
+	httpServer *http.Server
+	conn       *grpc.ClientConn
+}
+
+type options struct {
+	issuers          map[string]config.OIDCIssuer
+	ciIssuerMetadata map[string]config.IssuerMetadata
+	serverOpts       []server.GRPCCAServerOption
+}
+
+// Option configures the Server returned by NewServer.
+type Option func(*options)
+
+// WithIssuer adds an OIDC issuer named name, configured with iss. Its issuer
+// URL is set by the server, and its client ID defaults to DefaultClientID.
+// Names must be valid URL path segments.
+func WithIssuer(name string, iss config.OIDCIssuer) Option {
+	return func(o *options) {
+		o.issuers[name] = iss
+	}
+}
+
+// WithCIIssuerMetadata adds the metadata of a CI provider, for issuers of
+// type ci-provider.
+func WithCIIssuerMetadata(provider string, metadata config.IssuerMetadata) Option {
+	return func(o *options) {
+		o.ciIssuerMetadata[provider] = metadata
+	}
+}
+
+// WithServerOptions configures the CA service with opts, e.g. to attach an
+// audit sink.
+func WithServerOptions(opts ...server.GRPCCAServerOption) Option {
+	return func(o *options) {
+		o.serverOpts = append(o.serverOpts, opts...)
+	}
+}
+
+// NewServer starts a Server. It must be closed with Close.
+func NewServer(opts ...Option) (*Server, error) {
+	o := &options{
+		issuers:          map[string]config.OIDCIssuer{},
+		ciIssuerMetadata: map[string]config.IssuerMetadata{},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if len(o.issuers) == 0 {
+		o.issuers[DefaultIssuer] = config.OIDCIssuer{Type: config.IssuerTypeEmail}
+	}
+
+	s := &Server{}
+	if err := s.start(o); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Server) start(o *options) error {
+	var err error
+	if s.oidc, err = newOIDCServer(); err != nil {
+		return err
+	}
+	if s.Config, err = s.config(o); err != nil {
+		return err
+	}
+	if s.CA, err = ephemeralca.NewEphemeralCA(); err != nil {
+		return fmt.Errorf("creating CA: %w", err)
+	}
+	ctLogs, err := s.startCTLog()
+	if err != nil {
+		return err
+	}
+	if err := s.startGRPC(ctLogs, o.serverOpts); err != nil {
+		return err
+	}
+	return s.startHTTP()
+}
+
+// config returns the config of the issuers, validated and loaded as by
+// config.Read, which discovers each issuer.
+func (s *Server) config(o *options) (*config.FulcioConfig, error) {
+	cfg := &config.FulcioConfig{
+		OIDCIssuers:      map[string]config.OIDCIssuer{},
+		CIIssuerMetadata: o.ciIssuerMetadata,
+	}
+	for name, iss := range o.issuers {
+		if !validIssuerName(name) {
+			return nil, fmt.Errorf("invalid issuer name %q", name)
+		}
+		iss.IssuerURL = s.oidc.issuerURL(name)
+		if iss.ClientID == "" {
+			iss.ClientID = DefaultClientID
+		}
+		cfg.OIDCIssuers[iss.IssuerURL] = iss
+	}
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	loaded, err := config.Read(b)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	return loaded, nil
+}
+
+func (s *Server) startCTLog() (*ctl.MultiLog, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating CT log key: %w", err)
+	}
+	if s.CTLog, err = ctl.NewMemoryLog(key); err != nil {
+		return nil, err
+	}
+	s.ctServer = httptest.NewServer(s.CTLog.Handler())
+	s.CTLogURL = s.ctServer.URL
+
+	pub, err := cryptoutils.MarshalPublicKeyToPEM(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	// Fulcio verifies the SCTs of the log, as it does in production when
+	// given the log's public key
+	client, err := ctclient.New(s.CTLogURL, s.ctServer.Client(), jsonclient.Options{PublicKey: string(pub)})
+	if err != nil {
+		return nil, fmt.Errorf("creating CT log client: %w", err)
+	}
+	return ctl.NewMultiLog([]ctl.Log{client})
+}
+
+func (s *Server) startGRPC(ctLogs *ctl.MultiLog, opts []server.GRPCCAServerOption) error {
+	algorithmRegistry, err := signature.NewAlgorithmRegistryConfig([]v1.PublicKeyDetails{
+		v1.PublicKeyDetails_PKIX_ECDSA_P256_SHA_256,
+		v1.PublicKeyDetails_PKIX_ECDSA_P384_SHA_384,
+		v1.PublicKeyDetails_PKIX_ECDSA_P521_SHA_512,
+		v1.PublicKeyDetails_PKIX_RSA_PKCS1V15_2048_SHA256,
+		v1.PublicKeyDetails_PKIX_RSA_PKCS1V15_3072_SHA256,
+		v1.PublicKeyDetails_PKIX_RSA_PKCS1V15_4096_SHA256,
+		v1.PublicKeyDetails_PKIX_ED25519,
+	})
+	if err != nil {
+		return err
+	}
+	cfgs := server.NewConfigReloader("", s.Config)
+	opts = append([]server.GRPCCAServerOption{server.WithConfigReloader(cfgs)}, opts...)
+	ca := server.NewGRPCCAServer(ctLogs, s.CA, algorithmRegistry, cfgs.IssuerPool(), opts...)
+
+	// Requests are served from the reloader's config, as by the serve
+	// command
+	s.grpcServer = grpc.NewServer(grpc.UnaryInterceptor(cfgs.UnaryInterceptor()))
+	gw.RegisterCAServer(s.grpcServer, ca)
+	legacy_gw.RegisterCAServer(s.grpcServer, server.NewLegacyGRPCCAServer(ca))
+	health.RegisterHealthServer(s.grpcServer, ca)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("listening for gRPC: %w", err)
+	}
+	s.GRPCAddr = lis.Addr().String()
+	go func() { _ = s.grpcServer.Serve(lis) }()
+	return nil
+}
+
+func (s *Server) startHTTP() error {
+	var err error
+	s.conn, err = grpc.NewClient(s.GRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	mux := runtime.NewServeMux(
+		runtime.WithMetadata(server.ExtractOIDCTokenFromAuthHeader),
+		runtime.WithForwardResponseOption(server.SetResponseCodeModifier),
+		runtime.WithHealthzEndpoint(health.NewHealthClient(s.conn)))
+	if err := gw.RegisterCAHandlerClient(context.Background(), mux, gw.NewCAClient(s.conn)); err != nil {
+		return err
+	}
+	if err := legacy_gw.RegisterCAHandlerClient(context.Background(), mux, legacy_gw.NewCAClient(s.conn)); err != nil {
+		return err
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("listening for HTTP: %w", err)
+	}
+	s.URL = "http://" + lis.Addr().String()
+	s.httpServer = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = s.httpServer.Serve(lis) }()
+	return nil
+}
+
+// IssuerURL returns the issuer URL of the issuer named name.
+func (s *Server) IssuerURL(name string) string {
+	return s.oidc.issuerURL(name)
+}
+
+// Token returns an ID token from the issuer named name with claims, signed
+// with the issuer's key. The iss, aud, iat and exp claims default to the
+// issuer's URL and client ID, the current time and DefaultTokenLifetime
+// from now; the claims that the issuer's type requires, such as email and
+// email_verified for email issuers, must be given.
+func (s *Server) Token(name string, claims map[string]any) (string, error) {
+	iss, ok := s.Config.GetIssuer(s.IssuerURL(name))
+	if !ok {
+		return "", fmt.Errorf("no issuer named %q", name)
+	}
+	return s.oidc.token(iss.IssuerURL, iss.ClientID, claims)
+}
+
+// Close stops the server and the services it depends on.
+func (s *Server) Close() {
+	if s.httpServer != nil {
+		_ = s.httpServer.Close()
+	}
+	if s.conn != nil {
+		_ = s.conn.Close()
+	}
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
+	}
+	if s.ctServer != nil {
+		s.ctServer.Close()
+	}
+	if s.oidc != nil {
+		s.oidc.Close()
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fulciotest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/sigstore/fulcio/pkg/api"
+	"github.com/sigstore/fulcio/pkg/config"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// request returns a request for a certificate for a new key, proving
+// possession of it by signing identity.
+func request(t *testing.T, token, identity string) api.SigningCertificateRequest {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := cryptoutils.MarshalPublicKeyToPEM(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte(identity))
+	proof, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return api.SigningCertificateRequest{Token: token, PublicKey: pub, ProofOfPossession: proof}
+}
+
+func TestServer(t *testing.T) {
+	s, err := NewServer(
+		WithIssuer("email", config.OIDCIssuer{Type: config.IssuerTypeEmail}),
+		WithIssuer("spiffe", config.OIDCIssuer{Type: config.IssuerTypeSpiffe, SPIFFETrustDomain: "example.com"}))
+	if err != nil {
+		t.Fatalf("NewServer() = %v", err)
+	}
+	defer s.Close()
+	serverURL, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emailToken, err := s.Token("email", map[string]any{"sub": "1234", "email": "foo@example.com", "email_verified": true})
+	if err != nil {
+		t.Fatalf("Token() = %v", err)
+	}
+	spiffeToken, err := s.Token("spiffe", map[string]any{"sub": "spiffe://example.com/workload"})
+	if err != nil {
+		t.Fatalf("Token() = %v", err)
+	}
+
+	// Verifying the chain, identity and SCTs of each certificate checks
+	// that the server issues for its issuers and logs to its CT log
+	verifier, err := api.NewVerifier(api.WithCTLogPublicKeys(s.CTLog.PublicKey()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcClient, err := api.NewGRPCClient(s.GRPCAddr, api.WithVerifier(verifier),
+		api.WithGRPCDialOptions(grpc.WithTransportCredentials(insecure.NewCredentials())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer grpcClient.Close()
+	clients := map[string]api.Client{
+		"grpc": grpcClient,
+		"rest": api.NewRESTClient(serverURL, api.WithVerifier(verifier)),
+	}
+
+	issued := 0
+	for name, c := range clients {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			for _, tc := range []struct{ token, identity, issuer string }{
+				{emailToken, "foo@example.com", s.IssuerURL("email")},
+				{spiffeToken, "spiffe://example.com/workload", s.IssuerURL("spiffe")},
+			} {
+				cert, err := c.CreateSigningCertificate(ctx, request(t, tc.token, tc.identity))
+				if err != nil {
+					t.Fatalf("CreateSigningCertificate() = %v", err)
+				}
+				issued++
+				if v := cert.Verification; v == nil || v.Identity != tc.identity || v.Issuer != tc.issuer || len(v.SCTs) != 1 {
+					t.Errorf("unexpected verification %+v", v)
+				}
+			}
+		})
+	}
+
+	// The ephemeral CA embeds SCTs, which the legacy API returns in the
+	// certificate rather than a header
+	legacy := api.NewClient(serverURL, api.WithVerifier(verifier))
+	req := request(t, emailToken, "foo@example.com")
+	resp, err := legacy.SigningCert(api.CertificateRequest{
+		PublicKey:          api.Key{Content: req.PublicKey},
+		SignedEmailAddress: req.ProofOfPossession,
+	}, emailToken)
+	if err != nil {
+		t.Fatalf("SigningCert() = %v", err)
+	}
+	issued++
+	if v := resp.Verification; v == nil || v.Identity != "foo@example.com" || !v.EmbeddedSCT || len(v.SCTs) != 1 {
+		t.Errorf("unexpected response %+v", resp)
+	}
+
+	if got := len(s.CTLog.Entries()); got != issued {
+		t.Errorf("CT log has %d entries, want %d", got, issued)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	if _, err := NewServer(WithIssuer("a/b", config.OIDCIssuer{Type: config.IssuerTypeEmail})); err == nil {
+		t.Error("expected an invalid issuer name to be rejected")
+	}
+	if _, err := NewServer(WithIssuer("uri", config.OIDCIssuer{Type: config.IssuerTypeURI})); err == nil {
+		t.Error("expected an invalid issuer config to be rejected")
+	}
+
+	s, err := NewServer()
+	if err != nil {
+		t.Fatalf("NewServer() = %v", err)
+	}
+	defer s.Close()
+	if _, err := s.Token("other", nil); err == nil {
+		t.Error("expected a token of an unknown issuer to be rejected")
+	}
+	token, err := s.Token(DefaultIssuer, map[string]any{
+		"email":          "foo@example.com",
+		"email_verified": true,
+		"exp":            time.Now().Add(-time.Minute).Unix(),
+	})
+	if err != nil {
+		t.Fatalf("Token() = %v", err)
+	}
+	serverURL, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := api.NewRESTClient(serverURL)
+	if _, err := c.CreateSigningCertificate(context.Background(), request(t, token, "foo@example.com")); err == nil {
+		t.Error("expected an expired token to be rejected")
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fulciotest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+// DefaultTokenLifetime is how long tokens minted by the issuers are valid,
+// unless the claims set an expiry.
+const DefaultTokenLifetime = 10 * time.Minute
+
+// oidcServer serves the discovery documents and keys of the test issuers.
+// The issuer named name is served under /name, so that every issuer has
+// its own issuer URL.
+type oidcServer struct {
+	*httptest.Server
+	jwk    jose.JSONWebKey
+	signer jose.Signer
+}
+
+func newOIDCServer() (*oidcServer, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("generating OIDC signing key: %w", err)
+	}
+	jwk := jose.JSONWebKey{Key: key, KeyID: "fulciotest", Algorithm: string(jose.RS256), Use: "sig"}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: jwk},
+		(&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		return nil, err
+	}
+	o := &oidcServer{jwk: jwk, signer: signer}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{issuer}/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		issuer := o.issuerURL(r.PathValue("issuer"))
+		writeJSON(w, map[string]any{
+			"issuer":                                issuer,
+			"jwks_uri":                              issuer + "/keys",
+			"response_types_supported":              []string{"id_token"},
+			"subject_types_supported":               []string{"public"},
+			"id_token_signing_alg_values_supported": []string{string(jose.RS256)},
+		})
+	})
+	mux.HandleFunc("GET /{issuer}/keys", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{o.jwk.Public()}})
+	})
+	o.Server = httptest.NewServer(mux)
+	return o, nil
+}
+
+// issuerURL returns the URL of the issuer with the given name.
+func (o *oidcServer) issuerURL(name string) string {
+	return o.URL + "/" + name
+}
+
+// token returns an ID token from issuerURL for clientID with claims, which
+// override the default iss, aud, iat and exp claims.
+func (o *oidcServer) token(issuerURL, clientID string, claims map[string]any) (string, error) {
+	now := time.Now()
+	all := map[string]any{
+		"iss": issuerURL,
+		"aud": clientID,
+		"iat": now.Unix(),
+		"exp": now.Add(DefaultTokenLifetime).Unix(),
+	}
+	maps.Copy(all, claims)
+	return jwt.Signed(o.signer).Claims(all).Serialize()
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// validIssuerName reports whether name can be a path segment of an issuer
+// URL.
+func validIssuerName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, "/?#%")
+}
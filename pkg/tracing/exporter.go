@@ -0,0 +1,97 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// span is the JSON representation of a span written by JSONExporter.
+type span struct {
+	Name       string            `json:"name"`
+	TraceID    string            `json:"traceID"`
+	SpanID     string            `json:"spanID"`
+	ParentID   string            `json:"parentID,omitempty"`
+	Kind       string            `json:"kind"`
+	Start      time.Time         `json:"start"`
+	Duration   string            `json:"duration"`
+	Status     string            `json:"status"`
+	Error      string            `json:"error,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// JSONExporter writes spans as JSON Lines, for development and for
+// collecting traces from logs.
+type JSONExporter struct {
+	mu      sync.Mutex
+	enc     *json.Encoder
+	stopped bool
+}
+
+var _ sdktrace.SpanExporter = (*JSONExporter)(nil)
+
+// NewJSONExporter returns a JSONExporter writing to w.
+func NewJSONExporter(w io.Writer) *JSONExporter {
+	return &JSONExporter{enc: json.NewEncoder(w)}
+}
+
+// ExportSpans writes spans, one per line.
+func (e *JSONExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.stopped {
+		return errors.New("trace exporter is shut down")
+	}
+	for _, s := range spans {
+		out := span{
+			Name:     s.Name(),
+			TraceID:  s.SpanContext().TraceID().String(),
+			SpanID:   s.SpanContext().SpanID().String(),
+			Kind:     s.SpanKind().String(),
+			Start:    s.StartTime().UTC(),
+			Duration: s.EndTime().Sub(s.StartTime()).String(),
+			Status:   s.Status().Code.String(),
+			Error:    s.Status().Description,
+		}
+		if s.Parent().IsValid() {
+			out.ParentID = s.Parent().SpanID().String()
+		}
+		if attrs := s.Attributes(); len(attrs) > 0 {
+			out.Attributes = make(map[string]string, len(attrs))
+			for _, a := range attrs {
+				out.Attributes[string(a.Key)] = a.Value.Emit()
+			}
+		}
+		if err := e.enc.Encode(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown stops the exporter. Spans exported afterwards are not written.
+func (e *JSONExporter) Shutdown(context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stopped = true
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing records OpenTelemetry spans for the stages of issuing a
+// certificate, such as verifying the identity token, signing with the CA
+// and submitting to CT logs.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/release-utils/version"
+)
+
+// instrumentationName identifies the spans recorded by Fulcio.
+const instrumentationName = "github.com/sigstore/fulcio"
+
+const (
+	// ExporterNone disables tracing.
+	ExporterNone = "none"
+	// ExporterOTLP exports spans to an OTLP collector over gRPC.
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes spans to stdout as JSON Lines.
+	ExporterStdout = "stdout"
+)
+
+// Config configures the export of spans.
+type Config struct {
+	// Exporter is ExporterNone, ExporterOTLP or ExporterStdout.
+	Exporter string
+	// OTLPEndpoint is the host and port of the OTLP collector. If empty,
+	// the OTEL_EXPORTER_OTLP_ENDPOINT environment variable or the default
+	// localhost:4317 is used.
+	OTLPEndpoint string
+	// OTLPInsecure disables TLS to the OTLP collector.
+	OTLPInsecure bool
+	// SampleRatio is the fraction of traces started by Fulcio that are
+	// recorded. Traces started by a caller are recorded if the caller
+	// sampled them.
+	SampleRatio float64
+}
+
+// Setup installs a global tracer provider exporting spans as configured in
+// cfg, and propagates W3C trace context. The returned function flushes
+// spans and shuts the exporter down.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		var opts []otlptracegrpc.Option
+		if cfg.OTLPEndpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint))
+		}
+		if cfg.OTLPInsecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		var err error
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("creating OTLP trace exporter: %w", err)
+		}
+	case ExporterStdout:
+		exporter = NewJSONExporter(os.Stdout)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q, must be %s, %s or %s", cfg.Exporter, ExporterNone, ExporterOTLP, ExporterStdout)
+	}
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return nil, fmt.Errorf("trace sample ratio must be between 0 and 1, got %v", cfg.SampleRatio)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", "fulcio"),
+			attribute.String("service.version", version.GetVersionInfo().GitVersion),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Start starts a span named name as a child of the span in ctx, if any.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartHTTP starts a server span named name for r, continuing the trace
+// propagated in its headers, if any. The returned request carries the span.
+func StartHTTP(r *http.Request, name string) (*http.Request, trace.Span) {
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer))
+	return r.WithContext(ctx), span
+}
+
+// End ends span, marking it as failed if err is not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
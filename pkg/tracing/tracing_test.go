@@ -0,0 +1,87 @@
+// Copyright 2026 The Sigstore Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestJSONExporter(t *testing.T) {
+	var buf bytes.Buffer
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(NewJSONExporter(&buf)))
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	otel.SetTracerProvider(tp)
+
+	ctx, parent := Start(context.Background(), "parent")
+	_, child := Start(ctx, "child", attribute.String("log", "ctfe"))
+	End(child, errors.New("unavailable"))
+	End(parent, nil)
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+
+	dec := json.NewDecoder(&buf)
+	var spans []span
+	for dec.More() {
+		var s span
+		if err := dec.Decode(&s); err != nil {
+			t.Fatalf("Decode() = %v", err)
+		}
+		spans = append(spans, s)
+	}
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	c, p := spans[0], spans[1]
+	switch {
+	case c.Name != "child" || p.Name != "parent":
+		t.Errorf("unexpected span names %q and %q", c.Name, p.Name)
+	case c.TraceID != p.TraceID || c.ParentID != p.SpanID || p.ParentID != "":
+		t.Errorf("expected child of parent, got %+v and %+v", c, p)
+	case c.Status != "Error" || c.Error != "unavailable" || p.Status != "Unset":
+		t.Errorf("unexpected status %+v and %+v", c, p)
+	case c.Attributes["log"] != "ctfe":
+		t.Errorf("unexpected attributes %v", c.Attributes)
+	}
+}
+
+func TestSetup(t *testing.T) {
+	for name, cfg := range map[string]Config{
+		"unknown exporter": {Exporter: "zipkin"},
+		"bad ratio":        {Exporter: ExporterStdout, SampleRatio: 2},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Setup(context.Background(), cfg); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterNone})
+	if err != nil {
+		t.Fatalf("Setup() = %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() = %v", err)
+	}
+}